@@ -0,0 +1,324 @@
+// Package association keeps track of the PFCP associations of a CP function with its UP functions.
+package association
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/dot-5g/pfcp/client"
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+	"github.com/dot-5g/pfcp/server"
+)
+
+// State is the state of the association with a peer.
+type State int
+
+const (
+	// Idle means there is no association with the peer.
+	Idle State = iota
+	// SettingUp means a PFCP Association Setup Request was sent to the peer and is waiting for its response.
+	SettingUp
+	// Associated means the association is established and session messages are accepted.
+	Associated
+	// Releasing means the association is being released and new session messages are rejected.
+	Releasing
+)
+
+func (state State) String() string {
+	switch state {
+	case Idle:
+		return "Idle"
+	case SettingUp:
+		return "SettingUp"
+	case Associated:
+		return "Associated"
+	case Releasing:
+		return "Releasing"
+	default:
+		return fmt.Sprintf("State(%d)", int(state))
+	}
+}
+
+// Peer is the association with a UP function.
+type Peer struct {
+	Address            string
+	State              State
+	NodeID             ie.NodeID
+	RecoveryTimeStamp  ie.RecoveryTimeStamp
+	UPFunctionFeatures *ie.UPFunctionFeatures
+}
+
+// Event reports the change of state of the association with a peer.
+type Event struct {
+	Peer     Peer
+	Previous State
+}
+
+// ErrNotAssociated is returned when releasing the association with a peer that is not associated.
+var ErrNotAssociated = errors.New("no established PFCP association")
+
+// RejectedError is returned when the peer answers a request with a Cause other than Request accepted.
+type RejectedError struct {
+	Cause ie.CauseValue
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("request rejected with cause %d", e.Cause)
+}
+
+// Manager drives the associations of a server with its peers. It answers the PFCP Association
+// Setup, Update and Release Requests received by the server and rejects the session requests
+// of peers that are not associated with the Cause No established PFCP Association.
+type Manager struct {
+	server  *server.Server
+	mu      sync.Mutex
+	peers   map[string]*Peer
+	onEvent func(Event)
+}
+
+type Option func(*Manager)
+
+// WithEventHandler registers a function called every time the state of an association changes.
+// It is called without locks held, in the order the changes happen for a given peer.
+func WithEventHandler(handler func(Event)) Option {
+	return func(manager *Manager) {
+		manager.onEvent = handler
+	}
+}
+
+// New creates a manager for the associations of pfcpServer and registers its handlers and interceptor.
+func New(pfcpServer *server.Server, opts ...Option) *Manager {
+	manager := &Manager{
+		server: pfcpServer,
+		peers:  make(map[string]*Peer),
+	}
+	for _, opt := range opts {
+		opt(manager)
+	}
+	server.HandleRequest(pfcpServer, manager.handleSetupRequest)
+	server.HandleRequest(pfcpServer, manager.handleUpdateRequest)
+	server.HandleRequest(pfcpServer, manager.handleReleaseRequest)
+	pfcpServer.Use(manager.intercept)
+	return manager
+}
+
+// Peer returns the association with the peer at address.
+func (manager *Manager) Peer(address string) (Peer, bool) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	peer, exists := manager.peers[address]
+	if !exists {
+		return Peer{}, false
+	}
+	return *peer, true
+}
+
+// Peers returns the associations in any state other than Idle.
+func (manager *Manager) Peers() []Peer {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	peers := make([]Peer, 0, len(manager.peers))
+	for _, peer := range manager.peers {
+		peers = append(peers, *peer)
+	}
+	return peers
+}
+
+// IsAssociated reports whether the association with the peer at address is established.
+func (manager *Manager) IsAssociated(address string) bool {
+	peer, exists := manager.Peer(address)
+	return exists && peer.State == Associated
+}
+
+// Setup sends a PFCP Association Setup Request to the peer at address from the server socket
+// and waits for its response.
+func (manager *Manager) Setup(address string) (Peer, error) {
+	pfcpClient, key, err := manager.clientFor(address)
+	if err != nil {
+		return Peer{}, err
+	}
+
+	manager.mu.Lock()
+	if peer, exists := manager.peers[key]; exists && peer.State != Associated {
+		manager.mu.Unlock()
+		return Peer{}, fmt.Errorf("association with %s is %s", key, peer.State)
+	}
+	event := manager.transition(key, SettingUp)
+	manager.mu.Unlock()
+	manager.emit(event)
+
+	response, err := pfcpClient.SendPFCPAssociationSetupRequestAndWait(messages.PFCPAssociationSetupRequest{
+		NodeID:            manager.server.NodeID(),
+		RecoveryTimeStamp: manager.server.RecoveryTimeStamp(),
+	})
+	if err == nil && response.Cause.Value != ie.RequestAccepted {
+		err = &RejectedError{Cause: response.Cause.Value}
+	}
+
+	manager.mu.Lock()
+	if err != nil {
+		event = manager.transition(key, Idle)
+		manager.mu.Unlock()
+		manager.emit(event)
+		return Peer{}, fmt.Errorf("failed to set up association with %s: %w", key, err)
+	}
+	peer := manager.peers[key]
+	peer.NodeID = response.NodeID
+	peer.RecoveryTimeStamp = response.RecoveryTimeStamp
+	peer.UPFunctionFeatures = response.UPFunctionFeatures
+	event = manager.transition(key, Associated)
+	manager.mu.Unlock()
+	manager.emit(event)
+	return event.Peer, nil
+}
+
+// Release sends a PFCP Association Release Request to the associated peer at address
+// and waits for its response. The association is removed once the peer accepts the release.
+func (manager *Manager) Release(address string) error {
+	pfcpClient, key, err := manager.clientFor(address)
+	if err != nil {
+		return err
+	}
+
+	manager.mu.Lock()
+	if peer, exists := manager.peers[key]; !exists || peer.State != Associated {
+		manager.mu.Unlock()
+		return fmt.Errorf("%s: %w", key, ErrNotAssociated)
+	}
+	event := manager.transition(key, Releasing)
+	manager.mu.Unlock()
+	manager.emit(event)
+
+	response, err := pfcpClient.SendPFCPAssociationReleaseRequestAndWait(messages.PFCPAssociationReleaseRequest{
+		NodeID: manager.server.NodeID(),
+	})
+	if err == nil && response.Cause.Value != ie.RequestAccepted {
+		err = &RejectedError{Cause: response.Cause.Value}
+	}
+
+	manager.mu.Lock()
+	if err != nil {
+		event = manager.transition(key, Associated)
+		manager.mu.Unlock()
+		manager.emit(event)
+		return fmt.Errorf("failed to release association with %s: %w", key, err)
+	}
+	event = manager.transition(key, Idle)
+	manager.mu.Unlock()
+	manager.emit(event)
+	return nil
+}
+
+// clientFor returns the client of the server for the peer at address and the key of the peer.
+func (manager *Manager) clientFor(address string) (*client.PFCP, string, error) {
+	udpAddress, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, "", err
+	}
+	if pfcpClient := manager.server.GetClientForAddress(udpAddress); pfcpClient != nil {
+		return pfcpClient, udpAddress.String(), nil
+	}
+	pfcpClient, err := manager.server.NewClient(udpAddress.String())
+	if err != nil {
+		return nil, "", err
+	}
+	return pfcpClient, udpAddress.String(), nil
+}
+
+// transition moves the peer at key to state and returns the corresponding event.
+// The peer is removed when it becomes Idle. It must be called with mu held.
+func (manager *Manager) transition(key string, state State) Event {
+	peer, exists := manager.peers[key]
+	if !exists {
+		peer = &Peer{Address: key}
+		manager.peers[key] = peer
+	}
+	previous := peer.State
+	peer.State = state
+	if state == Idle {
+		delete(manager.peers, key)
+	}
+	return Event{Peer: *peer, Previous: previous}
+}
+
+func (manager *Manager) emit(events ...Event) {
+	if manager.onEvent == nil {
+		return
+	}
+	for _, event := range events {
+		if event.Peer.State != event.Previous {
+			manager.onEvent(event)
+		}
+	}
+}
+
+func (manager *Manager) handleSetupRequest(ctx context.Context, req messages.PFCPAssociationSetupRequest) (messages.PFCPMessage, error) {
+	incoming, _ := server.IncomingFromContext(ctx)
+	key := incoming.Address.String()
+
+	manager.mu.Lock()
+	event := manager.transition(key, Associated)
+	peer := manager.peers[key]
+	peer.NodeID = req.NodeID
+	peer.RecoveryTimeStamp = req.RecoveryTimeStamp
+	peer.UPFunctionFeatures = req.UPFunctionFeatures
+	event.Peer = *peer
+	manager.mu.Unlock()
+	manager.emit(event)
+
+	cause, err := ie.NewCause(ie.RequestAccepted)
+	if err != nil {
+		return nil, err
+	}
+	return messages.PFCPAssociationSetupResponse{
+		NodeID:            manager.server.NodeID(),
+		Cause:             cause,
+		RecoveryTimeStamp: manager.server.RecoveryTimeStamp(),
+	}, nil
+}
+
+func (manager *Manager) handleUpdateRequest(ctx context.Context, req messages.PFCPAssociationUpdateRequest) (messages.PFCPMessage, error) {
+	incoming, _ := server.IncomingFromContext(ctx)
+	if !manager.IsAssociated(incoming.Address.String()) {
+		return nil, server.Reject(ie.NoEstablishedPFCPAssociation)
+	}
+	cause, err := ie.NewCause(ie.RequestAccepted)
+	if err != nil {
+		return nil, err
+	}
+	return messages.PFCPAssociationUpdateResponse{NodeID: manager.server.NodeID(), Cause: cause}, nil
+}
+
+func (manager *Manager) handleReleaseRequest(ctx context.Context, req messages.PFCPAssociationReleaseRequest) (messages.PFCPMessage, error) {
+	incoming, _ := server.IncomingFromContext(ctx)
+	key := incoming.Address.String()
+
+	manager.mu.Lock()
+	if peer, exists := manager.peers[key]; !exists || peer.State != Associated {
+		manager.mu.Unlock()
+		return nil, server.Reject(ie.NoEstablishedPFCPAssociation)
+	}
+	releasing := manager.transition(key, Releasing)
+	released := manager.transition(key, Idle)
+	manager.mu.Unlock()
+	manager.emit(releasing, released)
+
+	cause, err := ie.NewCause(ie.RequestAccepted)
+	if err != nil {
+		return nil, err
+	}
+	return messages.PFCPAssociationReleaseResponse{NodeID: manager.server.NodeID(), Cause: cause}, nil
+}
+
+// intercept rejects the session requests of peers that are not associated.
+func (manager *Manager) intercept(ctx context.Context, message messages.PFCPMessage, next server.Next) (messages.PFCPMessage, error) {
+	incoming, ok := server.IncomingFromContext(ctx)
+	if ok && incoming.Header.S && incoming.Header.MessageType.IsRequest() && !manager.IsAssociated(incoming.Address.String()) {
+		return nil, server.Reject(ie.NoEstablishedPFCPAssociation)
+	}
+	return next(ctx, message)
+}
@@ -0,0 +1,246 @@
+package association_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dot-5g/pfcp/association"
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/internal/pfcptest"
+	"github.com/dot-5g/pfcp/messages"
+	"github.com/dot-5g/pfcp/server"
+)
+
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []association.Event
+}
+
+func (r *eventRecorder) record(event association.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) states() []association.State {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	states := make([]association.State, 0, len(r.events))
+	for _, event := range r.events {
+		states = append(states, event.Peer.State)
+	}
+	return states
+}
+
+// startUserPlane answers the PFCP Association Setup and Release Requests received on a UDP
+// socket, reading their headers only, and returns the address and Recovery Time Stamp of the socket.
+func startUserPlane(t *testing.T, features ie.UPFunctionFeatures) (string, ie.RecoveryTimeStamp) {
+	conn := pfcptest.NewPeer(t)
+	nodeID, err := ie.NewNodeID("upf.example.com")
+	if err != nil {
+		t.Fatalf("Error creating NodeID: %v", err)
+	}
+	accepted, err := ie.NewCause(ie.RequestAccepted)
+	if err != nil {
+		t.Fatalf("Error creating Cause: %v", err)
+	}
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating RecoveryTimeStamp: %v", err)
+	}
+	go func() {
+		buffer := make([]byte, 1024)
+		for {
+			length, address, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			header, err := messages.DeserializeHeader(buffer[:length])
+			if err != nil {
+				continue
+			}
+			var response messages.PFCPMessage
+			switch header.MessageType {
+			case messages.PFCPAssociationSetupRequestMessageType:
+				response = messages.PFCPAssociationSetupResponse{
+					NodeID:             nodeID,
+					Cause:              accepted,
+					RecoveryTimeStamp:  recoveryTimeStamp,
+					UPFunctionFeatures: &features,
+				}
+			case messages.PFCPAssociationReleaseRequestMessageType:
+				response = messages.PFCPAssociationReleaseResponse{NodeID: nodeID, Cause: accepted}
+			default:
+				continue
+			}
+			conn.WriteTo(messages.Serialize(response, messages.NewNodeHeader(response.GetMessageType(), header.SequenceNumber)), address)
+		}
+	}()
+	return conn.LocalAddr().String(), recoveryTimeStamp
+}
+
+func TestGivenPeerAcceptingSetupWhenSetupThenAssociatedWithPeerInformation(t *testing.T) {
+	features, err := ie.NewUPFunctionFeatures([]ie.UPFeature{ie.FTUP})
+	if err != nil {
+		t.Fatalf("Error creating UPFunctionFeatures: %v", err)
+	}
+	address, recoveryTimeStamp := startUserPlane(t, features)
+	var recorder eventRecorder
+	manager := association.New(pfcptest.StartServer(t), association.WithEventHandler(recorder.record))
+
+	peer, err := manager.Setup(address)
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	if peer.State != association.Associated {
+		t.Errorf("Expected state Associated, got %s", peer.State)
+	}
+	if peer.RecoveryTimeStamp != recoveryTimeStamp {
+		t.Errorf("Expected Recovery Time Stamp %v, got %v", recoveryTimeStamp, peer.RecoveryTimeStamp)
+	}
+	if peer.UPFunctionFeatures == nil || peer.UPFunctionFeatures.SupportedFeatures[0] != features.SupportedFeatures[0] {
+		t.Errorf("Expected UP Function Features %v, got %v", features, peer.UPFunctionFeatures)
+	}
+	if !manager.IsAssociated(address) {
+		t.Errorf("Expected peer to be associated")
+	}
+	states := recorder.states()
+	if len(states) != 2 || states[0] != association.SettingUp || states[1] != association.Associated {
+		t.Errorf("Expected events SettingUp then Associated, got %v", states)
+	}
+}
+
+func TestGivenAssociatedPeerWhenReleaseThenAssociationRemoved(t *testing.T) {
+	features, err := ie.NewUPFunctionFeatures(nil)
+	if err != nil {
+		t.Fatalf("Error creating UPFunctionFeatures: %v", err)
+	}
+	address, _ := startUserPlane(t, features)
+	var recorder eventRecorder
+	manager := association.New(pfcptest.StartServer(t), association.WithEventHandler(recorder.record))
+	_, err = manager.Setup(address)
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	err = manager.Release(address)
+	if err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	if _, exists := manager.Peer(address); exists {
+		t.Errorf("Expected the association to be removed")
+	}
+	states := recorder.states()
+	if len(states) != 4 || states[2] != association.Releasing || states[3] != association.Idle {
+		t.Errorf("Expected events Releasing then Idle after the setup, got %v", states)
+	}
+}
+
+func TestGivenUnassociatedPeerWhenReleaseThenErrNotAssociated(t *testing.T) {
+	manager := association.New(pfcptest.StartServer(t))
+
+	err := manager.Release("127.0.0.1:9")
+
+	if err == nil {
+		t.Fatalf("Expected an error")
+	}
+}
+
+func TestGivenUnassociatedPeerWhenSessionRequestThenRejectedWithNoEstablishedPFCPAssociation(t *testing.T) {
+	cpServer := pfcptest.StartServer(t)
+	association.New(cpServer)
+	peerConn := pfcptest.NewPeer(t)
+
+	header, response := pfcptest.SendRequest(t, peerConn, cpServer.Conn().LocalAddr(),
+		messages.PFCPSessionDeletionRequest{}, messages.NewSessionHeader(messages.PFCPSessionDeletionRequestMessageType, 1, 1))
+
+	if header.MessageType != messages.PFCPSessionDeletionResponseMessageType {
+		t.Fatalf("Expected PFCP Session Deletion Response, got message type %d", header.MessageType)
+	}
+	if cause := response.(messages.PFCPSessionDeletionResponse).Cause.Value; cause != ie.NoEstablishedPFCPAssociation {
+		t.Errorf("Expected cause No established PFCP Association, got %d", cause)
+	}
+}
+
+func TestGivenSetupRequestFromPeerWhenSessionRequestThenHandled(t *testing.T) {
+	cpServer := pfcptest.StartServer(t)
+	var recorder eventRecorder
+	manager := association.New(cpServer, association.WithEventHandler(recorder.record))
+	accepted, err := ie.NewCause(ie.RequestAccepted)
+	if err != nil {
+		t.Fatalf("Error creating Cause: %v", err)
+	}
+	server.HandleRequest(cpServer, func(ctx context.Context, req messages.PFCPSessionDeletionRequest) (messages.PFCPMessage, error) {
+		return messages.PFCPSessionDeletionResponse{Cause: accepted}, nil
+	})
+	peerConn := pfcptest.NewPeer(t)
+	nodeID, err := ie.NewNodeID("upf.example.com")
+	if err != nil {
+		t.Fatalf("Error creating NodeID: %v", err)
+	}
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating RecoveryTimeStamp: %v", err)
+	}
+	features, err := ie.NewUPFunctionFeatures([]ie.UPFeature{ie.BUCP})
+	if err != nil {
+		t.Fatalf("Error creating UPFunctionFeatures: %v", err)
+	}
+
+	_, response := pfcptest.SendRequest(t, peerConn, cpServer.Conn().LocalAddr(),
+		messages.PFCPAssociationSetupRequest{NodeID: nodeID, RecoveryTimeStamp: recoveryTimeStamp, UPFunctionFeatures: &features},
+		messages.NewNodeHeader(messages.PFCPAssociationSetupRequestMessageType, 1))
+	if cause := response.(messages.PFCPAssociationSetupResponse).Cause.Value; cause != ie.RequestAccepted {
+		t.Fatalf("Expected setup to be accepted, got cause %d", cause)
+	}
+	_, response = pfcptest.SendRequest(t, peerConn, cpServer.Conn().LocalAddr(),
+		messages.PFCPSessionDeletionRequest{}, messages.NewSessionHeader(messages.PFCPSessionDeletionRequestMessageType, 1, 2))
+
+	if cause := response.(messages.PFCPSessionDeletionResponse).Cause.Value; cause != ie.RequestAccepted {
+		t.Errorf("Expected session request to be handled, got cause %d", cause)
+	}
+	peer, exists := manager.Peer(peerConn.LocalAddr().String())
+	if !exists || string(peer.NodeID.Value) != "upf.example.com" || peer.RecoveryTimeStamp != recoveryTimeStamp {
+		t.Errorf("Unexpected peer %+v", peer)
+	}
+	if states := recorder.states(); len(states) != 1 || states[0] != association.Associated {
+		t.Errorf("Expected event Associated, got %v", states)
+	}
+}
+
+func TestGivenReleaseRequestFromAssociatedPeerWhenHandledThenAssociationRemoved(t *testing.T) {
+	cpServer := pfcptest.StartServer(t)
+	var recorder eventRecorder
+	manager := association.New(cpServer, association.WithEventHandler(recorder.record))
+	peerConn := pfcptest.NewPeer(t)
+	nodeID, err := ie.NewNodeID("127.0.0.1")
+	if err != nil {
+		t.Fatalf("Error creating NodeID: %v", err)
+	}
+
+	_, response := pfcptest.SendRequest(t, peerConn, cpServer.Conn().LocalAddr(),
+		messages.PFCPAssociationReleaseRequest{NodeID: nodeID}, messages.NewNodeHeader(messages.PFCPAssociationReleaseRequestMessageType, 1))
+	if cause := response.(messages.PFCPAssociationReleaseResponse).Cause.Value; cause != ie.NoEstablishedPFCPAssociation {
+		t.Errorf("Expected release of a missing association to be rejected, got cause %d", cause)
+	}
+
+	_, _ = pfcptest.SendRequest(t, peerConn, cpServer.Conn().LocalAddr(),
+		messages.PFCPAssociationSetupRequest{NodeID: nodeID}, messages.NewNodeHeader(messages.PFCPAssociationSetupRequestMessageType, 2))
+	_, response = pfcptest.SendRequest(t, peerConn, cpServer.Conn().LocalAddr(),
+		messages.PFCPAssociationReleaseRequest{NodeID: nodeID}, messages.NewNodeHeader(messages.PFCPAssociationReleaseRequestMessageType, 3))
+
+	if cause := response.(messages.PFCPAssociationReleaseResponse).Cause.Value; cause != ie.RequestAccepted {
+		t.Errorf("Expected release to be accepted, got cause %d", cause)
+	}
+	if _, exists := manager.Peer(peerConn.LocalAddr().String()); exists {
+		t.Errorf("Expected the association to be removed")
+	}
+	states := recorder.states()
+	if len(states) != 3 || states[1] != association.Releasing || states[2] != association.Idle {
+		t.Errorf("Expected events Associated, Releasing then Idle, got %v", states)
+	}
+}
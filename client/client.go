@@ -1,12 +1,31 @@
 package client
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/dot-5g/pfcp/messages"
+	"github.com/dot-5g/pfcp/metrics"
 	"github.com/dot-5g/pfcp/network"
 )
 
+const (
+	// DefaultT1 is the time waited for a response before a request is retransmitted.
+	DefaultT1 = 3 * time.Second
+	// DefaultN1 is the number of times a request is retransmitted before giving up.
+	DefaultN1 = 3
+)
+
+// ErrRequestTimeout is returned when no response is received after all retransmissions of a request.
+var ErrRequestTimeout = errors.New("no response received")
+
 type PfcpClienter interface {
 	SendHeartbeatRequest(msg messages.HeartbeatRequest, sequenceNumber uint32) error
 	SendHeartbeatResponse(msg messages.HeartbeatResponse, sequenceNumber uint32) error
@@ -26,20 +45,319 @@ type PfcpClienter interface {
 	SendPFCPSessionReportResponse(msg messages.PFCPSessionReportResponse, seid uint64, sequenceNumber uint32) error
 }
 
+// PfcpRequester extends PfcpClienter with the messages added since and with the requests that
+// allocate their sequence number and wait for their response.
+type PfcpRequester interface {
+	PfcpClienter
+	SendPFCPSessionModificationRequest(msg messages.PFCPSessionModificationRequest, seid uint64, sequenceNumber uint32) error
+	SendPFCPSessionModificationResponse(msg messages.PFCPSessionModificationResponse, seid uint64, sequenceNumber uint32) error
+	Send(msg messages.PFCPMessage, header messages.Header) error
+	SendHeartbeatRequestAndWait(msg messages.HeartbeatRequest) (messages.HeartbeatResponse, error)
+	SendHeartbeatRequestContext(ctx context.Context, msg messages.HeartbeatRequest) (messages.HeartbeatResponse, error)
+	SendPFCPAssociationSetupRequestAndWait(msg messages.PFCPAssociationSetupRequest) (messages.PFCPAssociationSetupResponse, error)
+	SendPFCPAssociationUpdateRequestAndWait(msg messages.PFCPAssociationUpdateRequest) (messages.PFCPAssociationUpdateResponse, error)
+	SendPFCPAssociationReleaseRequestAndWait(msg messages.PFCPAssociationReleaseRequest) (messages.PFCPAssociationReleaseResponse, error)
+	SendPFCPNodeReportRequestAndWait(msg messages.PFCPNodeReportRequest) (messages.PFCPNodeReportResponse, error)
+	SendPFCPSessionEstablishmentRequestAndWait(msg messages.PFCPSessionEstablishmentRequest, seid uint64) (messages.PFCPSessionEstablishmentResponse, error)
+	SendPFCPSessionModificationRequestAndWait(msg messages.PFCPSessionModificationRequest, seid uint64) (messages.PFCPSessionModificationResponse, error)
+	SendPFCPSessionDeletionRequestAndWait(msg messages.PFCPSessionDeletionRequest, seid uint64) (messages.PFCPSessionDeletionResponse, error)
+	SendPFCPSessionReportRequestAndWait(msg messages.PFCPSessionReportRequest, seid uint64) (messages.PFCPSessionReportResponse, error)
+}
+
 var _ PfcpClienter = (*PFCP)(nil)
+var _ PfcpRequester = (*PFCP)(nil)
 
 type PFCP struct {
 	ServerAddress string
 	Udp           network.UDPSender
+
+	peer           *net.UDPAddr
+	localAddress   string
+	conn           net.PacketConn
+	t1             time.Duration
+	n1             int
+	sequenceNumber atomic.Uint32
+	pendingMu      sync.Mutex
+	pending        map[uint32]pendingRequest
+	idle           chan struct{}
+	interceptors   []Interceptor
+	logger         *slog.Logger
+	metrics        metrics.Metrics
+}
+
+type response struct {
+	header  messages.Header
+	payload []byte
+}
+
+// pendingRequest is a request waiting for a response of type responseType.
+type pendingRequest struct {
+	responseType messages.MessageType
+	responses    chan response
+}
+
+type Option func(*PFCP)
+
+// WithRetransmission sets the T1 timer and the N1 retry count used by the request methods.
+func WithRetransmission(t1 time.Duration, n1 int) Option {
+	return func(pfcp *PFCP) {
+		pfcp.t1 = t1
+		pfcp.n1 = n1
+	}
+}
+
+// WithLocalAddress binds the client socket to localAddress, for example to send from port 8805.
+func WithLocalAddress(localAddress string) Option {
+	return func(pfcp *PFCP) {
+		pfcp.localAddress = localAddress
+	}
+}
+
+// WithLogger sets the logger of the client, slog.Default() by default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(pfcp *PFCP) {
+		pfcp.logger = logger
+	}
+}
+
+// WithMetrics records the traffic of the client in m.
+func WithMetrics(m metrics.Metrics) Option {
+	return func(pfcp *PFCP) {
+		pfcp.metrics = m
+	}
+}
+
+// WithInterceptors adds interceptors around the messages sent by the client.
+// The first interceptor is the outermost one.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(pfcp *PFCP) {
+		pfcp.interceptors = append(pfcp.interceptors, interceptors...)
+	}
+}
+
+// WithConn makes the client send over conn without reading from it. It is meant for server.Server,
+// which passes the datagrams it reads to the clients it created: to send requests from the socket
+// of a server, use server.NewClient rather than this option, whose clients never get a response.
+func WithConn(conn net.PacketConn) Option {
+	return func(pfcp *PFCP) {
+		pfcp.conn = conn
+	}
 }
 
-func New(ServerAddress string) *PFCP {
-	udpClient, err := network.NewUDP(ServerAddress)
+func New(ServerAddress string, opts ...Option) *PFCP {
+	pfcp := &PFCP{
+		ServerAddress: ServerAddress,
+		t1:            DefaultT1,
+		n1:            DefaultN1,
+		pending:       make(map[uint32]pendingRequest),
+		logger:        slog.Default(),
+		metrics:       metrics.Noop{},
+	}
+	for _, opt := range opts {
+		opt(pfcp)
+	}
+	pfcp.logger = pfcp.logger.With(slog.String("peer", ServerAddress))
+
+	peer, err := net.ResolveUDPAddr("udp", ServerAddress)
+	if err != nil {
+		pfcp.logger.Error("Failed to initialize PFCP client", slog.Any("error", err))
+		return nil
+	}
+	pfcp.peer = peer
+
+	var udpClient *network.UDP
+	switch {
+	case pfcp.conn != nil:
+		udpClient, err = network.NewUDPWithConn(ServerAddress, pfcp.conn)
+	case pfcp.localAddress != "":
+		udpClient, err = network.NewUDPWithLocalAddress(ServerAddress, pfcp.localAddress)
+	default:
+		udpClient, err = network.NewUDP(ServerAddress)
+	}
 	if err != nil {
-		log.Printf("Failed to initialize PFCP client: %v\n", err)
+		pfcp.logger.Error("Failed to initialize PFCP client", slog.Any("error", err))
 		return nil
 	}
-	return &PFCP{ServerAddress: ServerAddress, Udp: udpClient}
+	udpClient.SetHandler(func(address net.Addr, payload []byte) {
+		pfcp.HandleMessage(address, payload)
+	})
+	pfcp.Udp = udpClient
+	return pfcp
+}
+
+// Close releases the socket used by the client.
+func (pfcp *PFCP) Close() error {
+	if closer, ok := pfcp.Udp.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Wait blocks until no request is waiting for a response, including its retransmissions,
+// or until ctx is done.
+func (pfcp *PFCP) Wait(ctx context.Context) error {
+	for {
+		pfcp.pendingMu.Lock()
+		if len(pfcp.pending) == 0 {
+			pfcp.pendingMu.Unlock()
+			return nil
+		}
+		if pfcp.idle == nil {
+			pfcp.idle = make(chan struct{})
+		}
+		idle := pfcp.idle
+		pfcp.pendingMu.Unlock()
+
+		select {
+		case <-idle:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// HandleMessage delivers a datagram received from the peer to the request waiting for it.
+// It returns true if the datagram was the response to a pending request. Since the peer numbers
+// its own requests independently, a datagram is only taken as a response if it has the sequence
+// number of a pending request and the type of its response.
+func (pfcp *PFCP) HandleMessage(address net.Addr, payload []byte) bool {
+	if !pfcp.isPeer(address) {
+		return false
+	}
+
+	header, err := messages.DeserializeHeader(payload)
+	if err != nil {
+		return false
+	}
+
+	payloadOffset := 8
+	if header.S {
+		payloadOffset = 16
+	}
+	if len(payload) < payloadOffset {
+		return false
+	}
+
+	pfcp.pendingMu.Lock()
+	request, exists := pfcp.pending[header.SequenceNumber]
+	pfcp.pendingMu.Unlock()
+	if !exists || header.MessageType != request.responseType {
+		return false
+	}
+	pfcp.metrics.MessageReceived(header.MessageType)
+
+	select {
+	case request.responses <- response{header: header, payload: payload[payloadOffset:]}:
+	default:
+	}
+	return true
+}
+
+func (pfcp *PFCP) isPeer(address net.Addr) bool {
+	udpAddress, ok := address.(*net.UDPAddr)
+	if !ok || pfcp.peer == nil {
+		return false
+	}
+	return udpAddress.IP.Equal(pfcp.peer.IP) && udpAddress.Port == pfcp.peer.Port
+}
+
+// nextSequenceNumber allocates a non-zero 24 bit sequence number for a new request.
+func (pfcp *PFCP) nextSequenceNumber() uint32 {
+	for {
+		sequenceNumber := pfcp.sequenceNumber.Add(1) & 0xFFFFFF
+		if sequenceNumber != 0 {
+			return sequenceNumber
+		}
+	}
+}
+
+// exchange sends a request and waits for the response with the same sequence number,
+// retransmitting it every T1 up to N1 times or until ctx is done.
+func (pfcp *PFCP) exchange(ctx context.Context, message messages.PFCPMessage, header messages.Header) (response, error) {
+	responseType, _ := header.MessageType.ResponseType()
+	responseCh := make(chan response, 1)
+
+	pfcp.pendingMu.Lock()
+	pfcp.pending[header.SequenceNumber] = pendingRequest{responseType: responseType, responses: responseCh}
+	pfcp.pendingMu.Unlock()
+
+	defer func() {
+		pfcp.pendingMu.Lock()
+		delete(pfcp.pending, header.SequenceNumber)
+		if len(pfcp.pending) == 0 && pfcp.idle != nil {
+			close(pfcp.idle)
+			pfcp.idle = nil
+		}
+		pfcp.pendingMu.Unlock()
+	}()
+
+	start := time.Now()
+	for attempt := 0; attempt <= pfcp.n1; attempt++ {
+		if attempt > 0 {
+			pfcp.metrics.Retransmission(header.MessageType)
+		}
+		if err := pfcp.transmit(message, header); err != nil {
+			return response{}, err
+		}
+
+		timer := time.NewTimer(pfcp.t1)
+	wait:
+		for {
+			select {
+			case resp := <-responseCh:
+				if resp.header.MessageType != responseType {
+					continue
+				}
+				timer.Stop()
+				pfcp.metrics.ResponseLatency(header.MessageType, time.Since(start))
+				return resp, nil
+			case <-timer.C:
+				break wait
+			case <-ctx.Done():
+				timer.Stop()
+				return response{}, fmt.Errorf("%s to %s: %w", message.GetMessageTypeString(), pfcp.ServerAddress, ctx.Err())
+			}
+		}
+	}
+
+	pfcp.metrics.Timeout(header.MessageType)
+	return response{}, fmt.Errorf("%s to %s: %w", message.GetMessageTypeString(), pfcp.ServerAddress, ErrRequestTimeout)
+}
+
+func requestNode[T messages.PFCPMessage](ctx context.Context, pfcp *PFCP, message messages.PFCPMessage, deserialize func([]byte) (T, error)) (T, error) {
+	header := messages.NewNodeHeader(message.GetMessageType(), pfcp.nextSequenceNumber())
+	return request(ctx, pfcp, message, header, deserialize)
+}
+
+func requestSession[T messages.PFCPMessage](ctx context.Context, pfcp *PFCP, message messages.PFCPMessage, seid uint64, deserialize func([]byte) (T, error)) (T, error) {
+	header := messages.NewSessionHeader(message.GetMessageType(), seid, pfcp.nextSequenceNumber())
+	return request(ctx, pfcp, message, header, deserialize)
+}
+
+func request[T messages.PFCPMessage](ctx context.Context, pfcp *PFCP, message messages.PFCPMessage, header messages.Header, deserialize func([]byte) (T, error)) (T, error) {
+	var zero T
+	response, err := pfcp.intercept(ctx, message, header, func(ctx context.Context, message messages.PFCPMessage, header messages.Header) (messages.PFCPMessage, error) {
+		resp, err := pfcp.exchange(ctx, message, header)
+		if err != nil {
+			return nil, err
+		}
+		msg, err := deserialize(resp.payload)
+		if err != nil {
+			return nil, err
+		}
+		if cause, ok := metrics.CauseOf(msg); ok {
+			pfcp.metrics.Cause(resp.header.MessageType, cause)
+		}
+		return msg, nil
+	})
+	if err != nil {
+		return zero, err
+	}
+	msg, ok := response.(T)
+	if !ok {
+		return zero, fmt.Errorf("unexpected %T in response to %s", response, message.GetMessageTypeString())
+	}
+	return msg, nil
 }
 
 func (pfcp *PFCP) sendNodePfcpMessage(message messages.PFCPMessage, sequenceNumber uint32) error {
@@ -55,16 +373,34 @@ func (pfcp *PFCP) sendSessionPfcpMessage(message messages.PFCPMessage, seid uint
 }
 
 func (pfcp *PFCP) sendPfcpMessage(message messages.PFCPMessage, header messages.Header) error {
-	messageName := message.GetMessageTypeString()
+	_, err := pfcp.intercept(context.Background(), message, header, func(ctx context.Context, message messages.PFCPMessage, header messages.Header) (messages.PFCPMessage, error) {
+		return nil, pfcp.transmit(message, header)
+	})
+	return err
+}
+
+// transmit serializes and sends a message without going through the interceptors.
+func (pfcp *PFCP) transmit(message messages.PFCPMessage, header messages.Header) error {
 	payload := messages.Serialize(message, header)
 	if err := pfcp.Udp.Send(payload); err != nil {
-		log.Printf("Failed to send %s message to %v: %v\n", messageName, pfcp.ServerAddress, err)
+		pfcp.logger.Error("Failed to send message",
+			slog.String("message", message.GetMessageTypeString()), slog.Any("header", header), slog.Any("error", err))
 		return err
 	}
-	log.Printf("%s message sent successfully to %s.\n", messageName, pfcp.ServerAddress)
+	pfcp.logger.Debug("Message sent",
+		slog.String("message", message.GetMessageTypeString()), slog.Any("header", header))
+	pfcp.metrics.MessageSent(header.MessageType)
+	if cause, ok := metrics.CauseOf(message); ok {
+		pfcp.metrics.Cause(header.MessageType, cause)
+	}
 	return nil
 }
 
+// Send sends msg with the given header, for messages whose type is only known at runtime.
+func (pfcp *PFCP) Send(msg messages.PFCPMessage, header messages.Header) error {
+	return pfcp.sendPfcpMessage(msg, header)
+}
+
 func (pfcp *PFCP) SendHeartbeatRequest(msg messages.HeartbeatRequest, sequenceNumber uint32) error {
 	return pfcp.sendNodePfcpMessage(msg, sequenceNumber)
 }
@@ -113,6 +449,14 @@ func (pfcp *PFCP) SendPFCPSessionEstablishmentResponse(msg messages.PFCPSessionE
 	return pfcp.sendSessionPfcpMessage(msg, seid, sequenceNumber)
 }
 
+func (pfcp *PFCP) SendPFCPSessionModificationRequest(msg messages.PFCPSessionModificationRequest, seid uint64, sequenceNumber uint32) error {
+	return pfcp.sendSessionPfcpMessage(msg, seid, sequenceNumber)
+}
+
+func (pfcp *PFCP) SendPFCPSessionModificationResponse(msg messages.PFCPSessionModificationResponse, seid uint64, sequenceNumber uint32) error {
+	return pfcp.sendSessionPfcpMessage(msg, seid, sequenceNumber)
+}
+
 func (pfcp *PFCP) SendPFCPSessionDeletionRequest(msg messages.PFCPSessionDeletionRequest, seid uint64, sequenceNumber uint32) error {
 	return pfcp.sendSessionPfcpMessage(msg, seid, sequenceNumber)
 }
@@ -128,3 +472,45 @@ func (pfcp *PFCP) SendPFCPSessionReportRequest(msg messages.PFCPSessionReportReq
 func (pfcp *PFCP) SendPFCPSessionReportResponse(msg messages.PFCPSessionReportResponse, seid uint64, sequenceNumber uint32) error {
 	return pfcp.sendSessionPfcpMessage(msg, seid, sequenceNumber)
 }
+
+func (pfcp *PFCP) SendHeartbeatRequestAndWait(msg messages.HeartbeatRequest) (messages.HeartbeatResponse, error) {
+	return pfcp.SendHeartbeatRequestContext(context.Background(), msg)
+}
+
+// SendHeartbeatRequestContext sends a Heartbeat Request and waits for its response,
+// giving up when ctx is done even if retransmissions are left.
+func (pfcp *PFCP) SendHeartbeatRequestContext(ctx context.Context, msg messages.HeartbeatRequest) (messages.HeartbeatResponse, error) {
+	return requestNode(ctx, pfcp, msg, messages.DeserializeHeartbeatResponse)
+}
+
+func (pfcp *PFCP) SendPFCPAssociationSetupRequestAndWait(msg messages.PFCPAssociationSetupRequest) (messages.PFCPAssociationSetupResponse, error) {
+	return requestNode(context.Background(), pfcp, msg, messages.DeserializePFCPAssociationSetupResponse)
+}
+
+func (pfcp *PFCP) SendPFCPAssociationUpdateRequestAndWait(msg messages.PFCPAssociationUpdateRequest) (messages.PFCPAssociationUpdateResponse, error) {
+	return requestNode(context.Background(), pfcp, msg, messages.DeserializePFCPAssociationUpdateResponse)
+}
+
+func (pfcp *PFCP) SendPFCPAssociationReleaseRequestAndWait(msg messages.PFCPAssociationReleaseRequest) (messages.PFCPAssociationReleaseResponse, error) {
+	return requestNode(context.Background(), pfcp, msg, messages.DeserializePFCPAssociationReleaseResponse)
+}
+
+func (pfcp *PFCP) SendPFCPNodeReportRequestAndWait(msg messages.PFCPNodeReportRequest) (messages.PFCPNodeReportResponse, error) {
+	return requestNode(context.Background(), pfcp, msg, messages.DeserializePFCPNodeReportResponse)
+}
+
+func (pfcp *PFCP) SendPFCPSessionEstablishmentRequestAndWait(msg messages.PFCPSessionEstablishmentRequest, seid uint64) (messages.PFCPSessionEstablishmentResponse, error) {
+	return requestSession(context.Background(), pfcp, msg, seid, messages.DeserializePFCPSessionEstablishmentResponse)
+}
+
+func (pfcp *PFCP) SendPFCPSessionModificationRequestAndWait(msg messages.PFCPSessionModificationRequest, seid uint64) (messages.PFCPSessionModificationResponse, error) {
+	return requestSession(context.Background(), pfcp, msg, seid, messages.DeserializePFCPSessionModificationResponse)
+}
+
+func (pfcp *PFCP) SendPFCPSessionDeletionRequestAndWait(msg messages.PFCPSessionDeletionRequest, seid uint64) (messages.PFCPSessionDeletionResponse, error) {
+	return requestSession(context.Background(), pfcp, msg, seid, messages.DeserializePFCPSessionDeletionResponse)
+}
+
+func (pfcp *PFCP) SendPFCPSessionReportRequestAndWait(msg messages.PFCPSessionReportRequest, seid uint64) (messages.PFCPSessionReportResponse, error) {
+	return requestSession(context.Background(), pfcp, msg, seid, messages.DeserializePFCPSessionReportResponse)
+}
@@ -1,12 +1,21 @@
 package client_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/dot-5g/pfcp/client"
 	"github.com/dot-5g/pfcp/ie"
 	"github.com/dot-5g/pfcp/messages"
+	"github.com/dot-5g/pfcp/metrics"
 )
 
 type MockUDPSender struct {
@@ -42,3 +51,364 @@ func TestGivenPfcpWhenSendHeartbeatRequestThenNoError(t *testing.T) {
 		t.Errorf("SendHeartbeatRequest failed: %v", err)
 	}
 }
+
+type fakePeer struct {
+	conn     net.PacketConn
+	mu       sync.Mutex
+	requests []messages.Header
+	sources  []net.Addr
+}
+
+// newFakePeer starts a UDP peer on a random local port that replies to requests using respond.
+// When respond returns nil, the request is dropped.
+func newFakePeer(t *testing.T, respond func(attempt int, header messages.Header) []byte) *fakePeer {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening on UDP: %v", err)
+	}
+	peer := &fakePeer{conn: conn}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buffer := make([]byte, 1024)
+		for {
+			length, address, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			header, err := messages.DeserializeHeader(buffer[:length])
+			if err != nil {
+				continue
+			}
+			peer.mu.Lock()
+			peer.requests = append(peer.requests, header)
+			peer.sources = append(peer.sources, address)
+			attempt := len(peer.requests)
+			peer.mu.Unlock()
+			if reply := respond(attempt, header); reply != nil {
+				conn.WriteTo(reply, address)
+			}
+		}
+	}()
+
+	return peer
+}
+
+func (peer *fakePeer) Requests() []messages.Header {
+	peer.mu.Lock()
+	defer peer.mu.Unlock()
+	return append([]messages.Header{}, peer.requests...)
+}
+
+func (peer *fakePeer) Sources() []net.Addr {
+	peer.mu.Lock()
+	defer peer.mu.Unlock()
+	return append([]net.Addr{}, peer.sources...)
+}
+
+func TestGivenFirstRequestLostWhenSendHeartbeatRequestAndWaitThenRetransmittedAndResponseReturned(t *testing.T) {
+	responseRecoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+
+	peer := newFakePeer(t, func(attempt int, header messages.Header) []byte {
+		if attempt == 1 {
+			return nil
+		}
+		responseHeader := messages.NewNodeHeader(messages.HeartbeatResponseMessageType, header.SequenceNumber)
+		return messages.Serialize(messages.HeartbeatResponse{RecoveryTimeStamp: responseRecoveryTimeStamp}, responseHeader)
+	})
+
+	pfcpClient := client.New(peer.conn.LocalAddr().String(), client.WithRetransmission(100*time.Millisecond, 3))
+	defer pfcpClient.Close()
+
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+
+	response, err := pfcpClient.SendHeartbeatRequestAndWait(messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp})
+	if err != nil {
+		t.Fatalf("SendHeartbeatRequestAndWait failed: %v", err)
+	}
+
+	if response.RecoveryTimeStamp != responseRecoveryTimeStamp {
+		t.Errorf("Expected Recovery TimeStamp %v, got %v", responseRecoveryTimeStamp, response.RecoveryTimeStamp)
+	}
+
+	requests := peer.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests to be sent, got %d", len(requests))
+	}
+
+	if requests[0].SequenceNumber != requests[1].SequenceNumber {
+		t.Errorf("Expected retransmission to reuse sequence number %d, got %d", requests[0].SequenceNumber, requests[1].SequenceNumber)
+	}
+}
+
+func TestGivenNoResponseWhenSendHeartbeatRequestAndWaitThenTimeoutAfterN1Retries(t *testing.T) {
+	peer := newFakePeer(t, func(attempt int, header messages.Header) []byte {
+		return nil
+	})
+
+	n1 := 2
+	pfcpClient := client.New(peer.conn.LocalAddr().String(), client.WithRetransmission(50*time.Millisecond, n1))
+	defer pfcpClient.Close()
+
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+
+	_, err = pfcpClient.SendHeartbeatRequestAndWait(messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp})
+	if !errors.Is(err, client.ErrRequestTimeout) {
+		t.Fatalf("Expected ErrRequestTimeout, got %v", err)
+	}
+
+	if len(peer.Requests()) != n1+1 {
+		t.Errorf("Expected %d requests to be sent, got %d", n1+1, len(peer.Requests()))
+	}
+}
+
+func TestGivenConsecutiveRequestsWhenSendAndWaitThenSequenceNumbersAllocatedAndMatched(t *testing.T) {
+	peer := newFakePeer(t, func(attempt int, header messages.Header) []byte {
+		cause, _ := ie.NewCause(ie.RequestAccepted)
+		responseHeader := messages.NewSessionHeader(messages.PFCPSessionDeletionResponseMessageType, 77, header.SequenceNumber)
+		return messages.Serialize(messages.PFCPSessionDeletionResponse{Cause: cause}, responseHeader)
+	})
+
+	pfcpClient := client.New(peer.conn.LocalAddr().String(), client.WithRetransmission(time.Second, 0))
+	defer pfcpClient.Close()
+
+	for i := 0; i < 3; i++ {
+		response, err := pfcpClient.SendPFCPSessionDeletionRequestAndWait(messages.PFCPSessionDeletionRequest{}, 1234)
+		if err != nil {
+			t.Fatalf("SendPFCPSessionDeletionRequestAndWait failed: %v", err)
+		}
+		if response.Cause.Value != ie.RequestAccepted {
+			t.Errorf("Expected cause %d, got %d", ie.RequestAccepted, response.Cause.Value)
+		}
+	}
+
+	requests := peer.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 requests to be sent, got %d", len(requests))
+	}
+
+	for i, request := range requests {
+		if request.SEID != 1234 {
+			t.Errorf("Expected SEID 1234, got %d", request.SEID)
+		}
+		if i > 0 && request.SequenceNumber == requests[i-1].SequenceNumber {
+			t.Errorf("Expected a new sequence number for each request, got %d twice", request.SequenceNumber)
+		}
+	}
+}
+
+func TestGivenLocalAddressWhenSendHeartbeatRequestThenSentFromLocalAddress(t *testing.T) {
+	peer := newFakePeer(t, func(attempt int, header messages.Header) []byte {
+		responseHeader := messages.NewNodeHeader(messages.HeartbeatResponseMessageType, header.SequenceNumber)
+		return messages.Serialize(messages.HeartbeatResponse{}, responseHeader)
+	})
+
+	localConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening on UDP: %v", err)
+	}
+	localAddress := localConn.LocalAddr().String()
+	localConn.Close()
+
+	pfcpClient := client.New(peer.conn.LocalAddr().String(), client.WithLocalAddress(localAddress), client.WithRetransmission(time.Second, 0))
+	if pfcpClient == nil {
+		t.Fatalf("Expected client to be created")
+	}
+	defer pfcpClient.Close()
+
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err = pfcpClient.SendHeartbeatRequestAndWait(messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp})
+		if err != nil {
+			t.Fatalf("SendHeartbeatRequestAndWait failed: %v", err)
+		}
+	}
+
+	for _, source := range peer.Sources() {
+		if source.String() != localAddress {
+			t.Errorf("Expected request to be sent from %s, got %s", localAddress, source)
+		}
+	}
+}
+
+func TestGivenInterceptorsWhenSendHeartbeatRequestThenCalledInOrderAroundSend(t *testing.T) {
+	var calls []string
+	record := func(name string) client.Interceptor {
+		return func(ctx context.Context, peer net.Addr, message messages.PFCPMessage, header messages.Header, invoker client.Invoker) (messages.PFCPMessage, error) {
+			calls = append(calls, name+" "+message.GetMessageTypeString()+" to "+peer.String())
+			return invoker(ctx, message, header)
+		}
+	}
+	pfcpClient := client.New("127.0.0.1:8805", client.WithInterceptors(record("first"), record("second")))
+	pfcpClient.Udp = &MockUDPSender{
+		SendFunc: func(msg []byte) error {
+			calls = append(calls, "sent")
+			return nil
+		},
+	}
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+
+	err = pfcpClient.SendHeartbeatRequest(messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp}, 32)
+	if err != nil {
+		t.Fatalf("SendHeartbeatRequest failed: %v", err)
+	}
+
+	expected := []string{
+		"first Heartbeat Request to 127.0.0.1:8805",
+		"second Heartbeat Request to 127.0.0.1:8805",
+		"sent",
+	}
+	if strings.Join(calls, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("Expected calls %v, got %v", expected, calls)
+	}
+}
+
+func TestGivenInterceptorReturningResponseWhenSendHeartbeatRequestAndWaitThenResponseReturnedWithoutSending(t *testing.T) {
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+	cached := messages.HeartbeatResponse{RecoveryTimeStamp: recoveryTimeStamp}
+	pfcpClient := client.New("127.0.0.1:8805", client.WithInterceptors(
+		func(ctx context.Context, peer net.Addr, message messages.PFCPMessage, header messages.Header, invoker client.Invoker) (messages.PFCPMessage, error) {
+			return cached, nil
+		},
+	))
+	pfcpClient.Udp = &MockUDPSender{
+		SendFunc: func(msg []byte) error {
+			t.Errorf("Expected no message to be sent")
+			return nil
+		},
+	}
+
+	response, err := pfcpClient.SendHeartbeatRequestAndWait(messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp})
+	if err != nil {
+		t.Fatalf("SendHeartbeatRequestAndWait failed: %v", err)
+	}
+	if response.RecoveryTimeStamp != cached.RecoveryTimeStamp {
+		t.Errorf("Expected the response returned by the interceptor, got %v", response)
+	}
+}
+
+func TestGivenLoggerWhenSendHeartbeatRequestThenSendLoggedWithStructuredFields(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	pfcpClient := client.New("127.0.0.1:8805", client.WithLogger(logger))
+	pfcpClient.Udp = &MockUDPSender{
+		SendFunc: func(msg []byte) error {
+			return nil
+		},
+	}
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+
+	err = pfcpClient.SendHeartbeatRequest(messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp}, 33)
+	if err != nil {
+		t.Fatalf("SendHeartbeatRequest failed: %v", err)
+	}
+
+	var record struct {
+		Msg     string
+		Peer    string
+		Message string
+		Header  struct {
+			MessageType    int `json:"message_type"`
+			SequenceNumber int `json:"sequence_number"`
+		}
+	}
+	err = json.Unmarshal(buffer.Bytes(), &record)
+	if err != nil {
+		t.Fatalf("Error decoding log record %q: %v", buffer.String(), err)
+	}
+	if record.Peer != "127.0.0.1:8805" || record.Message != "Heartbeat Request" {
+		t.Errorf("Unexpected log record %q", buffer.String())
+	}
+	if record.Header.MessageType != int(messages.HeartbeatRequestMessageType) || record.Header.SequenceNumber != 33 {
+		t.Errorf("Unexpected header in log record %q", buffer.String())
+	}
+}
+
+func TestGivenMetricsWhenRequestsAnsweredAndLostThenTrafficRecorded(t *testing.T) {
+	peer := newFakePeer(t, func(attempt int, header messages.Header) []byte {
+		if header.MessageType != messages.PFCPSessionDeletionRequestMessageType {
+			return nil
+		}
+		cause, _ := ie.NewCause(ie.SessionContextNotFound)
+		responseHeader := messages.NewSessionHeader(messages.PFCPSessionDeletionResponseMessageType, 0, header.SequenceNumber)
+		return messages.Serialize(messages.PFCPSessionDeletionResponse{Cause: cause}, responseHeader)
+	})
+	prometheus := metrics.NewPrometheus()
+	pfcpClient := client.New(peer.conn.LocalAddr().String(), client.WithRetransmission(50*time.Millisecond, 1), client.WithMetrics(prometheus))
+	defer pfcpClient.Close()
+
+	_, err := pfcpClient.SendPFCPSessionDeletionRequestAndWait(messages.PFCPSessionDeletionRequest{}, 4)
+	if err != nil {
+		t.Fatalf("SendPFCPSessionDeletionRequestAndWait failed: %v", err)
+	}
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+	_, err = pfcpClient.SendHeartbeatRequestAndWait(messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp})
+	if !errors.Is(err, client.ErrRequestTimeout) {
+		t.Fatalf("Expected ErrRequestTimeout, got %v", err)
+	}
+
+	var output strings.Builder
+	prometheus.WriteTo(&output)
+	for _, expected := range []string{
+		`pfcp_messages_sent_total{message_type="1"} 2`,
+		`pfcp_messages_sent_total{message_type="54"} 1`,
+		`pfcp_messages_received_total{message_type="55"} 1`,
+		`pfcp_retransmissions_total{message_type="1"} 1`,
+		`pfcp_request_timeouts_total{message_type="1"} 1`,
+		`pfcp_causes_total{message_type="55",cause="65"} 1`,
+		`pfcp_response_latency_seconds_count{message_type="54"} 1`,
+	} {
+		if !strings.Contains(output.String(), expected+"\n") {
+			t.Errorf("Expected %s in metrics:\n%s", expected, output.String())
+		}
+	}
+}
+
+func TestGivenContextDoneWhenSendHeartbeatRequestContextThenReturnsBeforeRetransmissionsEnd(t *testing.T) {
+	peer := newFakePeer(t, func(attempt int, header messages.Header) []byte {
+		return nil
+	})
+	pfcpClient := client.New(peer.conn.LocalAddr().String(), client.WithRetransmission(time.Second, 3))
+	defer pfcpClient.Close()
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = pfcpClient.SendHeartbeatRequestContext(ctx, messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected to return when the context is done, took %s", elapsed)
+	}
+}
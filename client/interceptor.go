@@ -0,0 +1,28 @@
+package client
+
+import (
+	"context"
+	"net"
+
+	"github.com/dot-5g/pfcp/messages"
+)
+
+// Invoker sends a message and, for the requests sent by the methods waiting for a response,
+// returns that response once received.
+type Invoker func(ctx context.Context, message messages.PFCPMessage, header messages.Header) (messages.PFCPMessage, error)
+
+// Interceptor wraps the sending of messages to peer. It calls invoker to send the message,
+// or returns a response or an error without calling it to short-circuit the exchange.
+// The retransmissions of a request happen within a single call to invoker.
+type Interceptor func(ctx context.Context, peer net.Addr, message messages.PFCPMessage, header messages.Header, invoker Invoker) (messages.PFCPMessage, error)
+
+// intercept passes message through the interceptors of the client to invoker.
+func (pfcp *PFCP) intercept(ctx context.Context, message messages.PFCPMessage, header messages.Header, invoker Invoker) (messages.PFCPMessage, error) {
+	for i := len(pfcp.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := pfcp.interceptors[i], invoker
+		invoker = func(ctx context.Context, message messages.PFCPMessage, header messages.Header) (messages.PFCPMessage, error) {
+			return interceptor(ctx, pfcp.peer, message, header, next)
+		}
+	}
+	return invoker(ctx, message, header)
+}
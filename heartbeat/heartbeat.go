@@ -0,0 +1,232 @@
+// Package heartbeat checks that the path to each associated peer is alive, as described in TS 29.244 section 6.2.2.
+package heartbeat
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/dot-5g/pfcp/association"
+	"github.com/dot-5g/pfcp/messages"
+	"github.com/dot-5g/pfcp/server"
+)
+
+const (
+	// DefaultInterval is the time between two Heartbeat Requests sent to a peer.
+	DefaultInterval = 30 * time.Second
+	// DefaultMaxMissed is the number of consecutive Heartbeat Requests left without response
+	// after which the path to a peer is declared failed.
+	DefaultMaxMissed = 3
+)
+
+// PathFailure reports a peer that stopped answering Heartbeat Requests.
+type PathFailure struct {
+	Address string
+	Missed  int
+}
+
+// Scheduler sends Heartbeat Requests to peers from the server socket and answers
+// the Heartbeat Requests received by the server with its Recovery Time Stamp.
+type Scheduler struct {
+	server        *server.Server
+	interval      time.Duration
+	timeout       time.Duration
+	maxMissed     int
+	onPathFailure func(PathFailure)
+	onResponse    func(address string, response messages.HeartbeatResponse)
+	logger        *slog.Logger
+	mu            sync.Mutex
+	peers         map[string]*peer
+	wg            sync.WaitGroup
+}
+
+type peer struct {
+	cancel context.CancelFunc
+}
+
+type Option func(*Scheduler)
+
+// WithInterval sets the time between two Heartbeat Requests sent to a peer, DefaultInterval by default.
+func WithInterval(interval time.Duration) Option {
+	return func(scheduler *Scheduler) {
+		scheduler.interval = interval
+	}
+}
+
+// WithResponseTimeout sets how long a response to a Heartbeat Request is waited for,
+// retransmissions included. It defaults to the interval.
+func WithResponseTimeout(timeout time.Duration) Option {
+	return func(scheduler *Scheduler) {
+		scheduler.timeout = timeout
+	}
+}
+
+// WithMaxMissed sets the number of consecutive Heartbeat Requests left without response
+// after which the path to a peer is declared failed, DefaultMaxMissed by default.
+func WithMaxMissed(maxMissed int) Option {
+	return func(scheduler *Scheduler) {
+		scheduler.maxMissed = maxMissed
+	}
+}
+
+// WithPathFailureHandler registers a function called when the path to a peer is declared failed.
+// Heartbeat Requests are no longer sent to the peer afterwards.
+func WithPathFailureHandler(handler func(PathFailure)) Option {
+	return func(scheduler *Scheduler) {
+		scheduler.onPathFailure = handler
+	}
+}
+
+// WithResponseHandler registers a function called with every Heartbeat Response received from a peer.
+func WithResponseHandler(handler func(address string, response messages.HeartbeatResponse)) Option {
+	return func(scheduler *Scheduler) {
+		scheduler.onResponse = handler
+	}
+}
+
+// WithLogger sets the logger of the scheduler, slog.Default() by default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(scheduler *Scheduler) {
+		scheduler.logger = logger
+	}
+}
+
+// New creates a scheduler sending Heartbeat Requests from pfcpServer and registers its Heartbeat Request handler.
+func New(pfcpServer *server.Server, opts ...Option) *Scheduler {
+	scheduler := &Scheduler{
+		server:    pfcpServer,
+		interval:  DefaultInterval,
+		maxMissed: DefaultMaxMissed,
+		logger:    slog.Default(),
+		peers:     make(map[string]*peer),
+	}
+	for _, opt := range opts {
+		opt(scheduler)
+	}
+	if scheduler.timeout <= 0 {
+		scheduler.timeout = scheduler.interval
+	}
+	server.HandleRequest(pfcpServer, scheduler.handleHeartbeatRequest)
+	return scheduler
+}
+
+// Start sends Heartbeat Requests to the peer at address every interval until Stop is called
+// or the path to the peer fails. Starting a peer already started does nothing.
+func (scheduler *Scheduler) Start(address string) error {
+	udpAddress, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return err
+	}
+	key := udpAddress.String()
+	pfcpClient := scheduler.server.GetClientForAddress(udpAddress)
+	if pfcpClient == nil {
+		pfcpClient, err = scheduler.server.NewClient(key)
+		if err != nil {
+			return err
+		}
+	}
+
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	if _, started := scheduler.peers[key]; started {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &peer{cancel: cancel}
+	scheduler.peers[key] = p
+	scheduler.wg.Add(1)
+	go scheduler.run(ctx, key, p, pfcpClient.SendHeartbeatRequestContext)
+	return nil
+}
+
+// Stop stops sending Heartbeat Requests to the peer at address.
+func (scheduler *Scheduler) Stop(address string) {
+	udpAddress, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return
+	}
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	if p, started := scheduler.peers[udpAddress.String()]; started {
+		p.cancel()
+		delete(scheduler.peers, udpAddress.String())
+	}
+}
+
+// Close stops sending Heartbeat Requests to all peers and waits for the requests in progress.
+func (scheduler *Scheduler) Close() {
+	scheduler.mu.Lock()
+	for key, p := range scheduler.peers {
+		p.cancel()
+		delete(scheduler.peers, key)
+	}
+	scheduler.mu.Unlock()
+	scheduler.wg.Wait()
+}
+
+// HandleAssociationEvent starts sending Heartbeat Requests to peers once associated
+// and stops when the association is released, for use with association.WithEventHandler.
+// A peer whose Heartbeat Requests cannot be started is logged as an error.
+func (scheduler *Scheduler) HandleAssociationEvent(event association.Event) {
+	switch event.Peer.State {
+	case association.Associated:
+		if err := scheduler.Start(event.Peer.Address); err != nil {
+			scheduler.logger.Error("Failed to start sending Heartbeat Requests",
+				slog.String("peer", event.Peer.Address), slog.Any("error", err))
+		}
+	case association.Idle:
+		scheduler.Stop(event.Peer.Address)
+	}
+}
+
+type sendFunc func(ctx context.Context, msg messages.HeartbeatRequest) (messages.HeartbeatResponse, error)
+
+func (scheduler *Scheduler) run(ctx context.Context, key string, p *peer, send sendFunc) {
+	defer scheduler.wg.Done()
+	defer p.cancel()
+	ticker := time.NewTicker(scheduler.interval)
+	defer ticker.Stop()
+
+	missed := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		requestCtx, cancel := context.WithTimeout(ctx, scheduler.timeout)
+		response, err := send(requestCtx, messages.HeartbeatRequest{RecoveryTimeStamp: scheduler.server.RecoveryTimeStamp()})
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			missed = 0
+			if scheduler.onResponse != nil {
+				scheduler.onResponse(key, response)
+			}
+			continue
+		}
+
+		missed++
+		if missed < scheduler.maxMissed {
+			continue
+		}
+		scheduler.mu.Lock()
+		if scheduler.peers[key] == p {
+			delete(scheduler.peers, key)
+		}
+		scheduler.mu.Unlock()
+		if scheduler.onPathFailure != nil {
+			scheduler.onPathFailure(PathFailure{Address: key, Missed: missed})
+		}
+		return
+	}
+}
+
+func (scheduler *Scheduler) handleHeartbeatRequest(ctx context.Context, req messages.HeartbeatRequest) (messages.PFCPMessage, error) {
+	return messages.HeartbeatResponse{RecoveryTimeStamp: scheduler.server.RecoveryTimeStamp()}, nil
+}
@@ -0,0 +1,196 @@
+package heartbeat_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dot-5g/pfcp/association"
+	"github.com/dot-5g/pfcp/heartbeat"
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/internal/pfcptest"
+	"github.com/dot-5g/pfcp/messages"
+)
+
+func TestGivenAnsweringPeerWhenStartThenHeartbeatResponsesReceived(t *testing.T) {
+	peerServer := pfcptest.StartServer(t)
+	heartbeat.New(peerServer)
+	responses := make(chan messages.HeartbeatResponse, 10)
+	scheduler := heartbeat.New(pfcptest.StartServer(t),
+		heartbeat.WithInterval(20*time.Millisecond),
+		heartbeat.WithResponseHandler(func(address string, response messages.HeartbeatResponse) {
+			responses <- response
+		}),
+	)
+	defer scheduler.Close()
+
+	err := scheduler.Start(peerServer.Conn().LocalAddr().String())
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case response := <-responses:
+			if response.RecoveryTimeStamp != peerServer.RecoveryTimeStamp() {
+				t.Errorf("Expected Recovery Time Stamp %v, got %v", peerServer.RecoveryTimeStamp(), response.RecoveryTimeStamp)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected Heartbeat Responses, got %d", i)
+		}
+	}
+}
+
+func TestGivenSilentPeerWhenStartThenPathFailureAfterMaxMissed(t *testing.T) {
+	peerConn := pfcptest.NewPeer(t)
+	failures := make(chan heartbeat.PathFailure, 1)
+	scheduler := heartbeat.New(pfcptest.StartServer(t),
+		heartbeat.WithInterval(20*time.Millisecond),
+		heartbeat.WithResponseTimeout(30*time.Millisecond),
+		heartbeat.WithMaxMissed(3),
+		heartbeat.WithPathFailureHandler(func(failure heartbeat.PathFailure) {
+			failures <- failure
+		}),
+	)
+	defer scheduler.Close()
+
+	err := scheduler.Start(peerConn.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	select {
+	case failure := <-failures:
+		if failure.Address != peerConn.LocalAddr().String() || failure.Missed != 3 {
+			t.Errorf("Unexpected path failure %+v", failure)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Expected a path failure")
+	}
+}
+
+func TestGivenStartedPeerWhenStopThenNoMoreHeartbeatRequestsSent(t *testing.T) {
+	peerConn := pfcptest.NewPeer(t)
+	var requests atomic.Int32
+	go func() {
+		buffer := make([]byte, 1024)
+		for {
+			if _, _, err := peerConn.ReadFrom(buffer); err != nil {
+				return
+			}
+			requests.Add(1)
+		}
+	}()
+	scheduler := heartbeat.New(pfcptest.StartServer(t), heartbeat.WithInterval(20*time.Millisecond))
+	defer scheduler.Close()
+	err := scheduler.Start(peerConn.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	scheduler.Stop(peerConn.LocalAddr().String())
+	time.Sleep(50 * time.Millisecond)
+	sent := requests.Load()
+	time.Sleep(100 * time.Millisecond)
+
+	if sent == 0 {
+		t.Errorf("Expected Heartbeat Requests before Stop")
+	}
+	if requests.Load() != sent {
+		t.Errorf("Expected no Heartbeat Request after Stop, got %d more", requests.Load()-sent)
+	}
+}
+
+func TestGivenHeartbeatRequestWhenReceivedThenAnsweredWithRecoveryTimeStamp(t *testing.T) {
+	pfcpServer := pfcptest.StartServer(t)
+	heartbeat.New(pfcpServer)
+	peerConn := pfcptest.NewPeer(t)
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Error creating Recovery Time Stamp: %v", err)
+	}
+
+	request := messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp}
+	_, err = peerConn.WriteTo(messages.Serialize(request, messages.NewNodeHeader(messages.HeartbeatRequestMessageType, 12)), pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending Heartbeat Request: %v", err)
+	}
+
+	peerConn.SetReadDeadline(time.Now().Add(time.Second))
+	buffer := make([]byte, 1024)
+	length, _, err := peerConn.ReadFrom(buffer)
+	if err != nil {
+		t.Fatalf("Error reading Heartbeat Response: %v", err)
+	}
+	header, message, err := messages.Decode(buffer[:length])
+	if err != nil {
+		t.Fatalf("Error decoding Heartbeat Response: %v", err)
+	}
+	response, ok := message.(messages.HeartbeatResponse)
+	if !ok || header.SequenceNumber != 12 {
+		t.Fatalf("Expected Heartbeat Response 12, got %T %d", message, header.SequenceNumber)
+	}
+	if response.RecoveryTimeStamp != pfcpServer.RecoveryTimeStamp() {
+		t.Errorf("Expected Recovery Time Stamp %v, got %v", pfcpServer.RecoveryTimeStamp(), response.RecoveryTimeStamp)
+	}
+}
+
+func TestGivenAssociationEventsWhenPeerAssociatedThenHeartbeatsSent(t *testing.T) {
+	peerServer := pfcptest.StartServer(t)
+	heartbeat.New(peerServer)
+	responses := make(chan string, 10)
+	cpServer := pfcptest.StartServer(t)
+	scheduler := heartbeat.New(cpServer,
+		heartbeat.WithInterval(20*time.Millisecond),
+		heartbeat.WithResponseHandler(func(address string, response messages.HeartbeatResponse) {
+			responses <- address
+		}),
+	)
+	defer scheduler.Close()
+	association.New(cpServer, association.WithEventHandler(scheduler.HandleAssociationEvent))
+	features, err := ie.NewUPFunctionFeatures([]ie.UPFeature{ie.FTUP})
+	if err != nil {
+		t.Fatalf("Error creating UP Function Features: %v", err)
+	}
+	peerClient, err := peerServer.NewClient(cpServer.Conn().LocalAddr().String())
+	if err != nil {
+		t.Fatalf("Error creating client: %v", err)
+	}
+
+	response, err := peerClient.SendPFCPAssociationSetupRequestAndWait(messages.PFCPAssociationSetupRequest{
+		NodeID:             peerServer.NodeID(),
+		RecoveryTimeStamp:  peerServer.RecoveryTimeStamp(),
+		UPFunctionFeatures: &features,
+	})
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	if response.Cause.Value != ie.RequestAccepted {
+		t.Fatalf("Expected Setup to be accepted, got cause %d", response.Cause.Value)
+	}
+
+	select {
+	case address := <-responses:
+		if address != peerServer.Conn().LocalAddr().String() {
+			t.Errorf("Expected Heartbeat Response from %s, got %s", peerServer.Conn().LocalAddr(), address)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected a Heartbeat Response once associated")
+	}
+}
+
+func TestGivenUnresolvableAddressWhenPeerAssociatedThenStartErrorLogged(t *testing.T) {
+	var logs bytes.Buffer
+	scheduler := heartbeat.New(pfcptest.StartServer(t), heartbeat.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	defer scheduler.Close()
+
+	scheduler.HandleAssociationEvent(association.Event{Peer: association.Peer{Address: "not an address", State: association.Associated}})
+
+	if !strings.Contains(logs.String(), "Failed to start sending Heartbeat Requests") || !strings.Contains(logs.String(), "not an address") {
+		t.Errorf("Expected the start error to be logged, got %q", logs.String())
+	}
+}
@@ -0,0 +1,39 @@
+package ie
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type BARID struct {
+	Value uint8
+}
+
+func NewBARID(value uint8) (BARID, error) {
+	return BARID{
+		Value: value,
+	}, nil
+}
+
+func (barID BARID) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	// Octet 5: Value
+	buf.WriteByte(barID.Value)
+
+	return buf.Bytes()
+}
+
+func (barID BARID) GetType() IEType {
+	return BARIDIEType
+}
+
+func DeserializeBARID(ieValue []byte) (BARID, error) {
+	if len(ieValue) != 1 {
+		return BARID{}, fmt.Errorf("invalid length for BARID: got %d bytes, want 1", len(ieValue))
+	}
+
+	return BARID{
+		Value: ieValue[0],
+	}, nil
+}
@@ -0,0 +1,32 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenBARIDSerializedWhenDeserializeThenFieldsSetCorrectly(t *testing.T) {
+	barID, err := ie.NewBARID(12)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	deserializedBARID, err := ie.DeserializeBARID(barID.Serialize())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if deserializedBARID.Value != 12 {
+		t.Errorf("Expected Value 12, got %d", deserializedBARID.Value)
+	}
+}
+
+func TestGivenInvalidLengthWhenDeserializeBARIDThenErrorReturned(t *testing.T) {
+	_, err := ie.DeserializeBARID([]byte{0x01, 0x02})
+
+	if err == nil {
+		t.Fatalf("Expected error, got nil")
+	}
+}
@@ -0,0 +1,87 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type CreateBAR struct {
+	BARID                          BARID                           // Mandatory
+	SuggestedBufferingPacketsCount *SuggestedBufferingPacketsCount // Conditional
+}
+
+func NewCreateBAR(barID BARID, suggestedBufferingPacketsCount *SuggestedBufferingPacketsCount) (CreateBAR, error) {
+	return CreateBAR{
+		BARID:                          barID,
+		SuggestedBufferingPacketsCount: suggestedBufferingPacketsCount,
+	}, nil
+}
+
+func (createBAR CreateBAR) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	for _, ie := range createBAR.GetIEs() {
+		serializedIE := ie.Serialize()
+		ieLength := uint16(len(serializedIE))
+		ieHeader := Header{
+			Type:   ie.GetType(),
+			Length: ieLength,
+		}
+		buf.Write(ieHeader.Serialize())
+		buf.Write(serializedIE)
+	}
+
+	return buf.Bytes()
+}
+
+func (createBAR CreateBAR) GetIEs() []InformationElement {
+	ies := []InformationElement{createBAR.BARID}
+	if createBAR.SuggestedBufferingPacketsCount != nil {
+		ies = append(ies, *createBAR.SuggestedBufferingPacketsCount)
+	}
+	return ies
+}
+
+func (createBAR CreateBAR) GetType() IEType {
+	return CreateBARIEType
+}
+
+func DeserializeCreateBAR(value []byte) (CreateBAR, error) {
+	createBAR := CreateBAR{}
+
+	index := 0
+	for index < len(value) {
+		if index+4 > len(value) {
+			return CreateBAR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEType := binary.BigEndian.Uint16(value[index : index+2])
+		currentIELength := binary.BigEndian.Uint16(value[index+2 : index+4])
+
+		if index+4+int(currentIELength) > len(value) {
+			return CreateBAR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEValue := value[index+4 : index+4+int(currentIELength)]
+
+		switch IEType(currentIEType) {
+		case BARIDIEType:
+			barID, err := DeserializeBARID(currentIEValue)
+			if err != nil {
+				return CreateBAR{}, fmt.Errorf("failed to deserialize BAR ID: %v", err)
+			}
+			createBAR.BARID = barID
+		case SuggestedBufferingPacketsCountIEType:
+			count, err := DeserializeSuggestedBufferingPacketsCount(currentIEValue)
+			if err != nil {
+				return CreateBAR{}, fmt.Errorf("failed to deserialize Suggested Buffering Packets Count: %v", err)
+			}
+			createBAR.SuggestedBufferingPacketsCount = &count
+		}
+
+		index += 4 + int(currentIELength)
+	}
+
+	return createBAR, nil
+}
@@ -0,0 +1,37 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenSerializedWhenDeserializeCreateBARThenFieldsSetCorrectly(t *testing.T) {
+	barID, err := ie.NewBARID(1)
+	if err != nil {
+		t.Fatalf("Error creating BARID: %v", err)
+	}
+
+	count, err := ie.NewSuggestedBufferingPacketsCount(16)
+	if err != nil {
+		t.Fatalf("Error creating SuggestedBufferingPacketsCount: %v", err)
+	}
+
+	createBAR, err := ie.NewCreateBAR(barID, &count)
+	if err != nil {
+		t.Fatalf("Error creating CreateBAR: %v", err)
+	}
+
+	deserialized, err := ie.DeserializeCreateBAR(createBAR.Serialize())
+	if err != nil {
+		t.Fatalf("Error deserializing CreateBAR: %v", err)
+	}
+
+	if deserialized.BARID != barID {
+		t.Errorf("Expected BARID %v, got %v", barID, deserialized.BARID)
+	}
+
+	if deserialized.SuggestedBufferingPacketsCount == nil || *deserialized.SuggestedBufferingPacketsCount != count {
+		t.Errorf("Expected SuggestedBufferingPacketsCount %v, got %v", count, deserialized.SuggestedBufferingPacketsCount)
+	}
+}
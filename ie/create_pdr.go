@@ -7,9 +7,12 @@ import (
 )
 
 type CreatePDR struct {
-	PDRID      PDRID
-	Precedence Precedence
-	PDI        PDI
+	PDRID      PDRID      // Mandatory
+	Precedence Precedence // Mandatory
+	PDI        PDI        // Mandatory
+	FARID      *FARID     // Conditional
+	URRID      []URRID    // Conditional
+	QERID      []QERID    // Conditional
 }
 
 func NewCreatePDR(pdrID PDRID, precedence Precedence, pdi PDI) (CreatePDR, error) {
@@ -38,7 +41,17 @@ func (createPDR CreatePDR) Serialize() []byte {
 }
 
 func (createPDR CreatePDR) GetIEs() []InformationElement {
-	return []InformationElement{createPDR.PDRID, createPDR.Precedence, createPDR.PDI}
+	ies := []InformationElement{createPDR.PDRID, createPDR.Precedence, createPDR.PDI}
+	if createPDR.FARID != nil {
+		ies = append(ies, *createPDR.FARID)
+	}
+	for _, urrID := range createPDR.URRID {
+		ies = append(ies, urrID)
+	}
+	for _, qerID := range createPDR.QERID {
+		ies = append(ies, qerID)
+	}
+	return ies
 }
 
 func (createPDR CreatePDR) GetType() IEType {
@@ -87,6 +100,24 @@ func DeserializeCreatePDR(value []byte) (CreatePDR, error) {
 				return CreatePDR{}, fmt.Errorf("failed to deserialize PDI: %v", err)
 			}
 			createPDR.PDI = pdi
+		case FARIDIEType:
+			farID, err := DeserializeFARID(currentIEValue)
+			if err != nil {
+				return CreatePDR{}, fmt.Errorf("failed to deserialize FAR ID: %v", err)
+			}
+			createPDR.FARID = &farID
+		case URRIDIEType:
+			urrID, err := DeserializeURRID(currentIEValue)
+			if err != nil {
+				return CreatePDR{}, fmt.Errorf("failed to deserialize URR ID: %v", err)
+			}
+			createPDR.URRID = append(createPDR.URRID, urrID)
+		case QERIDIEType:
+			qerID, err := DeserializeQERID(currentIEValue)
+			if err != nil {
+				return CreatePDR{}, fmt.Errorf("failed to deserialize QER ID: %v", err)
+			}
+			createPDR.QERID = append(createPDR.QERID, qerID)
 		}
 
 		index += 4 + int(currentIELength)
@@ -30,7 +30,7 @@ func TestGivenCorrectParametersWhenNewCreatePDRThenFieldsSetCorrectly(t *testing
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
-	pdi, err := ie.NewPDI(sourceInterface, ueIPAddress)
+	pdi, err := ie.NewPDI(sourceInterface, &ueIPAddress)
 	if err != nil {
 		t.Fatalf("Error creating PDI: %v", err)
 	}
@@ -123,7 +123,7 @@ func TestGivenSerializedWhenDeserializeCreatePDRThenFieldsSetCorrectly(t *testin
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
-	pdi, err := ie.NewPDI(sourceInterface, ueIPAddress)
+	pdi, err := ie.NewPDI(sourceInterface, &ueIPAddress)
 
 	if err != nil {
 		t.Fatalf("Error creating PDI: %v", err)
@@ -199,3 +199,52 @@ func TestGivenSerializedWhenDeserializeCreatePDRThenFieldsSetCorrectly(t *testin
 		t.Errorf("Expected CreatePDR PDI UEIPAddress IPv6PrefixLength 0, got %d", deserialized.PDI.UEIPAddress.IPv6PrefixLength)
 	}
 }
+
+func TestGivenRuleReferencesWhenDeserializeCreatePDRThenReferencesSetCorrectly(t *testing.T) {
+	pdrID, err := ie.NewPDRID(1)
+	if err != nil {
+		t.Fatalf("Error creating PDRID: %v", err)
+	}
+	precedence, err := ie.NewPrecedence(1)
+	if err != nil {
+		t.Fatalf("Error creating Precedence: %v", err)
+	}
+	sourceInterface, err := ie.NewSourceInterface(1)
+	if err != nil {
+		t.Fatalf("Error creating SourceInterface: %v", err)
+	}
+	ueIPAddress, err := ie.NewUEIPAddress("1.2.3.4", "", ie.SourceDestination{}, 0, 0, false, false)
+	if err != nil {
+		t.Fatalf("Error creating UEIPAddress: %v", err)
+	}
+	pdi, err := ie.NewPDI(sourceInterface, &ueIPAddress)
+	if err != nil {
+		t.Fatalf("Error creating PDI: %v", err)
+	}
+	farID, err := ie.NewFarID(3)
+	if err != nil {
+		t.Fatalf("Error creating FARID: %v", err)
+	}
+	createPDR, err := ie.NewCreatePDR(pdrID, precedence, pdi)
+	if err != nil {
+		t.Fatalf("Error creating CreatePDR: %v", err)
+	}
+	createPDR.FARID = &farID
+	createPDR.URRID = []ie.URRID{{Value: 4}, {Value: 5}}
+	createPDR.QERID = []ie.QERID{{Value: 6}}
+
+	deserialized, err := ie.DeserializeCreatePDR(createPDR.Serialize())
+	if err != nil {
+		t.Fatalf("Error deserializing CreatePDR: %v", err)
+	}
+
+	if deserialized.FARID == nil || *deserialized.FARID != farID {
+		t.Errorf("Expected CreatePDR FARID %v, got %v", farID, deserialized.FARID)
+	}
+	if len(deserialized.URRID) != 2 || deserialized.URRID[0].Value != 4 || deserialized.URRID[1].Value != 5 {
+		t.Errorf("Expected CreatePDR URRID %v, got %v", createPDR.URRID, deserialized.URRID)
+	}
+	if len(deserialized.QERID) != 1 || deserialized.QERID[0].Value != 6 {
+		t.Errorf("Expected CreatePDR QERID %v, got %v", createPDR.QERID, deserialized.QERID)
+	}
+}
@@ -0,0 +1,83 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type CreateQER struct {
+	QERID      QERID      // Mandatory
+	GateStatus GateStatus // Mandatory
+}
+
+func NewCreateQER(qerID QERID, gateStatus GateStatus) (CreateQER, error) {
+	return CreateQER{
+		QERID:      qerID,
+		GateStatus: gateStatus,
+	}, nil
+}
+
+func (createQER CreateQER) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	for _, ie := range createQER.GetIEs() {
+		serializedIE := ie.Serialize()
+		ieLength := uint16(len(serializedIE))
+		ieHeader := Header{
+			Type:   ie.GetType(),
+			Length: ieLength,
+		}
+		buf.Write(ieHeader.Serialize())
+		buf.Write(serializedIE)
+	}
+
+	return buf.Bytes()
+}
+
+func (createQER CreateQER) GetIEs() []InformationElement {
+	return []InformationElement{createQER.QERID, createQER.GateStatus}
+}
+
+func (createQER CreateQER) GetType() IEType {
+	return CreateQERIEType
+}
+
+func DeserializeCreateQER(value []byte) (CreateQER, error) {
+	createQER := CreateQER{}
+
+	index := 0
+	for index < len(value) {
+		if index+4 > len(value) {
+			return CreateQER{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEType := binary.BigEndian.Uint16(value[index : index+2])
+		currentIELength := binary.BigEndian.Uint16(value[index+2 : index+4])
+
+		if index+4+int(currentIELength) > len(value) {
+			return CreateQER{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEValue := value[index+4 : index+4+int(currentIELength)]
+
+		switch IEType(currentIEType) {
+		case QERIDIEType:
+			qerID, err := DeserializeQERID(currentIEValue)
+			if err != nil {
+				return CreateQER{}, fmt.Errorf("failed to deserialize QER ID: %v", err)
+			}
+			createQER.QERID = qerID
+		case GateStatusIEType:
+			gateStatus, err := DeserializeGateStatus(currentIEValue)
+			if err != nil {
+				return CreateQER{}, fmt.Errorf("failed to deserialize Gate Status: %v", err)
+			}
+			createQER.GateStatus = gateStatus
+		}
+
+		index += 4 + int(currentIELength)
+	}
+
+	return createQER, nil
+}
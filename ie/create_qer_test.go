@@ -0,0 +1,35 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenSerializedWhenDeserializeCreateQERThenFieldsSetCorrectly(t *testing.T) {
+	qerID, err := ie.NewQERID(7)
+	if err != nil {
+		t.Fatalf("Error creating QERID: %v", err)
+	}
+
+	gateStatus, err := ie.NewGateStatus(ie.GateOpen, ie.GateClosed)
+	if err != nil {
+		t.Fatalf("Error creating GateStatus: %v", err)
+	}
+
+	createQER, err := ie.NewCreateQER(qerID, gateStatus)
+	if err != nil {
+		t.Fatalf("Error creating CreateQER: %v", err)
+	}
+
+	serialized := createQER.Serialize()
+
+	deserialized, err := ie.DeserializeCreateQER(serialized)
+	if err != nil {
+		t.Fatalf("Error deserializing CreateQER: %v", err)
+	}
+
+	if deserialized != createQER {
+		t.Errorf("Expected CreateQER %v, got %v", createQER, deserialized)
+	}
+}
@@ -0,0 +1,91 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type CreateURR struct {
+	URRID             URRID             // Mandatory
+	MeasurementMethod MeasurementMethod // Mandatory
+	ReportingTriggers ReportingTriggers // Mandatory
+}
+
+func NewCreateURR(urrID URRID, measurementMethod MeasurementMethod, reportingTriggers ReportingTriggers) (CreateURR, error) {
+	return CreateURR{
+		URRID:             urrID,
+		MeasurementMethod: measurementMethod,
+		ReportingTriggers: reportingTriggers,
+	}, nil
+}
+
+func (createURR CreateURR) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	for _, ie := range createURR.GetIEs() {
+		serializedIE := ie.Serialize()
+		ieLength := uint16(len(serializedIE))
+		ieHeader := Header{
+			Type:   ie.GetType(),
+			Length: ieLength,
+		}
+		buf.Write(ieHeader.Serialize())
+		buf.Write(serializedIE)
+	}
+
+	return buf.Bytes()
+}
+
+func (createURR CreateURR) GetIEs() []InformationElement {
+	return []InformationElement{createURR.URRID, createURR.MeasurementMethod, createURR.ReportingTriggers}
+}
+
+func (createURR CreateURR) GetType() IEType {
+	return CreateURRIEType
+}
+
+func DeserializeCreateURR(value []byte) (CreateURR, error) {
+	createURR := CreateURR{}
+
+	index := 0
+	for index < len(value) {
+		if index+4 > len(value) {
+			return CreateURR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEType := binary.BigEndian.Uint16(value[index : index+2])
+		currentIELength := binary.BigEndian.Uint16(value[index+2 : index+4])
+
+		if index+4+int(currentIELength) > len(value) {
+			return CreateURR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEValue := value[index+4 : index+4+int(currentIELength)]
+
+		switch IEType(currentIEType) {
+		case URRIDIEType:
+			urrID, err := DeserializeURRID(currentIEValue)
+			if err != nil {
+				return CreateURR{}, fmt.Errorf("failed to deserialize URR ID: %v", err)
+			}
+			createURR.URRID = urrID
+		case MeasurementMethodIEType:
+			measurementMethod, err := DeserializeMeasurementMethod(currentIEValue)
+			if err != nil {
+				return CreateURR{}, fmt.Errorf("failed to deserialize Measurement Method: %v", err)
+			}
+			createURR.MeasurementMethod = measurementMethod
+		case ReportingTriggersIEType:
+			reportingTriggers, err := DeserializeReportingTriggers(currentIEValue)
+			if err != nil {
+				return CreateURR{}, fmt.Errorf("failed to deserialize Reporting Triggers: %v", err)
+			}
+			createURR.ReportingTriggers = reportingTriggers
+		}
+
+		index += 4 + int(currentIELength)
+	}
+
+	return createURR, nil
+}
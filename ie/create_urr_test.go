@@ -0,0 +1,48 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenSerializedWhenDeserializeCreateURRThenFieldsSetCorrectly(t *testing.T) {
+	urrID, err := ie.NewURRID(1)
+	if err != nil {
+		t.Fatalf("Error creating URRID: %v", err)
+	}
+
+	measurementMethod, err := ie.NewMeasurementMethod(false, true, false)
+	if err != nil {
+		t.Fatalf("Error creating MeasurementMethod: %v", err)
+	}
+
+	reportingTriggers, err := ie.NewReportingTriggers([]ie.ReportingTrigger{ie.VOLTH})
+	if err != nil {
+		t.Fatalf("Error creating ReportingTriggers: %v", err)
+	}
+
+	createURR, err := ie.NewCreateURR(urrID, measurementMethod, reportingTriggers)
+	if err != nil {
+		t.Fatalf("Error creating CreateURR: %v", err)
+	}
+
+	serialized := createURR.Serialize()
+
+	deserialized, err := ie.DeserializeCreateURR(serialized)
+	if err != nil {
+		t.Fatalf("Error deserializing CreateURR: %v", err)
+	}
+
+	if deserialized.URRID != urrID {
+		t.Errorf("Expected URRID %v, got %v", urrID, deserialized.URRID)
+	}
+
+	if deserialized.MeasurementMethod != measurementMethod {
+		t.Errorf("Expected MeasurementMethod %v, got %v", measurementMethod, deserialized.MeasurementMethod)
+	}
+
+	if len(deserialized.ReportingTriggers.Triggers) != 1 || deserialized.ReportingTriggers.Triggers[0] != ie.VOLTH {
+		t.Errorf("Expected ReportingTriggers %v, got %v", reportingTriggers, deserialized.ReportingTriggers)
+	}
+}
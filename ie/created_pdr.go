@@ -0,0 +1,98 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type CreatedPDR struct {
+	PDRID       PDRID        // Mandatory
+	LocalFTEID  *FTEID       // Conditional
+	UEIPAddress *UEIPAddress // Conditional
+}
+
+func NewCreatedPDR(pdrID PDRID, localFTEID *FTEID, ueIPAddress *UEIPAddress) (CreatedPDR, error) {
+	return CreatedPDR{
+		PDRID:       pdrID,
+		LocalFTEID:  localFTEID,
+		UEIPAddress: ueIPAddress,
+	}, nil
+}
+
+func (createdPDR CreatedPDR) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	for _, ie := range createdPDR.GetIEs() {
+		serializedIE := ie.Serialize()
+		ieLength := uint16(len(serializedIE))
+		ieHeader := Header{
+			Type:   ie.GetType(),
+			Length: ieLength,
+		}
+		buf.Write(ieHeader.Serialize())
+		buf.Write(serializedIE)
+	}
+
+	return buf.Bytes()
+}
+
+func (createdPDR CreatedPDR) GetIEs() []InformationElement {
+	ies := []InformationElement{createdPDR.PDRID}
+	if createdPDR.LocalFTEID != nil {
+		ies = append(ies, *createdPDR.LocalFTEID)
+	}
+	if createdPDR.UEIPAddress != nil {
+		ies = append(ies, *createdPDR.UEIPAddress)
+	}
+	return ies
+}
+
+func (createdPDR CreatedPDR) GetType() IEType {
+	return CreatedPDRIEType
+}
+
+func DeserializeCreatedPDR(value []byte) (CreatedPDR, error) {
+	createdPDR := CreatedPDR{}
+
+	index := 0
+	for index < len(value) {
+		if index+4 > len(value) {
+			return CreatedPDR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEType := binary.BigEndian.Uint16(value[index : index+2])
+		currentIELength := binary.BigEndian.Uint16(value[index+2 : index+4])
+
+		if index+4+int(currentIELength) > len(value) {
+			return CreatedPDR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEValue := value[index+4 : index+4+int(currentIELength)]
+
+		switch IEType(currentIEType) {
+		case PDRIDIEType:
+			pdrID, err := DeserializePDRID(currentIEValue)
+			if err != nil {
+				return CreatedPDR{}, fmt.Errorf("failed to deserialize PDR ID: %v", err)
+			}
+			createdPDR.PDRID = pdrID
+		case FTEIDIEType:
+			localFTEID, err := DeserializeFTEID(currentIEValue)
+			if err != nil {
+				return CreatedPDR{}, fmt.Errorf("failed to deserialize Local F-TEID: %v", err)
+			}
+			createdPDR.LocalFTEID = &localFTEID
+		case UEIPAddressIEType:
+			ueIPAddress, err := DeserializeUEIPAddress(currentIEValue)
+			if err != nil {
+				return CreatedPDR{}, fmt.Errorf("failed to deserialize UE IP Address: %v", err)
+			}
+			createdPDR.UEIPAddress = &ueIPAddress
+		}
+
+		index += 4 + int(currentIELength)
+	}
+
+	return createdPDR, nil
+}
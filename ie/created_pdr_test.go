@@ -0,0 +1,47 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenSerializedWhenDeserializeCreatedPDRThenFieldsSetCorrectly(t *testing.T) {
+	pdrID, err := ie.NewPDRID(1)
+	if err != nil {
+		t.Fatalf("Error creating PDRID: %v", err)
+	}
+
+	localFTEID, err := ie.NewFTEID(0xABCD, "192.168.0.1", "")
+	if err != nil {
+		t.Fatalf("Error creating FTEID: %v", err)
+	}
+
+	createdPDR, err := ie.NewCreatedPDR(pdrID, &localFTEID, nil)
+	if err != nil {
+		t.Fatalf("Error creating CreatedPDR: %v", err)
+	}
+
+	serialized := createdPDR.Serialize()
+
+	deserialized, err := ie.DeserializeCreatedPDR(serialized)
+	if err != nil {
+		t.Fatalf("Error deserializing CreatedPDR: %v", err)
+	}
+
+	if deserialized.PDRID != pdrID {
+		t.Errorf("Expected PDRID %v, got %v", pdrID, deserialized.PDRID)
+	}
+
+	if deserialized.LocalFTEID == nil {
+		t.Fatalf("Expected LocalFTEID, got nil")
+	}
+
+	if deserialized.LocalFTEID.TEID != localFTEID.TEID {
+		t.Errorf("Expected LocalFTEID TEID %d, got %d", localFTEID.TEID, deserialized.LocalFTEID.TEID)
+	}
+
+	if deserialized.UEIPAddress != nil {
+		t.Errorf("Expected no UEIPAddress, got %v", deserialized.UEIPAddress)
+	}
+}
@@ -0,0 +1,101 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type RuleIDType int
+
+const (
+	PDRRuleIDType RuleIDType = iota
+	FARRuleIDType
+	QERRuleIDType
+	URRRuleIDType
+	BARRuleIDType
+)
+
+type FailedRuleID struct {
+	RuleIDType  RuleIDType
+	RuleIDValue uint32
+}
+
+func NewFailedRuleID(ruleIDType RuleIDType, ruleIDValue uint32) (FailedRuleID, error) {
+	switch ruleIDType {
+	case PDRRuleIDType:
+		if ruleIDValue > 0xFFFF {
+			return FailedRuleID{}, fmt.Errorf("invalid value for PDR Rule ID: got %d, want 0-65535", ruleIDValue)
+		}
+	case BARRuleIDType:
+		if ruleIDValue > 0xFF {
+			return FailedRuleID{}, fmt.Errorf("invalid value for BAR Rule ID: got %d, want 0-255", ruleIDValue)
+		}
+	case FARRuleIDType, QERRuleIDType, URRRuleIDType:
+	default:
+		return FailedRuleID{}, fmt.Errorf("invalid Rule ID Type: %d", ruleIDType)
+	}
+
+	return FailedRuleID{
+		RuleIDType:  ruleIDType,
+		RuleIDValue: ruleIDValue,
+	}, nil
+}
+
+func (failedRuleID FailedRuleID) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	// Octet 5: Spare (3 bits), Rule ID Type (5 bits)
+	buf.WriteByte(byte(failedRuleID.RuleIDType) & 0x1F)
+
+	// Octets 6 to p: Rule ID value
+	switch failedRuleID.RuleIDType {
+	case PDRRuleIDType:
+		binary.Write(buf, binary.BigEndian, uint16(failedRuleID.RuleIDValue))
+	case BARRuleIDType:
+		buf.WriteByte(byte(failedRuleID.RuleIDValue))
+	default:
+		binary.Write(buf, binary.BigEndian, failedRuleID.RuleIDValue)
+	}
+
+	return buf.Bytes()
+}
+
+func (failedRuleID FailedRuleID) GetType() IEType {
+	return FailedRuleIDIEType
+}
+
+func DeserializeFailedRuleID(ieValue []byte) (FailedRuleID, error) {
+	if len(ieValue) < 1 {
+		return FailedRuleID{}, fmt.Errorf("invalid length for FailedRuleID: got %d bytes, expected at least 1", len(ieValue))
+	}
+
+	ruleIDType := RuleIDType(ieValue[0] & 0x1F)
+	value := ieValue[1:]
+
+	var ruleIDValue uint32
+	switch ruleIDType {
+	case PDRRuleIDType:
+		if len(value) != 2 {
+			return FailedRuleID{}, fmt.Errorf("invalid length for PDR Rule ID: got %d bytes, want 2", len(value))
+		}
+		ruleIDValue = uint32(binary.BigEndian.Uint16(value))
+	case BARRuleIDType:
+		if len(value) != 1 {
+			return FailedRuleID{}, fmt.Errorf("invalid length for BAR Rule ID: got %d bytes, want 1", len(value))
+		}
+		ruleIDValue = uint32(value[0])
+	case FARRuleIDType, QERRuleIDType, URRRuleIDType:
+		if len(value) != 4 {
+			return FailedRuleID{}, fmt.Errorf("invalid length for Rule ID: got %d bytes, want 4", len(value))
+		}
+		ruleIDValue = binary.BigEndian.Uint32(value)
+	default:
+		return FailedRuleID{}, fmt.Errorf("invalid Rule ID Type: %d", ruleIDType)
+	}
+
+	return FailedRuleID{
+		RuleIDType:  ruleIDType,
+		RuleIDValue: ruleIDValue,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenPDRRuleIDOutOfRangeWhenNewFailedRuleIDThenErrorReturned(t *testing.T) {
+	_, err := ie.NewFailedRuleID(ie.PDRRuleIDType, 0x10000)
+
+	if err == nil {
+		t.Fatalf("Expected error, got nil")
+	}
+}
+
+func TestGivenInvalidRuleIDTypeWhenNewFailedRuleIDThenErrorReturned(t *testing.T) {
+	_, err := ie.NewFailedRuleID(ie.RuleIDType(7), 1)
+
+	if err == nil {
+		t.Fatalf("Expected error, got nil")
+	}
+}
+
+func TestGivenFailedRuleIDSerializedWhenDeserializeThenFieldsSetCorrectly(t *testing.T) {
+	cases := []struct {
+		ruleIDType     ie.RuleIDType
+		ruleIDValue    uint32
+		expectedLength int
+	}{
+		{ie.PDRRuleIDType, 0x1234, 3},
+		{ie.FARRuleIDType, 0x12345678, 5},
+		{ie.QERRuleIDType, 9, 5},
+		{ie.URRRuleIDType, 10, 5},
+		{ie.BARRuleIDType, 0x12, 2},
+	}
+
+	for _, c := range cases {
+		failedRuleID, err := ie.NewFailedRuleID(c.ruleIDType, c.ruleIDValue)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		serialized := failedRuleID.Serialize()
+
+		if len(serialized) != c.expectedLength {
+			t.Errorf("Expected %d bytes for rule type %d, got %d", c.expectedLength, c.ruleIDType, len(serialized))
+		}
+
+		deserialized, err := ie.DeserializeFailedRuleID(serialized)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if deserialized != failedRuleID {
+			t.Errorf("Expected %v, got %v", failedRuleID, deserialized)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package ie
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"net"
 )
 
@@ -77,8 +78,24 @@ func (fseid FSEID) GetType() IEType {
 }
 
 func DeserializeFSEID(ieValue []byte) (FSEID, error) {
+	if len(ieValue) < 9 {
+		return FSEID{}, fmt.Errorf("invalid length for FSEID: got %d bytes, expected at least 9", len(ieValue))
+	}
+
 	v4 := ieValue[0]&0x02 > 0
 	v6 := ieValue[0]&0x01 > 0
+
+	expectedLength := 9
+	if v4 {
+		expectedLength += 4
+	}
+	if v6 {
+		expectedLength += 16
+	}
+	if len(ieValue) < expectedLength {
+		return FSEID{}, fmt.Errorf("invalid length for FSEID: got %d bytes, expected at least %d", len(ieValue), expectedLength)
+	}
+
 	seid := binary.BigEndian.Uint64(ieValue[1:9])
 	var ipv4 []byte
 	var ipv6 []byte
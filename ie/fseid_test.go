@@ -148,3 +148,19 @@ func TestGivenIPv4SerializedWhenDeserializeThenFieldsSetCorrectly(t *testing.T)
 		}
 	}
 }
+
+func TestGivenTruncatedFSEIDWhenDeserializeThenReturnsError(t *testing.T) {
+	fseid, err := ie.NewFSEID(1, "2.3.4.5", "")
+	if err != nil {
+		t.Fatalf("Error creating FSEID: %v", err)
+	}
+
+	serialized := fseid.Serialize()
+
+	for _, value := range [][]byte{{}, serialized[:5], serialized[:len(serialized)-1]} {
+		_, err := ie.DeserializeFSEID(value)
+		if err == nil {
+			t.Errorf("Expected error deserializing truncated FSEID %v", value)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+type FTEID struct {
+	CHID        bool
+	CH          bool
+	V6          bool
+	V4          bool
+	TEID        uint32
+	IPv4Address []byte
+	IPv6Address []byte
+	ChooseID    uint8
+}
+
+// NewFTEID creates an F-TEID carrying a TEID and at least one of an IPv4 or IPv6 address.
+func NewFTEID(teid uint32, ipv4Address string, ipv6Address string) (FTEID, error) {
+	var v4 bool
+	var v6 bool
+	var ipv4Bytes []byte
+	var ipv6Bytes []byte
+
+	if ipv4Address == "" && ipv6Address == "" {
+		return FTEID{}, fmt.Errorf("at least one of IPv4 or IPv6 address must be provided")
+	}
+
+	if ipv4Address != "" {
+		ipv4Bytes = net.ParseIP(ipv4Address).To4()
+		if ipv4Bytes == nil {
+			return FTEID{}, fmt.Errorf("invalid IPv4 address")
+		}
+		v4 = true
+	}
+
+	if ipv6Address != "" {
+		ip := net.ParseIP(ipv6Address)
+		if ip == nil || ip.To4() != nil {
+			return FTEID{}, fmt.Errorf("invalid IPv6 address")
+		}
+		ipv6Bytes = ip.To16()
+		v6 = true
+	}
+
+	return FTEID{
+		V4:          v4,
+		V6:          v6,
+		TEID:        teid,
+		IPv4Address: ipv4Bytes,
+		IPv6Address: ipv6Bytes,
+	}, nil
+}
+
+// NewChooseFTEID creates an F-TEID asking the UP function to allocate the TEID and addresses.
+// When chid is set, the same F-TEID is allocated for every PDR sharing the Choose ID.
+func NewChooseFTEID(chooseV4 bool, chooseV6 bool, chid bool, chooseID uint8) (FTEID, error) {
+	if !chooseV4 && !chooseV6 {
+		return FTEID{}, fmt.Errorf("at least one of IPv4 or IPv6 must be chosen")
+	}
+
+	if !chid && chooseID != 0 {
+		return FTEID{}, fmt.Errorf("cannot provide Choose ID without setting the CHID flag")
+	}
+
+	return FTEID{
+		CHID:     chid,
+		CH:       true,
+		V6:       chooseV6,
+		V4:       chooseV4,
+		ChooseID: chooseID,
+	}, nil
+}
+
+func (fteid FTEID) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	// Octet 5: Spare (4 bits), CHID (bit 4), CH (bit 3), V6 (bit 2), V4 (bit 1)
+	var octet5 byte
+	if fteid.CHID {
+		octet5 |= 1 << 3
+	}
+	if fteid.CH {
+		octet5 |= 1 << 2
+	}
+	if fteid.V6 {
+		octet5 |= 1 << 1
+	}
+	if fteid.V4 {
+		octet5 |= 1
+	}
+	buf.WriteByte(octet5)
+
+	if !fteid.CH {
+		// Octets 6 to 9: TEID
+		binary.Write(buf, binary.BigEndian, fteid.TEID)
+
+		// Octets m to (m+3): IPv4 address
+		if fteid.V4 {
+			buf.Write(fteid.IPv4Address)
+		}
+
+		// Octets p to (p+15): IPv6 address
+		if fteid.V6 {
+			buf.Write(fteid.IPv6Address)
+		}
+	}
+
+	// Octet q: Choose ID
+	if fteid.CHID {
+		buf.WriteByte(fteid.ChooseID)
+	}
+
+	return buf.Bytes()
+}
+
+func (fteid FTEID) GetType() IEType {
+	return FTEIDIEType
+}
+
+func DeserializeFTEID(ieValue []byte) (FTEID, error) {
+	if len(ieValue) < 1 {
+		return FTEID{}, fmt.Errorf("invalid length for FTEID: got %d bytes, expected at least 1", len(ieValue))
+	}
+
+	fteid := FTEID{}
+
+	octet5 := ieValue[0]
+	fteid.CHID = octet5&(1<<3) > 0
+	fteid.CH = octet5&(1<<2) > 0
+	fteid.V6 = octet5&(1<<1) > 0
+	fteid.V4 = octet5&1 > 0
+
+	if fteid.CHID && !fteid.CH {
+		return FTEID{}, fmt.Errorf("invalid flags for FTEID: CHID is set but CH is not")
+	}
+
+	if !fteid.V4 && !fteid.V6 {
+		return FTEID{}, fmt.Errorf("invalid flags for FTEID: neither V4 nor V6 is set")
+	}
+
+	index := 1
+
+	if !fteid.CH {
+		if len(ieValue[index:]) < 4 {
+			return FTEID{}, fmt.Errorf("invalid length for TEID")
+		}
+		fteid.TEID = binary.BigEndian.Uint32(ieValue[index : index+4])
+		index += 4
+
+		if fteid.V4 {
+			if len(ieValue[index:]) < 4 {
+				return FTEID{}, fmt.Errorf("invalid length for IPv4 address")
+			}
+			fteid.IPv4Address = ieValue[index : index+4]
+			index += 4
+		}
+
+		if fteid.V6 {
+			if len(ieValue[index:]) < 16 {
+				return FTEID{}, fmt.Errorf("invalid length for IPv6 address")
+			}
+			fteid.IPv6Address = ieValue[index : index+16]
+			index += 16
+		}
+	}
+
+	if fteid.CHID {
+		if len(ieValue[index:]) < 1 {
+			return FTEID{}, fmt.Errorf("invalid length for Choose ID")
+		}
+		fteid.ChooseID = ieValue[index]
+	}
+
+	return fteid, nil
+}
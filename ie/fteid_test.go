@@ -0,0 +1,146 @@
+package ie_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenIPv4AndIPv6AddressWhenNewFTEIDThenFieldsSetCorrectly(t *testing.T) {
+	teid := uint32(0x12345678)
+
+	fteid, err := ie.NewFTEID(teid, "1.2.3.4", "2001:db8::1")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !fteid.V4 || !fteid.V6 {
+		t.Errorf("Expected V4 and V6 true, got %v and %v", fteid.V4, fteid.V6)
+	}
+
+	if fteid.CH || fteid.CHID {
+		t.Errorf("Expected CH and CHID false, got %v and %v", fteid.CH, fteid.CHID)
+	}
+
+	if fteid.TEID != teid {
+		t.Errorf("Expected TEID %d, got %d", teid, fteid.TEID)
+	}
+
+	if !net.IP(fteid.IPv4Address).Equal(net.ParseIP("1.2.3.4")) {
+		t.Errorf("Expected IPv4 address 1.2.3.4, got %v", net.IP(fteid.IPv4Address))
+	}
+
+	if !net.IP(fteid.IPv6Address).Equal(net.ParseIP("2001:db8::1")) {
+		t.Errorf("Expected IPv6 address 2001:db8::1, got %v", net.IP(fteid.IPv6Address))
+	}
+}
+
+func TestGivenNoAddressWhenNewFTEIDThenErrorReturned(t *testing.T) {
+	_, err := ie.NewFTEID(1, "", "")
+
+	if err == nil {
+		t.Fatalf("Expected error, got nil")
+	}
+}
+
+func TestGivenInvalidAddressWhenNewFTEIDThenErrorReturned(t *testing.T) {
+	_, err := ie.NewFTEID(1, "2001:db8::1", "")
+
+	if err == nil {
+		t.Fatalf("Expected error, got nil")
+	}
+}
+
+func TestGivenNoIPVersionChosenWhenNewChooseFTEIDThenErrorReturned(t *testing.T) {
+	_, err := ie.NewChooseFTEID(false, false, false, 0)
+
+	if err == nil {
+		t.Fatalf("Expected error, got nil")
+	}
+}
+
+func TestGivenChooseIDWithoutCHIDWhenNewChooseFTEIDThenErrorReturned(t *testing.T) {
+	_, err := ie.NewChooseFTEID(true, false, false, 3)
+
+	if err == nil {
+		t.Fatalf("Expected error, got nil")
+	}
+}
+
+func TestGivenFTEIDSerializedWhenDeserializeThenFieldsSetCorrectly(t *testing.T) {
+	fteid, err := ie.NewFTEID(42, "1.2.3.4", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	serialized := fteid.Serialize()
+
+	if len(serialized) != 9 {
+		t.Fatalf("Expected 9 bytes, got %d", len(serialized))
+	}
+
+	deserialized, err := ie.DeserializeFTEID(serialized)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !deserialized.V4 || deserialized.V6 || deserialized.CH || deserialized.CHID {
+		t.Errorf("Expected only V4 flag set, got %v", deserialized)
+	}
+
+	if deserialized.TEID != 42 {
+		t.Errorf("Expected TEID 42, got %d", deserialized.TEID)
+	}
+
+	if !net.IP(deserialized.IPv4Address).Equal(net.ParseIP("1.2.3.4")) {
+		t.Errorf("Expected IPv4 address 1.2.3.4, got %v", net.IP(deserialized.IPv4Address))
+	}
+}
+
+func TestGivenChooseFTEIDSerializedWhenDeserializeThenFieldsSetCorrectly(t *testing.T) {
+	fteid, err := ie.NewChooseFTEID(true, true, true, 7)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	serialized := fteid.Serialize()
+
+	if len(serialized) != 2 {
+		t.Fatalf("Expected 2 bytes, got %d", len(serialized))
+	}
+
+	deserialized, err := ie.DeserializeFTEID(serialized)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !deserialized.CH || !deserialized.CHID || !deserialized.V4 || !deserialized.V6 {
+		t.Errorf("Expected CH, CHID, V4 and V6 set, got %v", deserialized)
+	}
+
+	if deserialized.TEID != 0 || deserialized.IPv4Address != nil || deserialized.IPv6Address != nil {
+		t.Errorf("Expected no TEID or addresses, got %v", deserialized)
+	}
+
+	if deserialized.ChooseID != 7 {
+		t.Errorf("Expected Choose ID 7, got %d", deserialized.ChooseID)
+	}
+}
+
+func TestGivenCHIDWithoutCHWhenDeserializeFTEIDThenErrorReturned(t *testing.T) {
+	_, err := ie.DeserializeFTEID([]byte{0x09, 0, 0, 0, 1, 1, 2, 3, 4, 1})
+
+	if err == nil {
+		t.Fatalf("Expected error, got nil")
+	}
+}
+
+func TestGivenNoIPVersionWhenDeserializeFTEIDThenErrorReturned(t *testing.T) {
+	_, err := ie.DeserializeFTEID([]byte{0x00, 0, 0, 0, 1})
+
+	if err == nil {
+		t.Fatalf("Expected error, got nil")
+	}
+}
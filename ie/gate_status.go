@@ -0,0 +1,58 @@
+package ie
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type GateStatusValue int
+
+const (
+	GateOpen GateStatusValue = iota
+	GateClosed
+)
+
+type GateStatus struct {
+	ULGate GateStatusValue
+	DLGate GateStatusValue
+}
+
+func NewGateStatus(ulGate GateStatusValue, dlGate GateStatusValue) (GateStatus, error) {
+	if ulGate != GateOpen && ulGate != GateClosed {
+		return GateStatus{}, fmt.Errorf("invalid value for UL Gate: got %d, want 0-1", ulGate)
+	}
+
+	if dlGate != GateOpen && dlGate != GateClosed {
+		return GateStatus{}, fmt.Errorf("invalid value for DL Gate: got %d, want 0-1", dlGate)
+	}
+
+	return GateStatus{
+		ULGate: ulGate,
+		DLGate: dlGate,
+	}, nil
+}
+
+func (gateStatus GateStatus) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	// Octet 5: Spare (4 bits), UL Gate (2 bits), DL Gate (2 bits)
+	octet5 := byte(gateStatus.ULGate&0x03)<<2 | byte(gateStatus.DLGate&0x03)
+	buf.WriteByte(octet5)
+
+	return buf.Bytes()
+}
+
+func (gateStatus GateStatus) GetType() IEType {
+	return GateStatusIEType
+}
+
+func DeserializeGateStatus(ieValue []byte) (GateStatus, error) {
+	if len(ieValue) != 1 {
+		return GateStatus{}, fmt.Errorf("invalid length for GateStatus: got %d bytes, want 1", len(ieValue))
+	}
+
+	return GateStatus{
+		ULGate: GateStatusValue((ieValue[0] >> 2) & 0x03),
+		DLGate: GateStatusValue(ieValue[0] & 0x03),
+	}, nil
+}
@@ -0,0 +1,59 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenCorrectValuesWhenNewGateStatusThenFieldsSetCorrectly(t *testing.T) {
+	gateStatus, err := ie.NewGateStatus(ie.GateOpen, ie.GateClosed)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if gateStatus.ULGate != ie.GateOpen {
+		t.Errorf("Expected UL Gate %d, got %d", ie.GateOpen, gateStatus.ULGate)
+	}
+
+	if gateStatus.DLGate != ie.GateClosed {
+		t.Errorf("Expected DL Gate %d, got %d", ie.GateClosed, gateStatus.DLGate)
+	}
+}
+
+func TestGivenInvalidValueWhenNewGateStatusThenErrorReturned(t *testing.T) {
+	_, err := ie.NewGateStatus(ie.GateOpen, ie.GateStatusValue(2))
+
+	if err == nil {
+		t.Fatalf("Expected error, got nil")
+	}
+}
+
+func TestGivenGateStatusSerializedWhenDeserializeThenFieldsSetCorrectly(t *testing.T) {
+	gateStatus, err := ie.NewGateStatus(ie.GateClosed, ie.GateOpen)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	serialized := gateStatus.Serialize()
+
+	if len(serialized) != 1 {
+		t.Fatalf("Expected 1 byte, got %d", len(serialized))
+	}
+
+	if serialized[0] != 0x04 {
+		t.Errorf("Expected serialized value 0x04, got %#x", serialized[0])
+	}
+
+	deserialized, err := ie.DeserializeGateStatus(serialized)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if deserialized != gateStatus {
+		t.Errorf("Expected %v, got %v", gateStatus, deserialized)
+	}
+}
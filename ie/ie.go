@@ -8,23 +8,47 @@ import (
 type IEType uint16
 
 const (
-	CreatePDRIEType          IEType = 1
-	CreateFARIEType          IEType = 3
-	PDIIEType                IEType = 17
-	CauseIEType              IEType = 19
-	SourceInterfaceIEType    IEType = 20
-	PrecedenceIEType         IEType = 29
-	ReportTypeIEType         IEType = 39
-	UPFunctionFeaturesIEType IEType = 43
-	ApplyActionIEType        IEType = 44
-	PDRIDIEType              IEType = 56
-	FSEIDIEType              IEType = 57
-	NodeIDIEType             IEType = 60
-	UEIPAddressIEType        IEType = 93
-	RecoveryTimeStampIEType  IEType = 96
-	NodeReportTypeIEType     IEType = 101
-	FARIDIEType              IEType = 108
-	SourceIPAddressIEType    IEType = 192
+	CreatePDRIEType                      IEType = 1
+	PDIIEType                            IEType = 2
+	CreateFARIEType                      IEType = 3
+	CreateURRIEType                      IEType = 6
+	CreateQERIEType                      IEType = 7
+	CreatedPDRIEType                     IEType = 8
+	UpdatePDRIEType                      IEType = 9
+	UpdateFARIEType                      IEType = 10
+	UpdateURRIEType                      IEType = 13
+	UpdateQERIEType                      IEType = 14
+	RemovePDRIEType                      IEType = 15
+	RemoveFARIEType                      IEType = 16
+	RemoveURRIEType                      IEType = 17
+	RemoveQERIEType                      IEType = 18
+	CauseIEType                          IEType = 19
+	SourceInterfaceIEType                IEType = 20
+	FTEIDIEType                          IEType = 21
+	GateStatusIEType                     IEType = 25
+	PrecedenceIEType                     IEType = 29
+	ReportingTriggersIEType              IEType = 37
+	ReportTypeIEType                     IEType = 39
+	OffendingIEIEType                    IEType = 40
+	UPFunctionFeaturesIEType             IEType = 43
+	ApplyActionIEType                    IEType = 44
+	PDRIDIEType                          IEType = 56
+	FSEIDIEType                          IEType = 57
+	NodeIDIEType                         IEType = 60
+	MeasurementMethodIEType              IEType = 62
+	URRIDIEType                          IEType = 81
+	CreateBARIEType                      IEType = 85
+	UpdateBARIEType                      IEType = 86
+	RemoveBARIEType                      IEType = 87
+	BARIDIEType                          IEType = 88
+	UEIPAddressIEType                    IEType = 93
+	RecoveryTimeStampIEType              IEType = 96
+	NodeReportTypeIEType                 IEType = 101
+	FARIDIEType                          IEType = 108
+	QERIDIEType                          IEType = 109
+	FailedRuleIDIEType                   IEType = 114
+	SuggestedBufferingPacketsCountIEType IEType = 140
+	SourceIPAddressIEType                IEType = 192
 )
 
 type InformationElement interface {
@@ -105,6 +129,54 @@ func DeserializeInformationElements(payload []byte) ([]InformationElement, error
 			ie, err = DeserializeReportType(ieValue)
 		case UEIPAddressIEType:
 			ie, err = DeserializeUEIPAddress(ieValue)
+		case FTEIDIEType:
+			ie, err = DeserializeFTEID(ieValue)
+		case URRIDIEType:
+			ie, err = DeserializeURRID(ieValue)
+		case QERIDIEType:
+			ie, err = DeserializeQERID(ieValue)
+		case GateStatusIEType:
+			ie, err = DeserializeGateStatus(ieValue)
+		case MeasurementMethodIEType:
+			ie, err = DeserializeMeasurementMethod(ieValue)
+		case ReportingTriggersIEType:
+			ie, err = DeserializeReportingTriggers(ieValue)
+		case CreateURRIEType:
+			ie, err = DeserializeCreateURR(ieValue)
+		case CreateQERIEType:
+			ie, err = DeserializeCreateQER(ieValue)
+		case UpdatePDRIEType:
+			ie, err = DeserializeUpdatePDR(ieValue)
+		case UpdateFARIEType:
+			ie, err = DeserializeUpdateFAR(ieValue)
+		case UpdateURRIEType:
+			ie, err = DeserializeUpdateURR(ieValue)
+		case UpdateQERIEType:
+			ie, err = DeserializeUpdateQER(ieValue)
+		case RemovePDRIEType:
+			ie, err = DeserializeRemovePDR(ieValue)
+		case RemoveFARIEType:
+			ie, err = DeserializeRemoveFAR(ieValue)
+		case RemoveURRIEType:
+			ie, err = DeserializeRemoveURR(ieValue)
+		case RemoveQERIEType:
+			ie, err = DeserializeRemoveQER(ieValue)
+		case CreatedPDRIEType:
+			ie, err = DeserializeCreatedPDR(ieValue)
+		case OffendingIEIEType:
+			ie, err = DeserializeOffendingIE(ieValue)
+		case FailedRuleIDIEType:
+			ie, err = DeserializeFailedRuleID(ieValue)
+		case BARIDIEType:
+			ie, err = DeserializeBARID(ieValue)
+		case SuggestedBufferingPacketsCountIEType:
+			ie, err = DeserializeSuggestedBufferingPacketsCount(ieValue)
+		case CreateBARIEType:
+			ie, err = DeserializeCreateBAR(ieValue)
+		case UpdateBARIEType:
+			ie, err = DeserializeUpdateBAR(ieValue)
+		case RemoveBARIEType:
+			ie, err = DeserializeRemoveBAR(ieValue)
 		default:
 			err = fmt.Errorf("unknown IE type %d", header.Type)
 		}
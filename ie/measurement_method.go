@@ -0,0 +1,55 @@
+package ie
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type MeasurementMethod struct {
+	EVENT bool
+	VOLUM bool
+	DURAT bool
+}
+
+func NewMeasurementMethod(event bool, volum bool, durat bool) (MeasurementMethod, error) {
+	return MeasurementMethod{
+		EVENT: event,
+		VOLUM: volum,
+		DURAT: durat,
+	}, nil
+}
+
+func (measurementMethod MeasurementMethod) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	// Octet 5: Spare (5 bits), EVENT (bit 3), VOLUM (bit 2), DURAT (bit 1)
+	var octet5 byte
+	if measurementMethod.EVENT {
+		octet5 |= 1 << 2
+	}
+	if measurementMethod.VOLUM {
+		octet5 |= 1 << 1
+	}
+	if measurementMethod.DURAT {
+		octet5 |= 1
+	}
+	buf.WriteByte(octet5)
+
+	return buf.Bytes()
+}
+
+func (measurementMethod MeasurementMethod) GetType() IEType {
+	return MeasurementMethodIEType
+}
+
+func DeserializeMeasurementMethod(ieValue []byte) (MeasurementMethod, error) {
+	if len(ieValue) < 1 {
+		return MeasurementMethod{}, fmt.Errorf("invalid length for MeasurementMethod: got %d bytes, expected at least 1", len(ieValue))
+	}
+
+	return MeasurementMethod{
+		EVENT: ieValue[0]&0x04 != 0,
+		VOLUM: ieValue[0]&0x02 != 0,
+		DURAT: ieValue[0]&0x01 != 0,
+	}, nil
+}
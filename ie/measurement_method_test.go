@@ -0,0 +1,47 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenCorrectValuesWhenNewMeasurementMethodThenFieldsSetCorrectly(t *testing.T) {
+	measurementMethod, err := ie.NewMeasurementMethod(false, true, true)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if measurementMethod.EVENT {
+		t.Errorf("Expected EVENT false, got %v", measurementMethod.EVENT)
+	}
+
+	if !measurementMethod.VOLUM {
+		t.Errorf("Expected VOLUM true, got %v", measurementMethod.VOLUM)
+	}
+
+	if !measurementMethod.DURAT {
+		t.Errorf("Expected DURAT true, got %v", measurementMethod.DURAT)
+	}
+}
+
+func TestGivenMeasurementMethodSerializedWhenDeserializeThenFieldsSetCorrectly(t *testing.T) {
+	measurementMethod, err := ie.NewMeasurementMethod(true, false, true)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	serialized := measurementMethod.Serialize()
+
+	deserialized, err := ie.DeserializeMeasurementMethod(serialized)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if deserialized != measurementMethod {
+		t.Errorf("Expected %v, got %v", measurementMethod, deserialized)
+	}
+}
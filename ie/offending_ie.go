@@ -0,0 +1,40 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type OffendingIE struct {
+	TypeOfOffendingIE IEType
+}
+
+func NewOffendingIE(typeOfOffendingIE IEType) (OffendingIE, error) {
+	return OffendingIE{
+		TypeOfOffendingIE: typeOfOffendingIE,
+	}, nil
+}
+
+func (offendingIE OffendingIE) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	// Octets 5 to 6: Type of the offending IE
+	binary.Write(buf, binary.BigEndian, uint16(offendingIE.TypeOfOffendingIE))
+
+	return buf.Bytes()
+}
+
+func (offendingIE OffendingIE) GetType() IEType {
+	return OffendingIEIEType
+}
+
+func DeserializeOffendingIE(ieValue []byte) (OffendingIE, error) {
+	if len(ieValue) != 2 {
+		return OffendingIE{}, fmt.Errorf("invalid length for OffendingIE: got %d bytes, want 2", len(ieValue))
+	}
+
+	return OffendingIE{
+		TypeOfOffendingIE: IEType(binary.BigEndian.Uint16(ieValue)),
+	}, nil
+}
@@ -0,0 +1,27 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenOffendingIESerializedWhenDeserializeThenFieldsSetCorrectly(t *testing.T) {
+	offendingIE, err := ie.NewOffendingIE(ie.NodeIDIEType)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	serialized := offendingIE.Serialize()
+
+	deserialized, err := ie.DeserializeOffendingIE(serialized)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if deserialized.TypeOfOffendingIE != ie.NodeIDIEType {
+		t.Errorf("Expected TypeOfOffendingIE %d, got %d", ie.NodeIDIEType, deserialized.TypeOfOffendingIE)
+	}
+}
@@ -8,10 +8,11 @@ import (
 
 type PDI struct {
 	SourceInterface SourceInterface // Mandatory
-	UEIPAddress     UEIPAddress     // Optional
+	LocalFTEID      *FTEID          // Optional
+	UEIPAddress     *UEIPAddress    // Optional
 }
 
-func NewPDI(sourceInterface SourceInterface, ueIPAddress UEIPAddress) (PDI, error) {
+func NewPDI(sourceInterface SourceInterface, ueIPAddress *UEIPAddress) (PDI, error) {
 	return PDI{
 		SourceInterface: sourceInterface,
 		UEIPAddress:     ueIPAddress,
@@ -36,7 +37,14 @@ func (pdi PDI) Serialize() []byte {
 }
 
 func (pdi PDI) GetIEs() []InformationElement {
-	return []InformationElement{pdi.SourceInterface, pdi.UEIPAddress}
+	ies := []InformationElement{pdi.SourceInterface}
+	if pdi.LocalFTEID != nil {
+		ies = append(ies, *pdi.LocalFTEID)
+	}
+	if pdi.UEIPAddress != nil {
+		ies = append(ies, *pdi.UEIPAddress)
+	}
+	return ies
 }
 
 func (pdi PDI) GetType() IEType {
@@ -48,10 +56,7 @@ func DeserializePDI(ieValue []byte) (PDI, error) {
 		return PDI{}, fmt.Errorf("invalid length for PDI: got %d bytes, want at least 1", len(ieValue))
 	}
 
-	pdi := PDI{
-		SourceInterface: SourceInterface{},
-		UEIPAddress:     UEIPAddress{},
-	}
+	pdi := PDI{}
 
 	index := 0
 	for index < len(ieValue) {
@@ -75,12 +80,18 @@ func DeserializePDI(ieValue []byte) (PDI, error) {
 				return PDI{}, fmt.Errorf("failed to deserialize Source Interface: %v", err)
 			}
 			pdi.SourceInterface = sourceInterface
+		case FTEIDIEType:
+			localFTEID, err := DeserializeFTEID(currentIEValue)
+			if err != nil {
+				return PDI{}, fmt.Errorf("failed to deserialize Local F-TEID: %v", err)
+			}
+			pdi.LocalFTEID = &localFTEID
 		case UEIPAddressIEType:
 			ueIPAddress, err := DeserializeUEIPAddress(currentIEValue)
 			if err != nil {
 				return PDI{}, fmt.Errorf("failed to deserialize UE IP Address: %v", err)
 			}
-			pdi.UEIPAddress = ueIPAddress
+			pdi.UEIPAddress = &ueIPAddress
 		}
 		index += 4 + int(currentIELength)
 	}
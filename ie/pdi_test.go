@@ -1,6 +1,7 @@
 package ie_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/dot-5g/pfcp/ie"
@@ -18,7 +19,7 @@ func TestGivenCorrectPDIWhenNewPDIThenFieldsSetCorrectly(t *testing.T) {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
-	pdi, err := ie.NewPDI(sourceInterface, ueIPAddress)
+	pdi, err := ie.NewPDI(sourceInterface, &ueIPAddress)
 
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
@@ -73,6 +74,20 @@ func TestGivenCorrectPDIWhenNewPDIThenFieldsSetCorrectly(t *testing.T) {
 	}
 }
 
+func TestGivenPDIWhenSerializeThenEncodedWithPDIIEType(t *testing.T) {
+	sourceInterface, err := ie.NewSourceInterface(0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	serialized := ie.Serialize(ie.PDI{SourceInterface: sourceInterface})
+
+	// TS 29.244 Table 8.1.2-1: PDI is IE type 2, 17 being Remove URR.
+	if !bytes.Equal(serialized[:2], []byte{0x00, 0x02}) {
+		t.Errorf("Expected IE type 0x0002, got %x", serialized[:2])
+	}
+}
+
 func TestGivenPDISerializedWhenDeserializeThenFieldsSetCorrectly(t *testing.T) {
 	sourceInterface, err := ie.NewSourceInterface(4)
 	if err != nil {
@@ -86,7 +101,7 @@ func TestGivenPDISerializedWhenDeserializeThenFieldsSetCorrectly(t *testing.T) {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
-	pdi, err := ie.NewPDI(sourceInterface, ueIPAddress)
+	pdi, err := ie.NewPDI(sourceInterface, &ueIPAddress)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -147,3 +162,68 @@ func TestGivenPDISerializedWhenDeserializeThenFieldsSetCorrectly(t *testing.T) {
 		t.Errorf("Expected UEIPAddress IPv6PrefixLength %d, got %d", prefixLength, deserializedPDI.UEIPAddress.IPv6PrefixLength)
 	}
 }
+
+func TestGivenPDIWithLocalFTEIDSerializedWhenDeserializeThenFieldsSetCorrectly(t *testing.T) {
+	sourceInterface, err := ie.NewSourceInterface(0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ueIPAddress, err := ie.NewUEIPAddress("10.0.0.1", "", ie.SourceDestination{}, 0, 0, false, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	pdi, err := ie.NewPDI(sourceInterface, &ueIPAddress)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	localFTEID, err := ie.NewChooseFTEID(true, false, true, 5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	pdi.LocalFTEID = &localFTEID
+
+	deserializedPDI, err := ie.DeserializePDI(pdi.Serialize())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if deserializedPDI.LocalFTEID == nil {
+		t.Fatalf("Expected LocalFTEID, got nil")
+	}
+
+	if !deserializedPDI.LocalFTEID.CH || !deserializedPDI.LocalFTEID.CHID || deserializedPDI.LocalFTEID.ChooseID != 5 {
+		t.Errorf("Expected LocalFTEID %v, got %v", localFTEID, *deserializedPDI.LocalFTEID)
+	}
+
+	if !deserializedPDI.UEIPAddress.V4 {
+		t.Errorf("Expected UEIPAddress V4 true, got %v", deserializedPDI.UEIPAddress.V4)
+	}
+}
+
+func TestGivenPDIWithoutUEIPAddressWhenDeserializeThenUEIPAddressNil(t *testing.T) {
+	sourceInterface, err := ie.NewSourceInterface(0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	pdi, err := ie.NewPDI(sourceInterface, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(pdi.GetIEs()) != 1 {
+		t.Errorf("Expected only the Source Interface IE, got %v", pdi.GetIEs())
+	}
+
+	deserializedPDI, err := ie.DeserializePDI(pdi.Serialize())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if deserializedPDI.UEIPAddress != nil {
+		t.Errorf("Expected UEIPAddress nil, got %v", *deserializedPDI.UEIPAddress)
+	}
+}
@@ -0,0 +1,40 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type QERID struct {
+	Value uint32
+}
+
+func NewQERID(value uint32) (QERID, error) {
+	return QERID{
+		Value: value,
+	}, nil
+}
+
+func (qerID QERID) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	// Octets 5 to 8: Value
+	binary.Write(buf, binary.BigEndian, qerID.Value)
+
+	return buf.Bytes()
+}
+
+func (qerID QERID) GetType() IEType {
+	return QERIDIEType
+}
+
+func DeserializeQERID(ieValue []byte) (QERID, error) {
+	if len(ieValue) != 4 {
+		return QERID{}, fmt.Errorf("invalid length for QERID: got %d bytes, want 4", len(ieValue))
+	}
+
+	return QERID{
+		Value: binary.BigEndian.Uint32(ieValue),
+	}, nil
+}
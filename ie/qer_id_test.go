@@ -0,0 +1,42 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenCorrectValueWhenNewQERIDThenFieldsSetCorrectly(t *testing.T) {
+	value := uint32(1234)
+
+	qerID, err := ie.NewQERID(value)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if qerID.Value != value {
+		t.Errorf("Expected Value %d, got %d", value, qerID.Value)
+	}
+}
+
+func TestGivenQERIDSerializedWhenDeserializeThenFieldsSetCorrectly(t *testing.T) {
+	value := uint32(1234)
+	qerID, err := ie.NewQERID(value)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	qerIDSerialized := qerID.Serialize()
+
+	deserializedQERID, err := ie.DeserializeQERID(qerIDSerialized)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if deserializedQERID.Value != value {
+		t.Errorf("Expected Value %d, got %d", value, deserializedQERID.Value)
+	}
+}
@@ -0,0 +1,75 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type RemoveBAR struct {
+	BARID BARID // Mandatory
+}
+
+func NewRemoveBAR(barID BARID) (RemoveBAR, error) {
+	return RemoveBAR{
+		BARID: barID,
+	}, nil
+}
+
+func (removeBAR RemoveBAR) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	for _, ie := range removeBAR.GetIEs() {
+		serializedIE := ie.Serialize()
+		ieLength := uint16(len(serializedIE))
+		ieHeader := Header{
+			Type:   ie.GetType(),
+			Length: ieLength,
+		}
+		buf.Write(ieHeader.Serialize())
+		buf.Write(serializedIE)
+	}
+
+	return buf.Bytes()
+}
+
+func (removeBAR RemoveBAR) GetIEs() []InformationElement {
+	return []InformationElement{removeBAR.BARID}
+}
+
+func (removeBAR RemoveBAR) GetType() IEType {
+	return RemoveBARIEType
+}
+
+func DeserializeRemoveBAR(value []byte) (RemoveBAR, error) {
+	removeBAR := RemoveBAR{}
+
+	index := 0
+	for index < len(value) {
+		if index+4 > len(value) {
+			return RemoveBAR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEType := binary.BigEndian.Uint16(value[index : index+2])
+		currentIELength := binary.BigEndian.Uint16(value[index+2 : index+4])
+
+		if index+4+int(currentIELength) > len(value) {
+			return RemoveBAR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEValue := value[index+4 : index+4+int(currentIELength)]
+
+		switch IEType(currentIEType) {
+		case BARIDIEType:
+			barID, err := DeserializeBARID(currentIEValue)
+			if err != nil {
+				return RemoveBAR{}, fmt.Errorf("failed to deserialize BAR ID: %v", err)
+			}
+			removeBAR.BARID = barID
+		}
+
+		index += 4 + int(currentIELength)
+	}
+
+	return removeBAR, nil
+}
@@ -0,0 +1,75 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type RemoveFAR struct {
+	FARID FARID // Mandatory
+}
+
+func NewRemoveFAR(farID FARID) (RemoveFAR, error) {
+	return RemoveFAR{
+		FARID: farID,
+	}, nil
+}
+
+func (removeFAR RemoveFAR) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	for _, ie := range removeFAR.GetIEs() {
+		serializedIE := ie.Serialize()
+		ieLength := uint16(len(serializedIE))
+		ieHeader := Header{
+			Type:   ie.GetType(),
+			Length: ieLength,
+		}
+		buf.Write(ieHeader.Serialize())
+		buf.Write(serializedIE)
+	}
+
+	return buf.Bytes()
+}
+
+func (removeFAR RemoveFAR) GetIEs() []InformationElement {
+	return []InformationElement{removeFAR.FARID}
+}
+
+func (removeFAR RemoveFAR) GetType() IEType {
+	return RemoveFARIEType
+}
+
+func DeserializeRemoveFAR(value []byte) (RemoveFAR, error) {
+	removeFAR := RemoveFAR{}
+
+	index := 0
+	for index < len(value) {
+		if index+4 > len(value) {
+			return RemoveFAR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEType := binary.BigEndian.Uint16(value[index : index+2])
+		currentIELength := binary.BigEndian.Uint16(value[index+2 : index+4])
+
+		if index+4+int(currentIELength) > len(value) {
+			return RemoveFAR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEValue := value[index+4 : index+4+int(currentIELength)]
+
+		switch IEType(currentIEType) {
+		case FARIDIEType:
+			farID, err := DeserializeFARID(currentIEValue)
+			if err != nil {
+				return RemoveFAR{}, fmt.Errorf("failed to deserialize FAR ID: %v", err)
+			}
+			removeFAR.FARID = farID
+		}
+
+		index += 4 + int(currentIELength)
+	}
+
+	return removeFAR, nil
+}
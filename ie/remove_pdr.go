@@ -0,0 +1,75 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type RemovePDR struct {
+	PDRID PDRID // Mandatory
+}
+
+func NewRemovePDR(pdrID PDRID) (RemovePDR, error) {
+	return RemovePDR{
+		PDRID: pdrID,
+	}, nil
+}
+
+func (removePDR RemovePDR) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	for _, ie := range removePDR.GetIEs() {
+		serializedIE := ie.Serialize()
+		ieLength := uint16(len(serializedIE))
+		ieHeader := Header{
+			Type:   ie.GetType(),
+			Length: ieLength,
+		}
+		buf.Write(ieHeader.Serialize())
+		buf.Write(serializedIE)
+	}
+
+	return buf.Bytes()
+}
+
+func (removePDR RemovePDR) GetIEs() []InformationElement {
+	return []InformationElement{removePDR.PDRID}
+}
+
+func (removePDR RemovePDR) GetType() IEType {
+	return RemovePDRIEType
+}
+
+func DeserializeRemovePDR(value []byte) (RemovePDR, error) {
+	removePDR := RemovePDR{}
+
+	index := 0
+	for index < len(value) {
+		if index+4 > len(value) {
+			return RemovePDR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEType := binary.BigEndian.Uint16(value[index : index+2])
+		currentIELength := binary.BigEndian.Uint16(value[index+2 : index+4])
+
+		if index+4+int(currentIELength) > len(value) {
+			return RemovePDR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEValue := value[index+4 : index+4+int(currentIELength)]
+
+		switch IEType(currentIEType) {
+		case PDRIDIEType:
+			pdrID, err := DeserializePDRID(currentIEValue)
+			if err != nil {
+				return RemovePDR{}, fmt.Errorf("failed to deserialize PDR ID: %v", err)
+			}
+			removePDR.PDRID = pdrID
+		}
+
+		index += 4 + int(currentIELength)
+	}
+
+	return removePDR, nil
+}
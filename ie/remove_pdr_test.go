@@ -0,0 +1,30 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenSerializedWhenDeserializeRemovePDRThenFieldsSetCorrectly(t *testing.T) {
+	pdrID, err := ie.NewPDRID(12)
+	if err != nil {
+		t.Fatalf("Error creating PDRID: %v", err)
+	}
+
+	removePDR, err := ie.NewRemovePDR(pdrID)
+	if err != nil {
+		t.Fatalf("Error creating RemovePDR: %v", err)
+	}
+
+	serialized := removePDR.Serialize()
+
+	deserialized, err := ie.DeserializeRemovePDR(serialized)
+	if err != nil {
+		t.Fatalf("Error deserializing RemovePDR: %v", err)
+	}
+
+	if deserialized.PDRID != pdrID {
+		t.Errorf("Expected PDRID %v, got %v", pdrID, deserialized.PDRID)
+	}
+}
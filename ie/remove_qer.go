@@ -0,0 +1,75 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type RemoveQER struct {
+	QERID QERID // Mandatory
+}
+
+func NewRemoveQER(qerID QERID) (RemoveQER, error) {
+	return RemoveQER{
+		QERID: qerID,
+	}, nil
+}
+
+func (removeQER RemoveQER) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	for _, ie := range removeQER.GetIEs() {
+		serializedIE := ie.Serialize()
+		ieLength := uint16(len(serializedIE))
+		ieHeader := Header{
+			Type:   ie.GetType(),
+			Length: ieLength,
+		}
+		buf.Write(ieHeader.Serialize())
+		buf.Write(serializedIE)
+	}
+
+	return buf.Bytes()
+}
+
+func (removeQER RemoveQER) GetIEs() []InformationElement {
+	return []InformationElement{removeQER.QERID}
+}
+
+func (removeQER RemoveQER) GetType() IEType {
+	return RemoveQERIEType
+}
+
+func DeserializeRemoveQER(value []byte) (RemoveQER, error) {
+	removeQER := RemoveQER{}
+
+	index := 0
+	for index < len(value) {
+		if index+4 > len(value) {
+			return RemoveQER{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEType := binary.BigEndian.Uint16(value[index : index+2])
+		currentIELength := binary.BigEndian.Uint16(value[index+2 : index+4])
+
+		if index+4+int(currentIELength) > len(value) {
+			return RemoveQER{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEValue := value[index+4 : index+4+int(currentIELength)]
+
+		switch IEType(currentIEType) {
+		case QERIDIEType:
+			qerID, err := DeserializeQERID(currentIEValue)
+			if err != nil {
+				return RemoveQER{}, fmt.Errorf("failed to deserialize QER ID: %v", err)
+			}
+			removeQER.QERID = qerID
+		}
+
+		index += 4 + int(currentIELength)
+	}
+
+	return removeQER, nil
+}
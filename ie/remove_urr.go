@@ -0,0 +1,75 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type RemoveURR struct {
+	URRID URRID // Mandatory
+}
+
+func NewRemoveURR(urrID URRID) (RemoveURR, error) {
+	return RemoveURR{
+		URRID: urrID,
+	}, nil
+}
+
+func (removeURR RemoveURR) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	for _, ie := range removeURR.GetIEs() {
+		serializedIE := ie.Serialize()
+		ieLength := uint16(len(serializedIE))
+		ieHeader := Header{
+			Type:   ie.GetType(),
+			Length: ieLength,
+		}
+		buf.Write(ieHeader.Serialize())
+		buf.Write(serializedIE)
+	}
+
+	return buf.Bytes()
+}
+
+func (removeURR RemoveURR) GetIEs() []InformationElement {
+	return []InformationElement{removeURR.URRID}
+}
+
+func (removeURR RemoveURR) GetType() IEType {
+	return RemoveURRIEType
+}
+
+func DeserializeRemoveURR(value []byte) (RemoveURR, error) {
+	removeURR := RemoveURR{}
+
+	index := 0
+	for index < len(value) {
+		if index+4 > len(value) {
+			return RemoveURR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEType := binary.BigEndian.Uint16(value[index : index+2])
+		currentIELength := binary.BigEndian.Uint16(value[index+2 : index+4])
+
+		if index+4+int(currentIELength) > len(value) {
+			return RemoveURR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEValue := value[index+4 : index+4+int(currentIELength)]
+
+		switch IEType(currentIEType) {
+		case URRIDIEType:
+			urrID, err := DeserializeURRID(currentIEValue)
+			if err != nil {
+				return RemoveURR{}, fmt.Errorf("failed to deserialize URR ID: %v", err)
+			}
+			removeURR.URRID = urrID
+		}
+
+		index += 4 + int(currentIELength)
+	}
+
+	return removeURR, nil
+}
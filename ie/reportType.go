@@ -2,6 +2,7 @@ package ie
 
 import (
 	"bytes"
+	"fmt"
 )
 
 type Report int
@@ -45,6 +46,10 @@ func (reportType ReportType) GetType() IEType {
 }
 
 func DeserializeReportType(ieValue []byte) (ReportType, error) {
+	if len(ieValue) < 1 {
+		return ReportType{}, fmt.Errorf("invalid length for ReportType: got %d bytes, expected at least 1", len(ieValue))
+	}
+
 	var reports []Report
 	reportsByte := ieValue[0]
 
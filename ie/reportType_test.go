@@ -56,3 +56,11 @@ func TestGivenSerializedWhenDeserializeReportTypeThenFieldsSetCorrectly(t *testi
 		t.Errorf("Expected report %d, got %d", ie.SESR, deserializedReportType.Reports[1])
 	}
 }
+
+func TestGivenEmptyReportTypeWhenDeserializeThenReturnsError(t *testing.T) {
+	_, err := ie.DeserializeReportType([]byte{})
+
+	if err == nil {
+		t.Errorf("Expected error deserializing empty ReportType")
+	}
+}
@@ -0,0 +1,90 @@
+package ie
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type ReportingTrigger int
+
+const (
+	PERIO ReportingTrigger = iota
+	VOLTH
+	TIMTH
+	QUHTI
+	START
+	STOPT
+	DROTH
+	LIUSA
+	VOLQU
+	TIMQU
+	ENVCL
+	MACAR
+	EVETH
+	EVEQU
+	IPMJL
+	QUVTI
+	REEMR
+	UPINT
+	NumberOfReportingTriggers
+)
+
+type ReportingTriggers struct {
+	Triggers []ReportingTrigger
+}
+
+func NewReportingTriggers(triggers []ReportingTrigger) (ReportingTriggers, error) {
+	for _, trigger := range triggers {
+		if trigger < 0 || trigger >= NumberOfReportingTriggers {
+			return ReportingTriggers{}, fmt.Errorf("invalid value for ReportingTrigger: %d", trigger)
+		}
+	}
+
+	return ReportingTriggers{
+		Triggers: triggers,
+	}, nil
+}
+
+func (reportingTriggers ReportingTriggers) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	// Octet 5: LIUSA, DROTH, STOPT, START, QUHTI, TIMTH, VOLTH, PERIO
+	// Octet 6: QUVTI, IPMJL, EVEQU, EVETH, MACAR, ENVCL, TIMQU, VOLQU
+	// Octet 7: Spare (6 bits), UPINT, REEMR
+	triggerBytes := make([]byte, 3)
+	for _, trigger := range reportingTriggers.Triggers {
+		if trigger >= 0 && trigger < NumberOfReportingTriggers {
+			triggerBytes[trigger/8] |= 1 << (trigger % 8)
+		}
+	}
+	buf.Write(triggerBytes)
+
+	return buf.Bytes()
+}
+
+func (reportingTriggers ReportingTriggers) GetType() IEType {
+	return ReportingTriggersIEType
+}
+
+func DeserializeReportingTriggers(ieValue []byte) (ReportingTriggers, error) {
+	if len(ieValue) < 2 {
+		return ReportingTriggers{}, fmt.Errorf("invalid length for ReportingTriggers: got %d bytes, expected at least 2", len(ieValue))
+	}
+
+	var triggers []ReportingTrigger
+	for i, triggerByte := range ieValue {
+		for j := 0; j < 8; j++ {
+			trigger := ReportingTrigger(i*8 + j)
+			if trigger >= NumberOfReportingTriggers {
+				break
+			}
+			if triggerByte&(1<<j) != 0 {
+				triggers = append(triggers, trigger)
+			}
+		}
+	}
+
+	return ReportingTriggers{
+		Triggers: triggers,
+	}, nil
+}
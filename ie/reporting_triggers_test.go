@@ -0,0 +1,69 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenCorrectValuesWhenNewReportingTriggersThenFieldsSetCorrectly(t *testing.T) {
+	triggers := []ie.ReportingTrigger{ie.PERIO, ie.VOLTH}
+
+	reportingTriggers, err := ie.NewReportingTriggers(triggers)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(reportingTriggers.Triggers) != 2 {
+		t.Fatalf("Expected 2 triggers, got %d", len(reportingTriggers.Triggers))
+	}
+
+	if reportingTriggers.Triggers[0] != ie.PERIO {
+		t.Errorf("Expected trigger %d, got %d", ie.PERIO, reportingTriggers.Triggers[0])
+	}
+
+	if reportingTriggers.Triggers[1] != ie.VOLTH {
+		t.Errorf("Expected trigger %d, got %d", ie.VOLTH, reportingTriggers.Triggers[1])
+	}
+}
+
+func TestGivenInvalidTriggerWhenNewReportingTriggersThenErrorReturned(t *testing.T) {
+	_, err := ie.NewReportingTriggers([]ie.ReportingTrigger{ie.NumberOfReportingTriggers})
+
+	if err == nil {
+		t.Fatalf("Expected error, got nil")
+	}
+}
+
+func TestGivenReportingTriggersSerializedWhenDeserializeThenFieldsSetCorrectly(t *testing.T) {
+	triggers := []ie.ReportingTrigger{ie.PERIO, ie.LIUSA, ie.VOLQU, ie.UPINT}
+
+	reportingTriggers, err := ie.NewReportingTriggers(triggers)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	serialized := reportingTriggers.Serialize()
+
+	if len(serialized) != 3 {
+		t.Fatalf("Expected 3 bytes, got %d", len(serialized))
+	}
+
+	deserialized, err := ie.DeserializeReportingTriggers(serialized)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(deserialized.Triggers) != len(triggers) {
+		t.Fatalf("Expected %d triggers, got %d", len(triggers), len(deserialized.Triggers))
+	}
+
+	for i, trigger := range triggers {
+		if deserialized.Triggers[i] != trigger {
+			t.Errorf("Expected trigger %d, got %d", trigger, deserialized.Triggers[i])
+		}
+	}
+}
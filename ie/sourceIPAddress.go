@@ -2,6 +2,7 @@ package ie
 
 import (
 	"bytes"
+	"fmt"
 	"net"
 )
 
@@ -95,10 +96,17 @@ func DeserializeSourceIPAddress(ieValue []byte) (SourceIPAddress, error) {
 	var ipv6Address []byte
 	var maskPrefixLength uint8
 
+	if len(ieValue) < 1 {
+		return SourceIPAddress{}, fmt.Errorf("invalid length for SourceIPAddress: got %d bytes, expected at least 1", len(ieValue))
+	}
+
 	if ieValue[0]&0x80 == 0x80 {
 		mpl = true
 	}
 	if ieValue[0]&0x40 == 0x40 {
+		if err := checkSourceIPAddressLength(ieValue, net.IPv4len, mpl); err != nil {
+			return SourceIPAddress{}, err
+		}
 		v4 = true
 		ipv4Address = ieValue[1:5]
 		if mpl {
@@ -106,6 +114,9 @@ func DeserializeSourceIPAddress(ieValue []byte) (SourceIPAddress, error) {
 		}
 	}
 	if ieValue[0]&0x20 == 0x20 {
+		if err := checkSourceIPAddressLength(ieValue, net.IPv6len, mpl); err != nil {
+			return SourceIPAddress{}, err
+		}
 		v6 = true
 		ipv6Address = ieValue[1:17]
 		if mpl {
@@ -122,3 +133,14 @@ func DeserializeSourceIPAddress(ieValue []byte) (SourceIPAddress, error) {
 		MaskPrefixLength: maskPrefixLength,
 	}, nil
 }
+
+func checkSourceIPAddressLength(ieValue []byte, addressLength int, mpl bool) error {
+	expectedLength := 1 + addressLength
+	if mpl {
+		expectedLength++
+	}
+	if len(ieValue) < expectedLength {
+		return fmt.Errorf("invalid length for SourceIPAddress: got %d bytes, expected at least %d", len(ieValue), expectedLength)
+	}
+	return nil
+}
@@ -97,3 +97,19 @@ func TestGivenSerializedAddressWhenDeserializeThenFieldsSetCorrectly(t *testing.
 		}
 	}
 }
+
+func TestGivenTruncatedSourceIPAddressWhenDeserializeThenReturnsError(t *testing.T) {
+	sourceIPAddress, err := ie.NewSourceIPAddress("2.3.4.5/24", "")
+	if err != nil {
+		t.Fatalf("Error creating SourceIPAddress: %v", err)
+	}
+
+	serialized := sourceIPAddress.Serialize()
+
+	for _, value := range [][]byte{{}, serialized[:3], serialized[:len(serialized)-1]} {
+		_, err := ie.DeserializeSourceIPAddress(value)
+		if err == nil {
+			t.Errorf("Expected error deserializing truncated SourceIPAddress %v", value)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package ie
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type SuggestedBufferingPacketsCount struct {
+	PacketCount uint8
+}
+
+func NewSuggestedBufferingPacketsCount(packetCount uint8) (SuggestedBufferingPacketsCount, error) {
+	return SuggestedBufferingPacketsCount{
+		PacketCount: packetCount,
+	}, nil
+}
+
+func (count SuggestedBufferingPacketsCount) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	// Octet 5: Packet count value
+	buf.WriteByte(count.PacketCount)
+
+	return buf.Bytes()
+}
+
+func (count SuggestedBufferingPacketsCount) GetType() IEType {
+	return SuggestedBufferingPacketsCountIEType
+}
+
+func DeserializeSuggestedBufferingPacketsCount(ieValue []byte) (SuggestedBufferingPacketsCount, error) {
+	if len(ieValue) != 1 {
+		return SuggestedBufferingPacketsCount{}, fmt.Errorf("invalid length for SuggestedBufferingPacketsCount: got %d bytes, want 1", len(ieValue))
+	}
+
+	return SuggestedBufferingPacketsCount{
+		PacketCount: ieValue[0],
+	}, nil
+}
@@ -0,0 +1,87 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type UpdateBAR struct {
+	BARID                          BARID                           // Mandatory
+	SuggestedBufferingPacketsCount *SuggestedBufferingPacketsCount // Conditional
+}
+
+func NewUpdateBAR(barID BARID, suggestedBufferingPacketsCount *SuggestedBufferingPacketsCount) (UpdateBAR, error) {
+	return UpdateBAR{
+		BARID:                          barID,
+		SuggestedBufferingPacketsCount: suggestedBufferingPacketsCount,
+	}, nil
+}
+
+func (updateBAR UpdateBAR) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	for _, ie := range updateBAR.GetIEs() {
+		serializedIE := ie.Serialize()
+		ieLength := uint16(len(serializedIE))
+		ieHeader := Header{
+			Type:   ie.GetType(),
+			Length: ieLength,
+		}
+		buf.Write(ieHeader.Serialize())
+		buf.Write(serializedIE)
+	}
+
+	return buf.Bytes()
+}
+
+func (updateBAR UpdateBAR) GetIEs() []InformationElement {
+	ies := []InformationElement{updateBAR.BARID}
+	if updateBAR.SuggestedBufferingPacketsCount != nil {
+		ies = append(ies, *updateBAR.SuggestedBufferingPacketsCount)
+	}
+	return ies
+}
+
+func (updateBAR UpdateBAR) GetType() IEType {
+	return UpdateBARIEType
+}
+
+func DeserializeUpdateBAR(value []byte) (UpdateBAR, error) {
+	updateBAR := UpdateBAR{}
+
+	index := 0
+	for index < len(value) {
+		if index+4 > len(value) {
+			return UpdateBAR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEType := binary.BigEndian.Uint16(value[index : index+2])
+		currentIELength := binary.BigEndian.Uint16(value[index+2 : index+4])
+
+		if index+4+int(currentIELength) > len(value) {
+			return UpdateBAR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEValue := value[index+4 : index+4+int(currentIELength)]
+
+		switch IEType(currentIEType) {
+		case BARIDIEType:
+			barID, err := DeserializeBARID(currentIEValue)
+			if err != nil {
+				return UpdateBAR{}, fmt.Errorf("failed to deserialize BAR ID: %v", err)
+			}
+			updateBAR.BARID = barID
+		case SuggestedBufferingPacketsCountIEType:
+			count, err := DeserializeSuggestedBufferingPacketsCount(currentIEValue)
+			if err != nil {
+				return UpdateBAR{}, fmt.Errorf("failed to deserialize Suggested Buffering Packets Count: %v", err)
+			}
+			updateBAR.SuggestedBufferingPacketsCount = &count
+		}
+
+		index += 4 + int(currentIELength)
+	}
+
+	return updateBAR, nil
+}
@@ -0,0 +1,32 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenUpdateBARWithoutPacketsCountWhenDeserializeThenPacketsCountNil(t *testing.T) {
+	barID, err := ie.NewBARID(2)
+	if err != nil {
+		t.Fatalf("Error creating BARID: %v", err)
+	}
+
+	updateBAR, err := ie.NewUpdateBAR(barID, nil)
+	if err != nil {
+		t.Fatalf("Error creating UpdateBAR: %v", err)
+	}
+
+	deserialized, err := ie.DeserializeUpdateBAR(updateBAR.Serialize())
+	if err != nil {
+		t.Fatalf("Error deserializing UpdateBAR: %v", err)
+	}
+
+	if deserialized.BARID != barID {
+		t.Errorf("Expected BARID %v, got %v", barID, deserialized.BARID)
+	}
+
+	if deserialized.SuggestedBufferingPacketsCount != nil {
+		t.Errorf("Expected no SuggestedBufferingPacketsCount, got %v", *deserialized.SuggestedBufferingPacketsCount)
+	}
+}
@@ -0,0 +1,87 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type UpdateFAR struct {
+	FARID       FARID        // Mandatory
+	ApplyAction *ApplyAction // Conditional
+}
+
+func NewUpdateFAR(farID FARID, applyAction *ApplyAction) (UpdateFAR, error) {
+	return UpdateFAR{
+		FARID:       farID,
+		ApplyAction: applyAction,
+	}, nil
+}
+
+func (updateFAR UpdateFAR) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	for _, ie := range updateFAR.GetIEs() {
+		serializedIE := ie.Serialize()
+		ieLength := uint16(len(serializedIE))
+		ieHeader := Header{
+			Type:   ie.GetType(),
+			Length: ieLength,
+		}
+		buf.Write(ieHeader.Serialize())
+		buf.Write(serializedIE)
+	}
+
+	return buf.Bytes()
+}
+
+func (updateFAR UpdateFAR) GetIEs() []InformationElement {
+	ies := []InformationElement{updateFAR.FARID}
+	if updateFAR.ApplyAction != nil {
+		ies = append(ies, *updateFAR.ApplyAction)
+	}
+	return ies
+}
+
+func (updateFAR UpdateFAR) GetType() IEType {
+	return UpdateFARIEType
+}
+
+func DeserializeUpdateFAR(value []byte) (UpdateFAR, error) {
+	updateFAR := UpdateFAR{}
+
+	index := 0
+	for index < len(value) {
+		if index+4 > len(value) {
+			return UpdateFAR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEType := binary.BigEndian.Uint16(value[index : index+2])
+		currentIELength := binary.BigEndian.Uint16(value[index+2 : index+4])
+
+		if index+4+int(currentIELength) > len(value) {
+			return UpdateFAR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEValue := value[index+4 : index+4+int(currentIELength)]
+
+		switch IEType(currentIEType) {
+		case FARIDIEType:
+			farID, err := DeserializeFARID(currentIEValue)
+			if err != nil {
+				return UpdateFAR{}, fmt.Errorf("failed to deserialize FAR ID: %v", err)
+			}
+			updateFAR.FARID = farID
+		case ApplyActionIEType:
+			applyAction, err := DeserializeApplyAction(currentIEValue)
+			if err != nil {
+				return UpdateFAR{}, fmt.Errorf("failed to deserialize Apply Action: %v", err)
+			}
+			updateFAR.ApplyAction = &applyAction
+		}
+
+		index += 4 + int(currentIELength)
+	}
+
+	return updateFAR, nil
+}
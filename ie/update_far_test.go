@@ -0,0 +1,39 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenSerializedWhenDeserializeUpdateFARThenFieldsSetCorrectly(t *testing.T) {
+	farID, err := ie.NewFarID(5)
+	if err != nil {
+		t.Fatalf("Error creating FARID: %v", err)
+	}
+
+	applyAction, err := ie.NewApplyAction(ie.BUFF, []ie.ApplyActionExtraFlag{ie.NOCP})
+	if err != nil {
+		t.Fatalf("Error creating ApplyAction: %v", err)
+	}
+
+	updateFAR, err := ie.NewUpdateFAR(farID, &applyAction)
+	if err != nil {
+		t.Fatalf("Error creating UpdateFAR: %v", err)
+	}
+
+	serialized := updateFAR.Serialize()
+
+	deserialized, err := ie.DeserializeUpdateFAR(serialized)
+	if err != nil {
+		t.Fatalf("Error deserializing UpdateFAR: %v", err)
+	}
+
+	if deserialized.FARID != farID {
+		t.Errorf("Expected FARID %v, got %v", farID, deserialized.FARID)
+	}
+
+	if deserialized.ApplyAction == nil || *deserialized.ApplyAction != applyAction {
+		t.Errorf("Expected ApplyAction %v, got %v", applyAction, deserialized.ApplyAction)
+	}
+}
@@ -0,0 +1,129 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type UpdatePDR struct {
+	PDRID      PDRID       // Mandatory
+	Precedence *Precedence // Conditional
+	PDI        *PDI        // Conditional
+	FARID      *FARID      // Conditional
+	URRID      []URRID     // Conditional
+	QERID      []QERID     // Conditional
+}
+
+func NewUpdatePDR(pdrID PDRID, precedence *Precedence, pdi *PDI, farID *FARID) (UpdatePDR, error) {
+	return UpdatePDR{
+		PDRID:      pdrID,
+		Precedence: precedence,
+		PDI:        pdi,
+		FARID:      farID,
+	}, nil
+}
+
+func (updatePDR UpdatePDR) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	for _, ie := range updatePDR.GetIEs() {
+		serializedIE := ie.Serialize()
+		ieLength := uint16(len(serializedIE))
+		ieHeader := Header{
+			Type:   ie.GetType(),
+			Length: ieLength,
+		}
+		buf.Write(ieHeader.Serialize())
+		buf.Write(serializedIE)
+	}
+
+	return buf.Bytes()
+}
+
+func (updatePDR UpdatePDR) GetIEs() []InformationElement {
+	ies := []InformationElement{updatePDR.PDRID}
+	if updatePDR.Precedence != nil {
+		ies = append(ies, *updatePDR.Precedence)
+	}
+	if updatePDR.PDI != nil {
+		ies = append(ies, *updatePDR.PDI)
+	}
+	if updatePDR.FARID != nil {
+		ies = append(ies, *updatePDR.FARID)
+	}
+	for _, urrID := range updatePDR.URRID {
+		ies = append(ies, urrID)
+	}
+	for _, qerID := range updatePDR.QERID {
+		ies = append(ies, qerID)
+	}
+	return ies
+}
+
+func (updatePDR UpdatePDR) GetType() IEType {
+	return UpdatePDRIEType
+}
+
+func DeserializeUpdatePDR(value []byte) (UpdatePDR, error) {
+	updatePDR := UpdatePDR{}
+
+	index := 0
+	for index < len(value) {
+		if index+4 > len(value) {
+			return UpdatePDR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEType := binary.BigEndian.Uint16(value[index : index+2])
+		currentIELength := binary.BigEndian.Uint16(value[index+2 : index+4])
+
+		if index+4+int(currentIELength) > len(value) {
+			return UpdatePDR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEValue := value[index+4 : index+4+int(currentIELength)]
+
+		switch IEType(currentIEType) {
+		case PDRIDIEType:
+			pdrID, err := DeserializePDRID(currentIEValue)
+			if err != nil {
+				return UpdatePDR{}, fmt.Errorf("failed to deserialize PDR ID: %v", err)
+			}
+			updatePDR.PDRID = pdrID
+		case PrecedenceIEType:
+			precedence, err := DeserializePrecedence(currentIEValue)
+			if err != nil {
+				return UpdatePDR{}, fmt.Errorf("failed to deserialize Precedence: %v", err)
+			}
+			updatePDR.Precedence = &precedence
+		case PDIIEType:
+			pdi, err := DeserializePDI(currentIEValue)
+			if err != nil {
+				return UpdatePDR{}, fmt.Errorf("failed to deserialize PDI: %v", err)
+			}
+			updatePDR.PDI = &pdi
+		case FARIDIEType:
+			farID, err := DeserializeFARID(currentIEValue)
+			if err != nil {
+				return UpdatePDR{}, fmt.Errorf("failed to deserialize FAR ID: %v", err)
+			}
+			updatePDR.FARID = &farID
+		case URRIDIEType:
+			urrID, err := DeserializeURRID(currentIEValue)
+			if err != nil {
+				return UpdatePDR{}, fmt.Errorf("failed to deserialize URR ID: %v", err)
+			}
+			updatePDR.URRID = append(updatePDR.URRID, urrID)
+		case QERIDIEType:
+			qerID, err := DeserializeQERID(currentIEValue)
+			if err != nil {
+				return UpdatePDR{}, fmt.Errorf("failed to deserialize QER ID: %v", err)
+			}
+			updatePDR.QERID = append(updatePDR.QERID, qerID)
+		}
+
+		index += 4 + int(currentIELength)
+	}
+
+	return updatePDR, nil
+}
@@ -0,0 +1,110 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenOnlyPDRIDWhenSerializeUpdatePDRThenConditionalIEsOmitted(t *testing.T) {
+	pdrID, err := ie.NewPDRID(3)
+	if err != nil {
+		t.Fatalf("Error creating PDRID: %v", err)
+	}
+
+	updatePDR, err := ie.NewUpdatePDR(pdrID, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Error creating UpdatePDR: %v", err)
+	}
+
+	serialized := updatePDR.Serialize()
+
+	if len(serialized) != ie.HeaderLength+2 {
+		t.Fatalf("Expected %d bytes, got %d", ie.HeaderLength+2, len(serialized))
+	}
+
+	deserialized, err := ie.DeserializeUpdatePDR(serialized)
+	if err != nil {
+		t.Fatalf("Error deserializing UpdatePDR: %v", err)
+	}
+
+	if deserialized.PDRID != pdrID {
+		t.Errorf("Expected PDRID %v, got %v", pdrID, deserialized.PDRID)
+	}
+
+	if deserialized.Precedence != nil {
+		t.Errorf("Expected no Precedence, got %v", deserialized.Precedence)
+	}
+
+	if deserialized.PDI != nil {
+		t.Errorf("Expected no PDI, got %v", deserialized.PDI)
+	}
+
+	if deserialized.FARID != nil {
+		t.Errorf("Expected no FARID, got %v", deserialized.FARID)
+	}
+}
+
+func TestGivenSerializedWhenDeserializeUpdatePDRThenFieldsSetCorrectly(t *testing.T) {
+	pdrID, err := ie.NewPDRID(3)
+	if err != nil {
+		t.Fatalf("Error creating PDRID: %v", err)
+	}
+
+	precedence, err := ie.NewPrecedence(200)
+	if err != nil {
+		t.Fatalf("Error creating Precedence: %v", err)
+	}
+
+	sourceInterface, err := ie.NewSourceInterface(1)
+	if err != nil {
+		t.Fatalf("Error creating SourceInterface: %v", err)
+	}
+
+	ueIPAddress, err := ie.NewUEIPAddress("1.2.3.4", "", ie.SourceDestination{}, 0, 0, false, false)
+	if err != nil {
+		t.Fatalf("Error creating UEIPAddress: %v", err)
+	}
+
+	pdi, err := ie.NewPDI(sourceInterface, &ueIPAddress)
+	if err != nil {
+		t.Fatalf("Error creating PDI: %v", err)
+	}
+
+	farID, err := ie.NewFarID(4)
+	if err != nil {
+		t.Fatalf("Error creating FARID: %v", err)
+	}
+
+	updatePDR, err := ie.NewUpdatePDR(pdrID, &precedence, &pdi, &farID)
+	if err != nil {
+		t.Fatalf("Error creating UpdatePDR: %v", err)
+	}
+
+	serialized := updatePDR.Serialize()
+
+	deserialized, err := ie.DeserializeUpdatePDR(serialized)
+	if err != nil {
+		t.Fatalf("Error deserializing UpdatePDR: %v", err)
+	}
+
+	if deserialized.PDRID != pdrID {
+		t.Errorf("Expected PDRID %v, got %v", pdrID, deserialized.PDRID)
+	}
+
+	if deserialized.Precedence == nil || *deserialized.Precedence != precedence {
+		t.Errorf("Expected Precedence %v, got %v", precedence, deserialized.Precedence)
+	}
+
+	if deserialized.PDI == nil {
+		t.Fatalf("Expected PDI, got nil")
+	}
+
+	if deserialized.PDI.SourceInterface != sourceInterface {
+		t.Errorf("Expected PDI SourceInterface %v, got %v", sourceInterface, deserialized.PDI.SourceInterface)
+	}
+
+	if deserialized.FARID == nil || *deserialized.FARID != farID {
+		t.Errorf("Expected FARID %v, got %v", farID, deserialized.FARID)
+	}
+}
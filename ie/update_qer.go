@@ -0,0 +1,87 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type UpdateQER struct {
+	QERID      QERID       // Mandatory
+	GateStatus *GateStatus // Conditional
+}
+
+func NewUpdateQER(qerID QERID, gateStatus *GateStatus) (UpdateQER, error) {
+	return UpdateQER{
+		QERID:      qerID,
+		GateStatus: gateStatus,
+	}, nil
+}
+
+func (updateQER UpdateQER) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	for _, ie := range updateQER.GetIEs() {
+		serializedIE := ie.Serialize()
+		ieLength := uint16(len(serializedIE))
+		ieHeader := Header{
+			Type:   ie.GetType(),
+			Length: ieLength,
+		}
+		buf.Write(ieHeader.Serialize())
+		buf.Write(serializedIE)
+	}
+
+	return buf.Bytes()
+}
+
+func (updateQER UpdateQER) GetIEs() []InformationElement {
+	ies := []InformationElement{updateQER.QERID}
+	if updateQER.GateStatus != nil {
+		ies = append(ies, *updateQER.GateStatus)
+	}
+	return ies
+}
+
+func (updateQER UpdateQER) GetType() IEType {
+	return UpdateQERIEType
+}
+
+func DeserializeUpdateQER(value []byte) (UpdateQER, error) {
+	updateQER := UpdateQER{}
+
+	index := 0
+	for index < len(value) {
+		if index+4 > len(value) {
+			return UpdateQER{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEType := binary.BigEndian.Uint16(value[index : index+2])
+		currentIELength := binary.BigEndian.Uint16(value[index+2 : index+4])
+
+		if index+4+int(currentIELength) > len(value) {
+			return UpdateQER{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEValue := value[index+4 : index+4+int(currentIELength)]
+
+		switch IEType(currentIEType) {
+		case QERIDIEType:
+			qerID, err := DeserializeQERID(currentIEValue)
+			if err != nil {
+				return UpdateQER{}, fmt.Errorf("failed to deserialize QER ID: %v", err)
+			}
+			updateQER.QERID = qerID
+		case GateStatusIEType:
+			gateStatus, err := DeserializeGateStatus(currentIEValue)
+			if err != nil {
+				return UpdateQER{}, fmt.Errorf("failed to deserialize Gate Status: %v", err)
+			}
+			updateQER.GateStatus = &gateStatus
+		}
+
+		index += 4 + int(currentIELength)
+	}
+
+	return updateQER, nil
+}
@@ -0,0 +1,39 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenSerializedWhenDeserializeUpdateQERThenFieldsSetCorrectly(t *testing.T) {
+	qerID, err := ie.NewQERID(4)
+	if err != nil {
+		t.Fatalf("Error creating QERID: %v", err)
+	}
+
+	gateStatus, err := ie.NewGateStatus(ie.GateClosed, ie.GateClosed)
+	if err != nil {
+		t.Fatalf("Error creating GateStatus: %v", err)
+	}
+
+	updateQER, err := ie.NewUpdateQER(qerID, &gateStatus)
+	if err != nil {
+		t.Fatalf("Error creating UpdateQER: %v", err)
+	}
+
+	serialized := updateQER.Serialize()
+
+	deserialized, err := ie.DeserializeUpdateQER(serialized)
+	if err != nil {
+		t.Fatalf("Error deserializing UpdateQER: %v", err)
+	}
+
+	if deserialized.QERID != qerID {
+		t.Errorf("Expected QERID %v, got %v", qerID, deserialized.QERID)
+	}
+
+	if deserialized.GateStatus == nil || *deserialized.GateStatus != gateStatus {
+		t.Errorf("Expected GateStatus %v, got %v", gateStatus, deserialized.GateStatus)
+	}
+}
@@ -0,0 +1,98 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type UpdateURR struct {
+	URRID             URRID              // Mandatory
+	MeasurementMethod *MeasurementMethod // Conditional
+	ReportingTriggers *ReportingTriggers // Conditional
+}
+
+func NewUpdateURR(urrID URRID, measurementMethod *MeasurementMethod, reportingTriggers *ReportingTriggers) (UpdateURR, error) {
+	return UpdateURR{
+		URRID:             urrID,
+		MeasurementMethod: measurementMethod,
+		ReportingTriggers: reportingTriggers,
+	}, nil
+}
+
+func (updateURR UpdateURR) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	for _, ie := range updateURR.GetIEs() {
+		serializedIE := ie.Serialize()
+		ieLength := uint16(len(serializedIE))
+		ieHeader := Header{
+			Type:   ie.GetType(),
+			Length: ieLength,
+		}
+		buf.Write(ieHeader.Serialize())
+		buf.Write(serializedIE)
+	}
+
+	return buf.Bytes()
+}
+
+func (updateURR UpdateURR) GetIEs() []InformationElement {
+	ies := []InformationElement{updateURR.URRID}
+	if updateURR.MeasurementMethod != nil {
+		ies = append(ies, *updateURR.MeasurementMethod)
+	}
+	if updateURR.ReportingTriggers != nil {
+		ies = append(ies, *updateURR.ReportingTriggers)
+	}
+	return ies
+}
+
+func (updateURR UpdateURR) GetType() IEType {
+	return UpdateURRIEType
+}
+
+func DeserializeUpdateURR(value []byte) (UpdateURR, error) {
+	updateURR := UpdateURR{}
+
+	index := 0
+	for index < len(value) {
+		if index+4 > len(value) {
+			return UpdateURR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEType := binary.BigEndian.Uint16(value[index : index+2])
+		currentIELength := binary.BigEndian.Uint16(value[index+2 : index+4])
+
+		if index+4+int(currentIELength) > len(value) {
+			return UpdateURR{}, fmt.Errorf("slice bounds out of range")
+		}
+
+		currentIEValue := value[index+4 : index+4+int(currentIELength)]
+
+		switch IEType(currentIEType) {
+		case URRIDIEType:
+			urrID, err := DeserializeURRID(currentIEValue)
+			if err != nil {
+				return UpdateURR{}, fmt.Errorf("failed to deserialize URR ID: %v", err)
+			}
+			updateURR.URRID = urrID
+		case MeasurementMethodIEType:
+			measurementMethod, err := DeserializeMeasurementMethod(currentIEValue)
+			if err != nil {
+				return UpdateURR{}, fmt.Errorf("failed to deserialize Measurement Method: %v", err)
+			}
+			updateURR.MeasurementMethod = &measurementMethod
+		case ReportingTriggersIEType:
+			reportingTriggers, err := DeserializeReportingTriggers(currentIEValue)
+			if err != nil {
+				return UpdateURR{}, fmt.Errorf("failed to deserialize Reporting Triggers: %v", err)
+			}
+			updateURR.ReportingTriggers = &reportingTriggers
+		}
+
+		index += 4 + int(currentIELength)
+	}
+
+	return updateURR, nil
+}
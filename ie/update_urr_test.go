@@ -0,0 +1,47 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenSerializedWhenDeserializeUpdateURRThenFieldsSetCorrectly(t *testing.T) {
+	urrID, err := ie.NewURRID(9)
+	if err != nil {
+		t.Fatalf("Error creating URRID: %v", err)
+	}
+
+	reportingTriggers, err := ie.NewReportingTriggers([]ie.ReportingTrigger{ie.PERIO})
+	if err != nil {
+		t.Fatalf("Error creating ReportingTriggers: %v", err)
+	}
+
+	updateURR, err := ie.NewUpdateURR(urrID, nil, &reportingTriggers)
+	if err != nil {
+		t.Fatalf("Error creating UpdateURR: %v", err)
+	}
+
+	serialized := updateURR.Serialize()
+
+	deserialized, err := ie.DeserializeUpdateURR(serialized)
+	if err != nil {
+		t.Fatalf("Error deserializing UpdateURR: %v", err)
+	}
+
+	if deserialized.URRID != urrID {
+		t.Errorf("Expected URRID %v, got %v", urrID, deserialized.URRID)
+	}
+
+	if deserialized.MeasurementMethod != nil {
+		t.Errorf("Expected no MeasurementMethod, got %v", deserialized.MeasurementMethod)
+	}
+
+	if deserialized.ReportingTriggers == nil || len(deserialized.ReportingTriggers.Triggers) != 1 {
+		t.Fatalf("Expected 1 ReportingTrigger, got %v", deserialized.ReportingTriggers)
+	}
+
+	if deserialized.ReportingTriggers.Triggers[0] != ie.PERIO {
+		t.Errorf("Expected trigger %d, got %d", ie.PERIO, deserialized.ReportingTriggers.Triggers[0])
+	}
+}
@@ -0,0 +1,40 @@
+package ie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type URRID struct {
+	Value uint32
+}
+
+func NewURRID(value uint32) (URRID, error) {
+	return URRID{
+		Value: value,
+	}, nil
+}
+
+func (urrID URRID) Serialize() []byte {
+	buf := new(bytes.Buffer)
+
+	// Octets 5 to 8: Value
+	binary.Write(buf, binary.BigEndian, urrID.Value)
+
+	return buf.Bytes()
+}
+
+func (urrID URRID) GetType() IEType {
+	return URRIDIEType
+}
+
+func DeserializeURRID(ieValue []byte) (URRID, error) {
+	if len(ieValue) != 4 {
+		return URRID{}, fmt.Errorf("invalid length for URRID: got %d bytes, want 4", len(ieValue))
+	}
+
+	return URRID{
+		Value: binary.BigEndian.Uint32(ieValue),
+	}, nil
+}
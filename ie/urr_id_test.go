@@ -0,0 +1,42 @@
+package ie_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+func TestGivenCorrectValueWhenNewURRIDThenFieldsSetCorrectly(t *testing.T) {
+	value := uint32(1234)
+
+	urrID, err := ie.NewURRID(value)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if urrID.Value != value {
+		t.Errorf("Expected Value %d, got %d", value, urrID.Value)
+	}
+}
+
+func TestGivenURRIDSerializedWhenDeserializeThenFieldsSetCorrectly(t *testing.T) {
+	value := uint32(1234)
+	urrID, err := ie.NewURRID(value)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	urrIDSerialized := urrID.Serialize()
+
+	deserializedURRID, err := ie.DeserializeURRID(urrIDSerialized)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if deserializedURRID.Value != value {
+		t.Errorf("Expected Value %d, got %d", value, deserializedURRID.Value)
+	}
+}
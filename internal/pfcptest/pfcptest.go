@@ -0,0 +1,72 @@
+// Package pfcptest provides the servers and peers shared by the tests of the other packages.
+package pfcptest
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dot-5g/pfcp/messages"
+	"github.com/dot-5g/pfcp/server"
+)
+
+// StartServer runs a server listening on a random port of the loopback address until the test ends.
+func StartServer(t testing.TB, opts ...server.Option) *server.Server {
+	pfcpServer := server.New("127.0.0.1:0", opts...)
+	err := pfcpServer.Listen()
+	if err != nil {
+		t.Fatalf("Error listening: %v", err)
+	}
+	go pfcpServer.Run(context.Background())
+	t.Cleanup(pfcpServer.Close)
+	return pfcpServer
+}
+
+// NewPeer returns a UDP socket on a random port of the loopback address, closed when the test ends.
+func NewPeer(t testing.TB) net.PacketConn {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening on UDP: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// ReadMessage reads a message from conn within a second and returns its header and the IEs following it.
+func ReadMessage(t testing.TB, conn net.PacketConn) (messages.Header, []byte) {
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	buffer := make([]byte, 1024)
+	length, _, err := conn.ReadFrom(buffer)
+	if err != nil {
+		t.Fatalf("Error reading message: %v", err)
+	}
+	header, err := messages.DeserializeHeader(buffer[:length])
+	if err != nil {
+		t.Fatalf("Error deserializing header: %v", err)
+	}
+	payloadOffset := 8
+	if header.S {
+		payloadOffset = 16
+	}
+	return header, buffer[payloadOffset:length]
+}
+
+// SendRequest sends message with header from conn to the address to and returns the decoded response.
+func SendRequest(t testing.TB, conn net.PacketConn, to net.Addr, message messages.PFCPMessage, header messages.Header) (messages.Header, messages.PFCPMessage) {
+	_, err := conn.WriteTo(messages.Serialize(message, header), to)
+	if err != nil {
+		t.Fatalf("Error sending request: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	buffer := make([]byte, 1024)
+	length, _, err := conn.ReadFrom(buffer)
+	if err != nil {
+		t.Fatalf("Error reading response: %v", err)
+	}
+	responseHeader, response, err := messages.Decode(buffer[:length])
+	if err != nil {
+		t.Fatalf("Error decoding response: %v", err)
+	}
+	return responseHeader, response
+}
@@ -0,0 +1,122 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrInvalidLength is returned by Decode when the data is shorter than its header or its Message Length.
+	ErrInvalidLength = errors.New("invalid length")
+	// ErrUnknownMessageType is returned by Decode when no deserializer is registered for the message type.
+	ErrUnknownMessageType = errors.New("unknown message type")
+)
+
+// Deserializer decodes the information elements of a message, following its header.
+type Deserializer func(data []byte) (PFCPMessage, error)
+
+var (
+	registryMu    sync.RWMutex
+	registry      = make(map[MessageType]Deserializer)
+	responseTypes = make(map[MessageType]MessageType) // Keyed by request type
+	requestTypes  = make(map[MessageType]MessageType) // Keyed by response type
+)
+
+func init() {
+	Register(HeartbeatRequestMessageType, DeserializeHeartbeatRequest, AnsweredWith(HeartbeatResponseMessageType))
+	Register(HeartbeatResponseMessageType, DeserializeHeartbeatResponse)
+	Register(PFCPAssociationSetupRequestMessageType, DeserializePFCPAssociationSetupRequest, AnsweredWith(PFCPAssociationSetupResponseMessageType))
+	Register(PFCPAssociationSetupResponseMessageType, DeserializePFCPAssociationSetupResponse)
+	Register(PFCPAssociationUpdateRequestMessageType, DeserializePFCPAssociationUpdateRequest, AnsweredWith(PFCPAssociationUpdateResponseMessageType))
+	Register(PFCPAssociationUpdateResponseMessageType, DeserializePFCPAssociationUpdateResponse)
+	Register(PFCPAssociationReleaseRequestMessageType, DeserializePFCPAssociationReleaseRequest, AnsweredWith(PFCPAssociationReleaseResponseMessageType))
+	Register(PFCPAssociationReleaseResponseMessageType, DeserializePFCPAssociationReleaseResponse)
+	Register(PFCPNodeReportRequestMessageType, DeserializePFCPNodeReportRequest, AnsweredWith(PFCPNodeReportResponseMessageType))
+	Register(PFCPNodeReportResponseMessageType, DeserializePFCPNodeReportResponse)
+	Register(PFCPSessionEstablishmentRequestMessageType, DeserializePFCPSessionEstablishmentRequest, AnsweredWith(PFCPSessionEstablishmentResponseMessageType))
+	Register(PFCPSessionEstablishmentResponseMessageType, DeserializePFCPSessionEstablishmentResponse)
+	Register(PFCPSessionModificationRequestMessageType, DeserializePFCPSessionModificationRequest, AnsweredWith(PFCPSessionModificationResponseMessageType))
+	Register(PFCPSessionModificationResponseMessageType, DeserializePFCPSessionModificationResponse)
+	Register(PFCPSessionDeletionRequestMessageType, DeserializePFCPSessionDeletionRequest, AnsweredWith(PFCPSessionDeletionResponseMessageType))
+	Register(PFCPSessionDeletionResponseMessageType, DeserializePFCPSessionDeletionResponse)
+	Register(PFCPSessionReportRequestMessageType, DeserializePFCPSessionReportRequest, AnsweredWith(PFCPSessionReportResponseMessageType))
+	Register(PFCPSessionReportResponseMessageType, DeserializePFCPSessionReportResponse)
+}
+
+// RegisterOption declares properties of a message type being registered.
+type RegisterOption func(*registration)
+
+type registration struct {
+	responseType *MessageType
+}
+
+// AnsweredWith declares the messages being registered as requests answered with messages of responseType.
+func AnsweredWith(responseType MessageType) RegisterOption {
+	return func(r *registration) {
+		r.responseType = &responseType
+	}
+}
+
+// Register sets the function used by Decode to deserialize messages of messageType,
+// replacing any previous one. Applications use it to support additional message types,
+// declaring the requests with AnsweredWith so that servers answer them.
+func Register[T PFCPMessage](messageType MessageType, deserialize func(data []byte) (T, error), opts ...RegisterOption) {
+	var r registration
+	for _, opt := range opts {
+		opt(&r)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[messageType] = func(data []byte) (PFCPMessage, error) {
+		return deserialize(data)
+	}
+	if responseType, exists := responseTypes[messageType]; exists {
+		delete(requestTypes, responseType)
+		delete(responseTypes, messageType)
+	}
+	if r.responseType != nil {
+		responseTypes[messageType] = *r.responseType
+		requestTypes[*r.responseType] = messageType
+	}
+}
+
+// DeserializerFor returns the deserializer registered for messageType.
+func DeserializerFor(messageType MessageType) (Deserializer, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	deserializer, exists := registry[messageType]
+	return deserializer, exists
+}
+
+// Decode decodes a PFCP message, header included. The header is returned as long as it could be decoded,
+// even when the message type is unknown or its information elements are invalid.
+func Decode(data []byte) (Header, PFCPMessage, error) {
+	header, err := DeserializeHeader(data)
+	if err != nil {
+		return Header{}, nil, fmt.Errorf("%w: %v", ErrInvalidLength, err)
+	}
+
+	payloadOffset := 8
+	if header.S {
+		payloadOffset = 16
+	}
+
+	// The Message Length excludes the first 4 octets of the header.
+	messageEnd := int(header.MessageLength) + 4
+	if messageEnd < payloadOffset || messageEnd > len(data) {
+		return header, nil, fmt.Errorf("%w: message length %d for a %d bytes message", ErrInvalidLength, header.MessageLength, len(data))
+	}
+
+	deserialize, exists := DeserializerFor(header.MessageType)
+	if !exists {
+		return header, nil, fmt.Errorf("%w: %d", ErrUnknownMessageType, header.MessageType)
+	}
+
+	message, err := deserialize(data[payloadOffset:messageEnd])
+	if err != nil {
+		return header, nil, fmt.Errorf("failed to deserialize message type %d: %w", header.MessageType, err)
+	}
+
+	return header, message, nil
+}
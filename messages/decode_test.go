@@ -0,0 +1,147 @@
+package messages_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+)
+
+func TestGivenSerializedHeartbeatRequestWhenDecodeThenMessageReturned(t *testing.T) {
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+
+	payload := messages.Serialize(messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp}, messages.NewNodeHeader(messages.HeartbeatRequestMessageType, 23))
+
+	header, message, err := messages.Decode(payload)
+	if err != nil {
+		t.Fatalf("Error decoding message: %v", err)
+	}
+
+	if header.SequenceNumber != 23 {
+		t.Errorf("Expected sequence number 23, got %d", header.SequenceNumber)
+	}
+
+	heartbeatRequest, ok := message.(messages.HeartbeatRequest)
+	if !ok {
+		t.Fatalf("Expected HeartbeatRequest, got %T", message)
+	}
+
+	if heartbeatRequest.RecoveryTimeStamp != recoveryTimeStamp {
+		t.Errorf("Expected Recovery TimeStamp %v, got %v", recoveryTimeStamp, heartbeatRequest.RecoveryTimeStamp)
+	}
+}
+
+func TestGivenHeartbeatRequestWithoutSourceIPAddressWhenDecodeThenSourceIPAddressNil(t *testing.T) {
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+	request := messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp}
+
+	if ies := request.GetIEs(); len(ies) != 1 {
+		t.Fatalf("Expected only the Recovery Time Stamp IE, got %v", ies)
+	}
+
+	_, message, err := messages.Decode(messages.Serialize(request, messages.NewNodeHeader(messages.HeartbeatRequestMessageType, 1)))
+	if err != nil {
+		t.Fatalf("Error decoding message: %v", err)
+	}
+
+	if sourceIPAddress := message.(messages.HeartbeatRequest).SourceIPAddress; sourceIPAddress != nil {
+		t.Errorf("Expected no Source IP Address, got %v", *sourceIPAddress)
+	}
+}
+
+func TestGivenSerializedSessionMessageWhenDecodeThenSEIDReturned(t *testing.T) {
+	cause, err := ie.NewCause(ie.RequestAccepted)
+	if err != nil {
+		t.Fatalf("Error creating Cause: %v", err)
+	}
+
+	payload := messages.Serialize(messages.PFCPSessionDeletionResponse{Cause: cause}, messages.NewSessionHeader(messages.PFCPSessionDeletionResponseMessageType, 1234, 5))
+
+	header, message, err := messages.Decode(payload)
+	if err != nil {
+		t.Fatalf("Error decoding message: %v", err)
+	}
+
+	if header.SEID != 1234 {
+		t.Errorf("Expected SEID 1234, got %d", header.SEID)
+	}
+
+	deletionResponse, ok := message.(messages.PFCPSessionDeletionResponse)
+	if !ok {
+		t.Fatalf("Expected PFCPSessionDeletionResponse, got %T", message)
+	}
+
+	if deletionResponse.Cause != cause {
+		t.Errorf("Expected Cause %v, got %v", cause, deletionResponse.Cause)
+	}
+}
+
+func TestGivenTruncatedMessageWhenDecodeThenInvalidLength(t *testing.T) {
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+
+	payload := messages.Serialize(messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp}, messages.NewNodeHeader(messages.HeartbeatRequestMessageType, 23))
+
+	for _, data := range [][]byte{payload[:4], payload[:len(payload)-1]} {
+		_, _, err := messages.Decode(data)
+		if !errors.Is(err, messages.ErrInvalidLength) {
+			t.Errorf("Expected ErrInvalidLength decoding %v, got %v", data, err)
+		}
+	}
+}
+
+func TestGivenUnregisteredMessageTypeWhenDecodeThenUnknownMessageType(t *testing.T) {
+	header := messages.NewNodeHeader(messages.MessageType(200), 1)
+	header.MessageLength = 4
+
+	decodedHeader, _, err := messages.Decode(header.Serialize())
+
+	if !errors.Is(err, messages.ErrUnknownMessageType) {
+		t.Fatalf("Expected ErrUnknownMessageType, got %v", err)
+	}
+
+	if decodedHeader.MessageType != 200 {
+		t.Errorf("Expected the header to be returned, got message type %d", decodedHeader.MessageType)
+	}
+}
+
+type versionNotSupportedResponse struct{}
+
+func (msg versionNotSupportedResponse) GetIEs() []ie.InformationElement {
+	return []ie.InformationElement{}
+}
+
+func (msg versionNotSupportedResponse) GetMessageType() messages.MessageType {
+	return messages.MessageType(11)
+}
+
+func (msg versionNotSupportedResponse) GetMessageTypeString() string {
+	return "Version Not Supported Response"
+}
+
+func TestGivenRegisteredMessageTypeWhenDecodeThenCustomMessageReturned(t *testing.T) {
+	messages.Register(messages.MessageType(11), func(data []byte) (versionNotSupportedResponse, error) {
+		return versionNotSupportedResponse{}, nil
+	})
+
+	payload := messages.Serialize(versionNotSupportedResponse{}, messages.NewNodeHeader(messages.MessageType(11), 3))
+
+	_, message, err := messages.Decode(payload)
+	if err != nil {
+		t.Fatalf("Error decoding message: %v", err)
+	}
+
+	if _, ok := message.(versionNotSupportedResponse); !ok {
+		t.Errorf("Expected versionNotSupportedResponse, got %T", message)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"log/slog"
 )
 
 type Header struct {
@@ -127,3 +128,15 @@ func DeserializeHeader(data []byte) (Header, error) {
 
 	return header, nil
 }
+
+// LogValue groups the fields identifying the message in structured logs.
+func (header Header) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.Int("message_type", int(header.MessageType)),
+		slog.Uint64("sequence_number", uint64(header.SequenceNumber)),
+	}
+	if header.S {
+		attrs = append(attrs, slog.Uint64("seid", header.SEID))
+	}
+	return slog.GroupValue(attrs...)
+}
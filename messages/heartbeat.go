@@ -6,16 +6,22 @@ import (
 
 type HeartbeatRequest struct {
 	RecoveryTimeStamp ie.RecoveryTimeStamp // Mandatory
-	SourceIPAddress   ie.SourceIPAddress   // Optional
+	SourceIPAddress   *ie.SourceIPAddress  // Optional
+
+	received ieSet
 }
 
 type HeartbeatResponse struct {
 	RecoveryTimeStamp ie.RecoveryTimeStamp // Mandatory
+
+	received ieSet
 }
 
 func (msg HeartbeatRequest) GetIEs() []ie.InformationElement {
 	ies := []ie.InformationElement{msg.RecoveryTimeStamp}
-	ies = append(ies, msg.SourceIPAddress)
+	if msg.SourceIPAddress != nil {
+		ies = append(ies, *msg.SourceIPAddress)
+	}
 	return ies
 }
 
@@ -42,14 +48,14 @@ func (msg HeartbeatResponse) GetMessageTypeString() string {
 func DeserializeHeartbeatRequest(data []byte) (HeartbeatRequest, error) {
 	ies, err := ie.DeserializeInformationElements(data)
 	var recoveryTimeStamp ie.RecoveryTimeStamp
-	var sourceIPAddress ie.SourceIPAddress
+	var sourceIPAddress *ie.SourceIPAddress
 	for _, elem := range ies {
 		if tsIE, ok := elem.(ie.RecoveryTimeStamp); ok {
 			recoveryTimeStamp = tsIE
 			continue
 		}
 		if ipIE, ok := elem.(ie.SourceIPAddress); ok {
-			sourceIPAddress = ipIE
+			sourceIPAddress = &ipIE
 			continue
 		}
 	}
@@ -57,6 +63,7 @@ func DeserializeHeartbeatRequest(data []byte) (HeartbeatRequest, error) {
 	return HeartbeatRequest{
 		RecoveryTimeStamp: recoveryTimeStamp,
 		SourceIPAddress:   sourceIPAddress,
+		received:          receivedIEs(ies),
 	}, err
 }
 
@@ -72,5 +79,6 @@ func DeserializeHeartbeatResponse(data []byte) (HeartbeatResponse, error) {
 
 	return HeartbeatResponse{
 		RecoveryTimeStamp: recoveryTimeStamp,
+		received:          receivedIEs(ies),
 	}, err
 }
@@ -20,12 +20,37 @@ const (
 	PFCPNodeReportResponseMessageType           MessageType = 13
 	PFCPSessionEstablishmentRequestMessageType  MessageType = 50
 	PFCPSessionEstablishmentResponseMessageType MessageType = 51
+	PFCPSessionModificationRequestMessageType   MessageType = 52
+	PFCPSessionModificationResponseMessageType  MessageType = 53
 	PFCPSessionDeletionRequestMessageType       MessageType = 54
 	PFCPSessionDeletionResponseMessageType      MessageType = 55
 	PFCPSessionReportRequestMessageType         MessageType = 56
 	PFCPSessionReportResponseMessageType        MessageType = 57
 )
 
+// IsRequest reports whether messageType is a request that the peer answers with a response,
+// as declared with AnsweredWith when registering it.
+func (messageType MessageType) IsRequest() bool {
+	_, ok := messageType.ResponseType()
+	return ok
+}
+
+// IsResponse reports whether messageType is the response to a registered request.
+func (messageType MessageType) IsResponse() bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	_, ok := requestTypes[messageType]
+	return ok
+}
+
+// ResponseType returns the type of the response to the requests of messageType.
+func (messageType MessageType) ResponseType() (MessageType, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	responseType, ok := responseTypes[messageType]
+	return responseType, ok
+}
+
 type PFCPMessage interface {
 	GetIEs() []ie.InformationElement
 	GetMessageType() MessageType
@@ -38,7 +63,9 @@ func Serialize(message PFCPMessage, messageHeader Header) []byte {
 	for _, element := range ies {
 		payload = append(payload, ie.Serialize(element)...)
 	}
-	messageHeader.MessageLength = uint16(len(payload))
+	// The Message Length excludes the first 4 octets of the header.
+	headerLength := len(messageHeader.Serialize())
+	messageHeader.MessageLength = uint16(headerLength - 4 + len(payload))
 	headerBytes := messageHeader.Serialize()
 	return append(headerBytes, payload...)
 }
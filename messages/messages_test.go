@@ -0,0 +1,116 @@
+package messages_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+)
+
+func TestGivenRequestMessageTypeWhenIsRequestThenTrue(t *testing.T) {
+	requests := []messages.MessageType{
+		messages.HeartbeatRequestMessageType,
+		messages.PFCPAssociationSetupRequestMessageType,
+		messages.PFCPNodeReportRequestMessageType,
+		messages.PFCPSessionEstablishmentRequestMessageType,
+		messages.PFCPSessionReportRequestMessageType,
+	}
+
+	for _, messageType := range requests {
+		if !messageType.IsRequest() {
+			t.Errorf("Expected message type %d to be a request", messageType)
+		}
+		if messageType.IsResponse() {
+			t.Errorf("Expected message type %d not to be a response", messageType)
+		}
+	}
+}
+
+func TestGivenResponseMessageTypeWhenIsResponseThenTrue(t *testing.T) {
+	responses := []messages.MessageType{
+		messages.HeartbeatResponseMessageType,
+		messages.PFCPAssociationSetupResponseMessageType,
+		messages.PFCPNodeReportResponseMessageType,
+		messages.PFCPSessionEstablishmentResponseMessageType,
+		messages.PFCPSessionReportResponseMessageType,
+	}
+
+	for _, messageType := range responses {
+		if !messageType.IsResponse() {
+			t.Errorf("Expected message type %d to be a response", messageType)
+		}
+		if messageType.IsRequest() {
+			t.Errorf("Expected message type %d not to be a request", messageType)
+		}
+	}
+}
+
+func TestGivenUnknownMessageTypeWhenIsRequestOrIsResponseThenFalse(t *testing.T) {
+	messageType := messages.MessageType(11)
+
+	if messageType.IsRequest() || messageType.IsResponse() {
+		t.Errorf("Expected message type %d to be neither a request nor a response", messageType)
+	}
+}
+
+func TestGivenMessageWhenSerializeThenMessageLengthExcludesFirstFourOctets(t *testing.T) {
+	cause, err := ie.NewCause(ie.RequestAccepted)
+	if err != nil {
+		t.Fatalf("Error creating Cause: %v", err)
+	}
+
+	nodePayload := messages.Serialize(messages.PFCPSessionDeletionResponse{Cause: cause}, messages.NewNodeHeader(messages.PFCPSessionDeletionResponseMessageType, 1))
+	sessionPayload := messages.Serialize(messages.PFCPSessionDeletionResponse{Cause: cause}, messages.NewSessionHeader(messages.PFCPSessionDeletionResponseMessageType, 2, 1))
+
+	for _, payload := range [][]byte{nodePayload, sessionPayload} {
+		header, err := messages.DeserializeHeader(payload)
+		if err != nil {
+			t.Fatalf("Error deserializing header: %v", err)
+		}
+		if int(header.MessageLength) != len(payload)-4 {
+			t.Errorf("Expected message length %d, got %d", len(payload)-4, header.MessageLength)
+		}
+	}
+}
+
+func TestGivenSessionDeletionResponseWhenSerializeThenMatchesEncodedBytes(t *testing.T) {
+	cause, err := ie.NewCause(ie.RequestAccepted)
+	if err != nil {
+		t.Fatalf("Error creating Cause: %v", err)
+	}
+
+	payload := messages.Serialize(messages.PFCPSessionDeletionResponse{Cause: cause}, messages.NewSessionHeader(messages.PFCPSessionDeletionResponseMessageType, 0x0102030405060708, 0x0a0b0c))
+
+	// TS 29.244 section 7.2.2: the Message Length counts the 12 octets of the header
+	// following the first 4 octets and the 5 octets of the Cause IE.
+	expected := []byte{
+		0x21, 0x37, 0x00, 0x11, // Version 1 and S flag, Message Type 55, Message Length 17
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, // SEID
+		0x0a, 0x0b, 0x0c, 0x00, // Sequence Number and Spare
+		0x00, 0x13, 0x00, 0x01, 0x01, // Cause Request accepted
+	}
+	if !bytes.Equal(payload, expected) {
+		t.Errorf("Expected %x, got %x", expected, payload)
+	}
+}
+
+func TestGivenRequestRegisteredWithResponseTypeWhenIsRequestThenTrue(t *testing.T) {
+	// The PFCP Session Set Deletion messages are not registered by the package nor by other tests.
+	sessionSetDeletionRequest := messages.MessageType(14)
+	sessionSetDeletionResponse := messages.MessageType(15)
+
+	messages.Register(sessionSetDeletionRequest, func(data []byte) (messages.HeartbeatRequest, error) {
+		return messages.HeartbeatRequest{}, nil
+	}, messages.AnsweredWith(sessionSetDeletionResponse))
+
+	if !sessionSetDeletionRequest.IsRequest() {
+		t.Errorf("Expected message type %d to be a request", sessionSetDeletionRequest)
+	}
+	if !sessionSetDeletionResponse.IsResponse() {
+		t.Errorf("Expected message type %d to be a response", sessionSetDeletionResponse)
+	}
+	if responseType, ok := sessionSetDeletionRequest.ResponseType(); !ok || responseType != sessionSetDeletionResponse {
+		t.Errorf("Expected response type %d, got %d", sessionSetDeletionResponse, responseType)
+	}
+}
@@ -4,11 +4,15 @@ import "github.com/dot-5g/pfcp/ie"
 
 type PFCPAssociationReleaseRequest struct {
 	NodeID ie.NodeID // Mandatory
+
+	received ieSet
 }
 
 type PFCPAssociationReleaseResponse struct {
 	NodeID ie.NodeID // Mandatory
 	Cause  ie.Cause  // Mandatory
+
+	received ieSet
 }
 
 func (msg PFCPAssociationReleaseRequest) GetIEs() []ie.InformationElement {
@@ -46,7 +50,8 @@ func DeserializePFCPAssociationReleaseRequest(data []byte) (PFCPAssociationRelea
 	}
 
 	return PFCPAssociationReleaseRequest{
-		NodeID: nodeID,
+		NodeID:   nodeID,
+		received: receivedIEs(ies),
 	}, err
 }
 
@@ -66,7 +71,8 @@ func DeserializePFCPAssociationReleaseResponse(data []byte) (PFCPAssociationRele
 	}
 
 	return PFCPAssociationReleaseResponse{
-		NodeID: nodeID,
-		Cause:  cause,
+		NodeID:   nodeID,
+		Cause:    cause,
+		received: receivedIEs(ies),
 	}, err
 }
@@ -5,25 +5,36 @@ import (
 )
 
 type PFCPAssociationSetupRequest struct {
-	NodeID             ie.NodeID             // Mandatory
-	RecoveryTimeStamp  ie.RecoveryTimeStamp  // Mandatory
-	UPFunctionFeatures ie.UPFunctionFeatures // Conditional
+	NodeID             ie.NodeID              // Mandatory
+	RecoveryTimeStamp  ie.RecoveryTimeStamp   // Mandatory
+	UPFunctionFeatures *ie.UPFunctionFeatures // Conditional
+
+	received ieSet
 }
 
 type PFCPAssociationSetupResponse struct {
-	NodeID            ie.NodeID            // Mandatory
-	Cause             ie.Cause             // Mandatory
-	RecoveryTimeStamp ie.RecoveryTimeStamp // Mandatory
+	NodeID             ie.NodeID              // Mandatory
+	Cause              ie.Cause               // Mandatory
+	RecoveryTimeStamp  ie.RecoveryTimeStamp   // Mandatory
+	UPFunctionFeatures *ie.UPFunctionFeatures // Conditional
+
+	received ieSet
 }
 
 func (msg PFCPAssociationSetupRequest) GetIEs() []ie.InformationElement {
 	ies := []ie.InformationElement{msg.NodeID, msg.RecoveryTimeStamp}
-	ies = append(ies, msg.UPFunctionFeatures)
+	if msg.UPFunctionFeatures != nil {
+		ies = append(ies, *msg.UPFunctionFeatures)
+	}
 	return ies
 }
 
 func (msg PFCPAssociationSetupResponse) GetIEs() []ie.InformationElement {
-	return []ie.InformationElement{msg.NodeID, msg.Cause, msg.RecoveryTimeStamp}
+	ies := []ie.InformationElement{msg.NodeID, msg.Cause, msg.RecoveryTimeStamp}
+	if msg.UPFunctionFeatures != nil {
+		ies = append(ies, *msg.UPFunctionFeatures)
+	}
+	return ies
 }
 
 func (msg PFCPAssociationSetupRequest) GetMessageType() MessageType {
@@ -46,7 +57,7 @@ func DeserializePFCPAssociationSetupRequest(data []byte) (PFCPAssociationSetupRe
 	ies, err := ie.DeserializeInformationElements(data)
 	var nodeID ie.NodeID
 	var recoveryTimeStamp ie.RecoveryTimeStamp
-	var upfeatures ie.UPFunctionFeatures
+	var upfeatures *ie.UPFunctionFeatures
 	for _, elem := range ies {
 		if tsIE, ok := elem.(ie.RecoveryTimeStamp); ok {
 			recoveryTimeStamp = tsIE
@@ -57,7 +68,7 @@ func DeserializePFCPAssociationSetupRequest(data []byte) (PFCPAssociationSetupRe
 			continue
 		}
 		if upfeaturesIE, ok := elem.(ie.UPFunctionFeatures); ok {
-			upfeatures = upfeaturesIE
+			upfeatures = &upfeaturesIE
 			continue
 		}
 	}
@@ -66,6 +77,7 @@ func DeserializePFCPAssociationSetupRequest(data []byte) (PFCPAssociationSetupRe
 		NodeID:             nodeID,
 		RecoveryTimeStamp:  recoveryTimeStamp,
 		UPFunctionFeatures: upfeatures,
+		received:           receivedIEs(ies),
 	}, err
 }
 
@@ -74,6 +86,7 @@ func DeserializePFCPAssociationSetupResponse(data []byte) (PFCPAssociationSetupR
 	var nodeID ie.NodeID
 	var cause ie.Cause
 	var recoveryTimeStamp ie.RecoveryTimeStamp
+	var upfeatures *ie.UPFunctionFeatures
 	for _, elem := range ies {
 		if tsIE, ok := elem.(ie.RecoveryTimeStamp); ok {
 			recoveryTimeStamp = tsIE
@@ -87,11 +100,17 @@ func DeserializePFCPAssociationSetupResponse(data []byte) (PFCPAssociationSetupR
 			cause = causeIE
 			continue
 		}
+		if upfeaturesIE, ok := elem.(ie.UPFunctionFeatures); ok {
+			upfeatures = &upfeaturesIE
+			continue
+		}
 	}
 
 	return PFCPAssociationSetupResponse{
-		NodeID:            nodeID,
-		Cause:             cause,
-		RecoveryTimeStamp: recoveryTimeStamp,
+		NodeID:             nodeID,
+		Cause:              cause,
+		RecoveryTimeStamp:  recoveryTimeStamp,
+		UPFunctionFeatures: upfeatures,
+		received:           receivedIEs(ies),
 	}, err
 }
@@ -0,0 +1,95 @@
+package messages_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+)
+
+func TestGivenAssociationSetupResponseWithUPFunctionFeaturesWhenSerializeThenFeaturesRoundTrip(t *testing.T) {
+	nodeID, err := ie.NewNodeID("1.2.3.4")
+	if err != nil {
+		t.Fatalf("Error creating NodeID: %v", err)
+	}
+	cause, err := ie.NewCause(ie.RequestAccepted)
+	if err != nil {
+		t.Fatalf("Error creating Cause: %v", err)
+	}
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating RecoveryTimeStamp: %v", err)
+	}
+	features, err := ie.NewUPFunctionFeatures([]ie.UPFeature{ie.BUCP, ie.FTUP})
+	if err != nil {
+		t.Fatalf("Error creating UPFunctionFeatures: %v", err)
+	}
+
+	msg := messages.PFCPAssociationSetupResponse{
+		NodeID:             nodeID,
+		Cause:              cause,
+		RecoveryTimeStamp:  recoveryTimeStamp,
+		UPFunctionFeatures: &features,
+	}
+	payload := messages.Serialize(msg, messages.NewNodeHeader(msg.GetMessageType(), 3))
+
+	deserialized, err := messages.DeserializePFCPAssociationSetupResponse(payload[8:])
+	if err != nil {
+		t.Fatalf("Error deserializing PFCPAssociationSetupResponse: %v", err)
+	}
+
+	if deserialized.UPFunctionFeatures == nil || !bytes.Equal(deserialized.UPFunctionFeatures.SupportedFeatures, features.SupportedFeatures) {
+		t.Errorf("Expected UPFunctionFeatures %v, got %v", features, deserialized.UPFunctionFeatures)
+	}
+}
+
+func TestGivenAssociationSetupResponseWithoutUPFunctionFeaturesWhenSerializeThenFeaturesAbsent(t *testing.T) {
+	nodeID, err := ie.NewNodeID("1.2.3.4")
+	if err != nil {
+		t.Fatalf("Error creating NodeID: %v", err)
+	}
+	cause, err := ie.NewCause(ie.RequestAccepted)
+	if err != nil {
+		t.Fatalf("Error creating Cause: %v", err)
+	}
+
+	msg := messages.PFCPAssociationSetupResponse{NodeID: nodeID, Cause: cause}
+	payload := messages.Serialize(msg, messages.NewNodeHeader(msg.GetMessageType(), 3))
+
+	deserialized, err := messages.DeserializePFCPAssociationSetupResponse(payload[8:])
+	if err != nil {
+		t.Fatalf("Error deserializing PFCPAssociationSetupResponse: %v", err)
+	}
+
+	if deserialized.UPFunctionFeatures != nil {
+		t.Errorf("Expected no UPFunctionFeatures, got %v", deserialized.UPFunctionFeatures)
+	}
+}
+
+func TestGivenAssociationSetupRequestWithoutUPFunctionFeaturesWhenSerializeThenFeaturesAbsent(t *testing.T) {
+	nodeID, err := ie.NewNodeID("1.2.3.4")
+	if err != nil {
+		t.Fatalf("Error creating NodeID: %v", err)
+	}
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating RecoveryTimeStamp: %v", err)
+	}
+
+	msg := messages.PFCPAssociationSetupRequest{NodeID: nodeID, RecoveryTimeStamp: recoveryTimeStamp}
+	payload := messages.Serialize(msg, messages.NewNodeHeader(msg.GetMessageType(), 3))
+
+	deserialized, err := messages.DeserializePFCPAssociationSetupRequest(payload[8:])
+	if err != nil {
+		t.Fatalf("Error deserializing PFCPAssociationSetupRequest: %v", err)
+	}
+
+	if deserialized.RecoveryTimeStamp != recoveryTimeStamp {
+		t.Errorf("Expected RecoveryTimeStamp %v, got %v", recoveryTimeStamp, deserialized.RecoveryTimeStamp)
+	}
+	if deserialized.UPFunctionFeatures != nil {
+		t.Errorf("Expected no UPFunctionFeatures, got %v", deserialized.UPFunctionFeatures)
+	}
+}
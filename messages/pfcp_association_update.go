@@ -4,11 +4,15 @@ import "github.com/dot-5g/pfcp/ie"
 
 type PFCPAssociationUpdateRequest struct {
 	NodeID ie.NodeID // Mandatory
+
+	received ieSet
 }
 
 type PFCPAssociationUpdateResponse struct {
 	NodeID ie.NodeID // Mandatory
 	Cause  ie.Cause  // Mandatory
+
+	received ieSet
 }
 
 func (msg PFCPAssociationUpdateRequest) GetIEs() []ie.InformationElement {
@@ -46,7 +50,8 @@ func DeserializePFCPAssociationUpdateRequest(data []byte) (PFCPAssociationUpdate
 	}
 
 	return PFCPAssociationUpdateRequest{
-		NodeID: nodeID,
+		NodeID:   nodeID,
+		received: receivedIEs(ies),
 	}, err
 }
 
@@ -66,7 +71,8 @@ func DeserializePFCPAssociationUpdateResponse(data []byte) (PFCPAssociationUpdat
 	}
 
 	return PFCPAssociationUpdateResponse{
-		NodeID: nodeID,
-		Cause:  cause,
+		NodeID:   nodeID,
+		Cause:    cause,
+		received: receivedIEs(ies),
 	}, err
 }
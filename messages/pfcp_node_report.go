@@ -7,11 +7,16 @@ import (
 type PFCPNodeReportRequest struct {
 	NodeID         ie.NodeID         // Mandatory
 	NodeReportType ie.NodeReportType // Mandatory
+
+	received ieSet
 }
 
 type PFCPNodeReportResponse struct {
-	NodeID ie.NodeID // Mandatory
-	Cause  ie.Cause  // Mandatory
+	NodeID      ie.NodeID       // Mandatory
+	Cause       ie.Cause        // Mandatory
+	OffendingIE *ie.OffendingIE // Conditional
+
+	received ieSet
 }
 
 func (msg PFCPNodeReportRequest) GetIEs() []ie.InformationElement {
@@ -19,7 +24,11 @@ func (msg PFCPNodeReportRequest) GetIEs() []ie.InformationElement {
 }
 
 func (msg PFCPNodeReportResponse) GetIEs() []ie.InformationElement {
-	return []ie.InformationElement{msg.NodeID, msg.Cause}
+	ies := []ie.InformationElement{msg.NodeID, msg.Cause}
+	if msg.OffendingIE != nil {
+		ies = append(ies, *msg.OffendingIE)
+	}
+	return ies
 }
 
 func (msg PFCPNodeReportRequest) GetMessageType() MessageType {
@@ -56,6 +65,7 @@ func DeserializePFCPNodeReportRequest(data []byte) (PFCPNodeReportRequest, error
 	return PFCPNodeReportRequest{
 		NodeID:         nodeID,
 		NodeReportType: nodeReportType,
+		received:       receivedIEs(ies),
 	}, err
 }
 
@@ -63,6 +73,7 @@ func DeserializePFCPNodeReportResponse(data []byte) (PFCPNodeReportResponse, err
 	ies, err := ie.DeserializeInformationElements(data)
 	var nodeID ie.NodeID
 	var cause ie.Cause
+	var offendingIE *ie.OffendingIE
 	for _, elem := range ies {
 		if nodeIDIE, ok := elem.(ie.NodeID); ok {
 			nodeID = nodeIDIE
@@ -72,10 +83,16 @@ func DeserializePFCPNodeReportResponse(data []byte) (PFCPNodeReportResponse, err
 			cause = causeIE
 			continue
 		}
+		if offendingIEIE, ok := elem.(ie.OffendingIE); ok {
+			offendingIE = &offendingIEIE
+			continue
+		}
 	}
 
 	return PFCPNodeReportResponse{
-		NodeID: nodeID,
-		Cause:  cause,
+		NodeID:      nodeID,
+		Cause:       cause,
+		OffendingIE: offendingIE,
+		received:    receivedIEs(ies),
 	}, err
 }
@@ -5,7 +5,10 @@ import "github.com/dot-5g/pfcp/ie"
 type PFCPSessionDeletionRequest struct{}
 
 type PFCPSessionDeletionResponse struct {
-	Cause ie.Cause // Mandatory
+	Cause       ie.Cause        // Mandatory
+	OffendingIE *ie.OffendingIE // Conditional
+
+	received ieSet
 }
 
 func (msg PFCPSessionDeletionRequest) GetIEs() []ie.InformationElement {
@@ -13,7 +16,11 @@ func (msg PFCPSessionDeletionRequest) GetIEs() []ie.InformationElement {
 }
 
 func (msg PFCPSessionDeletionResponse) GetIEs() []ie.InformationElement {
-	return []ie.InformationElement{msg.Cause}
+	ies := []ie.InformationElement{msg.Cause}
+	if msg.OffendingIE != nil {
+		ies = append(ies, *msg.OffendingIE)
+	}
+	return ies
 }
 
 func (msg PFCPSessionDeletionRequest) GetMessageType() MessageType {
@@ -43,14 +50,21 @@ func DeserializePFCPSessionDeletionResponse(data []byte) (PFCPSessionDeletionRes
 	}
 
 	var cause ie.Cause
+	var offendingIE *ie.OffendingIE
 	for _, elem := range ies {
 		if causeIE, ok := elem.(ie.Cause); ok {
 			cause = causeIE
 			continue
 		}
+		if offendingIEIE, ok := elem.(ie.OffendingIE); ok {
+			offendingIE = &offendingIEIE
+			continue
+		}
 	}
 
 	return PFCPSessionDeletionResponse{
-		Cause: cause,
+		Cause:       cause,
+		OffendingIE: offendingIE,
+		received:    receivedIEs(ies),
 	}, nil
 }
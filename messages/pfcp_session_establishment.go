@@ -3,23 +3,63 @@ package messages
 import "github.com/dot-5g/pfcp/ie"
 
 type PFCPSessionEstablishmentRequest struct {
-	NodeID    ie.NodeID    // Mandatory
-	CPFSEID   ie.FSEID     // Mandatory
-	CreatePDR ie.CreatePDR // Mandatory
-	CreateFAR ie.CreateFAR // Mandatory
+	NodeID    ie.NodeID      // Mandatory
+	CPFSEID   ie.FSEID       // Mandatory
+	CreatePDR []ie.CreatePDR // Mandatory
+	CreateFAR []ie.CreateFAR // Mandatory
+	CreateURR []ie.CreateURR // Conditional
+	CreateQER []ie.CreateQER // Conditional
+	CreateBAR *ie.CreateBAR  // Optional
+
+	received ieSet
 }
 
 type PFCPSessionEstablishmentResponse struct {
-	NodeID ie.NodeID // Mandatory
-	Cause  ie.Cause  // Mandatory
+	NodeID       ie.NodeID        // Mandatory
+	Cause        ie.Cause         // Mandatory
+	OffendingIE  *ie.OffendingIE  // Conditional
+	UPFSEID      *ie.FSEID        // Conditional
+	CreatedPDR   []ie.CreatedPDR  // Conditional
+	FailedRuleID *ie.FailedRuleID // Conditional
+
+	received ieSet
 }
 
 func (msg PFCPSessionEstablishmentRequest) GetIEs() []ie.InformationElement {
-	return []ie.InformationElement{msg.NodeID, msg.CPFSEID, msg.CreatePDR, msg.CreateFAR}
+	ies := []ie.InformationElement{msg.NodeID, msg.CPFSEID}
+	for _, createPDR := range msg.CreatePDR {
+		ies = append(ies, createPDR)
+	}
+	for _, createFAR := range msg.CreateFAR {
+		ies = append(ies, createFAR)
+	}
+	for _, createURR := range msg.CreateURR {
+		ies = append(ies, createURR)
+	}
+	for _, createQER := range msg.CreateQER {
+		ies = append(ies, createQER)
+	}
+	if msg.CreateBAR != nil {
+		ies = append(ies, *msg.CreateBAR)
+	}
+	return ies
 }
 
 func (msg PFCPSessionEstablishmentResponse) GetIEs() []ie.InformationElement {
-	return []ie.InformationElement{msg.NodeID, msg.Cause}
+	ies := []ie.InformationElement{msg.NodeID, msg.Cause}
+	if msg.OffendingIE != nil {
+		ies = append(ies, *msg.OffendingIE)
+	}
+	if msg.UPFSEID != nil {
+		ies = append(ies, *msg.UPFSEID)
+	}
+	for _, createdPDR := range msg.CreatedPDR {
+		ies = append(ies, createdPDR)
+	}
+	if msg.FailedRuleID != nil {
+		ies = append(ies, *msg.FailedRuleID)
+	}
+	return ies
 }
 
 func (msg PFCPSessionEstablishmentRequest) GetMessageType() MessageType {
@@ -42,8 +82,11 @@ func DeserializePFCPSessionEstablishmentRequest(data []byte) (PFCPSessionEstabli
 	ies, err := ie.DeserializeInformationElements(data)
 	var nodeID ie.NodeID
 	var controlPlaneFSEID ie.FSEID
-	var createPDR ie.CreatePDR
-	var createFAR ie.CreateFAR
+	var createPDRs []ie.CreatePDR
+	var createFARs []ie.CreateFAR
+	var createURRs []ie.CreateURR
+	var createQERs []ie.CreateQER
+	var createBAR *ie.CreateBAR
 
 	for _, elem := range ies {
 		if nodeIDIE, ok := elem.(ie.NodeID); ok {
@@ -55,11 +98,23 @@ func DeserializePFCPSessionEstablishmentRequest(data []byte) (PFCPSessionEstabli
 			continue
 		}
 		if createPDRIE, ok := elem.(ie.CreatePDR); ok {
-			createPDR = createPDRIE
+			createPDRs = append(createPDRs, createPDRIE)
 			continue
 		}
 		if createFARIE, ok := elem.(ie.CreateFAR); ok {
-			createFAR = createFARIE
+			createFARs = append(createFARs, createFARIE)
+			continue
+		}
+		if createURRIE, ok := elem.(ie.CreateURR); ok {
+			createURRs = append(createURRs, createURRIE)
+			continue
+		}
+		if createQERIE, ok := elem.(ie.CreateQER); ok {
+			createQERs = append(createQERs, createQERIE)
+			continue
+		}
+		if createBARIE, ok := elem.(ie.CreateBAR); ok {
+			createBAR = &createBARIE
 			continue
 		}
 	}
@@ -67,8 +122,12 @@ func DeserializePFCPSessionEstablishmentRequest(data []byte) (PFCPSessionEstabli
 	return PFCPSessionEstablishmentRequest{
 		NodeID:    nodeID,
 		CPFSEID:   controlPlaneFSEID,
-		CreatePDR: createPDR,
-		CreateFAR: createFAR,
+		CreatePDR: createPDRs,
+		CreateFAR: createFARs,
+		CreateURR: createURRs,
+		CreateQER: createQERs,
+		CreateBAR: createBAR,
+		received:  receivedIEs(ies),
 	}, err
 }
 
@@ -76,6 +135,10 @@ func DeserializePFCPSessionEstablishmentResponse(data []byte) (PFCPSessionEstabl
 	ies, err := ie.DeserializeInformationElements(data)
 	var nodeID ie.NodeID
 	var cause ie.Cause
+	var offendingIE *ie.OffendingIE
+	var userPlaneFSEID *ie.FSEID
+	var createdPDRs []ie.CreatedPDR
+	var failedRuleID *ie.FailedRuleID
 
 	for _, elem := range ies {
 		if nodeIDIE, ok := elem.(ie.NodeID); ok {
@@ -86,10 +149,31 @@ func DeserializePFCPSessionEstablishmentResponse(data []byte) (PFCPSessionEstabl
 			cause = causeIE
 			continue
 		}
+		if offendingIEIE, ok := elem.(ie.OffendingIE); ok {
+			offendingIE = &offendingIEIE
+			continue
+		}
+		if userPlaneFSEIDIE, ok := elem.(ie.FSEID); ok {
+			userPlaneFSEID = &userPlaneFSEIDIE
+			continue
+		}
+		if createdPDRIE, ok := elem.(ie.CreatedPDR); ok {
+			createdPDRs = append(createdPDRs, createdPDRIE)
+			continue
+		}
+		if failedRuleIDIE, ok := elem.(ie.FailedRuleID); ok {
+			failedRuleID = &failedRuleIDIE
+			continue
+		}
 	}
 
 	return PFCPSessionEstablishmentResponse{
-		NodeID: nodeID,
-		Cause:  cause,
+		NodeID:       nodeID,
+		Cause:        cause,
+		OffendingIE:  offendingIE,
+		UPFSEID:      userPlaneFSEID,
+		CreatedPDR:   createdPDRs,
+		FailedRuleID: failedRuleID,
+		received:     receivedIEs(ies),
 	}, err
 }
@@ -0,0 +1,65 @@
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+)
+
+func TestGivenRejectedSessionEstablishmentResponseWhenSerializeThenOffendingIEAndFailedRuleIDRoundTrip(t *testing.T) {
+	nodeID, err := ie.NewNodeID("1.2.3.4")
+	if err != nil {
+		t.Fatalf("Error creating NodeID: %v", err)
+	}
+
+	cause, err := ie.NewCause(ie.RuleCreationFailure)
+	if err != nil {
+		t.Fatalf("Error creating Cause: %v", err)
+	}
+
+	offendingIE, err := ie.NewOffendingIE(ie.CreateFARIEType)
+	if err != nil {
+		t.Fatalf("Error creating OffendingIE: %v", err)
+	}
+
+	failedRuleID, err := ie.NewFailedRuleID(ie.FARRuleIDType, 3)
+	if err != nil {
+		t.Fatalf("Error creating FailedRuleID: %v", err)
+	}
+
+	msg := messages.PFCPSessionEstablishmentResponse{
+		NodeID:       nodeID,
+		Cause:        cause,
+		OffendingIE:  &offendingIE,
+		FailedRuleID: &failedRuleID,
+	}
+
+	header := messages.NewSessionHeader(msg.GetMessageType(), 1, 2)
+	payload := messages.Serialize(msg, header)
+
+	deserialized, err := messages.DeserializePFCPSessionEstablishmentResponse(payload[16:])
+	if err != nil {
+		t.Fatalf("Error deserializing PFCPSessionEstablishmentResponse: %v", err)
+	}
+
+	if deserialized.Cause != cause {
+		t.Errorf("Expected Cause %v, got %v", cause, deserialized.Cause)
+	}
+
+	if deserialized.OffendingIE == nil || *deserialized.OffendingIE != offendingIE {
+		t.Errorf("Expected OffendingIE %v, got %v", offendingIE, deserialized.OffendingIE)
+	}
+
+	if deserialized.FailedRuleID == nil || *deserialized.FailedRuleID != failedRuleID {
+		t.Errorf("Expected FailedRuleID %v, got %v", failedRuleID, deserialized.FailedRuleID)
+	}
+
+	if deserialized.UPFSEID != nil {
+		t.Errorf("Expected no UP F-SEID, got %v", deserialized.UPFSEID)
+	}
+
+	if len(deserialized.CreatedPDR) != 0 {
+		t.Errorf("Expected no Created PDR, got %v", deserialized.CreatedPDR)
+	}
+}
@@ -0,0 +1,213 @@
+package messages
+
+import "github.com/dot-5g/pfcp/ie"
+
+type PFCPSessionModificationRequest struct {
+	CPFSEID   *ie.FSEID      // Conditional
+	RemovePDR []ie.RemovePDR // Conditional
+	RemoveFAR []ie.RemoveFAR // Conditional
+	RemoveURR []ie.RemoveURR // Conditional
+	RemoveQER []ie.RemoveQER // Conditional
+	RemoveBAR *ie.RemoveBAR  // Conditional
+	CreatePDR []ie.CreatePDR // Conditional
+	CreateFAR []ie.CreateFAR // Conditional
+	CreateURR []ie.CreateURR // Conditional
+	CreateQER []ie.CreateQER // Conditional
+	CreateBAR *ie.CreateBAR  // Conditional
+	UpdatePDR []ie.UpdatePDR // Conditional
+	UpdateFAR []ie.UpdateFAR // Conditional
+	UpdateURR []ie.UpdateURR // Conditional
+	UpdateQER []ie.UpdateQER // Conditional
+	UpdateBAR *ie.UpdateBAR  // Conditional
+}
+
+type PFCPSessionModificationResponse struct {
+	Cause        ie.Cause         // Mandatory
+	OffendingIE  *ie.OffendingIE  // Conditional
+	FailedRuleID *ie.FailedRuleID // Conditional
+
+	received ieSet
+}
+
+func (msg PFCPSessionModificationRequest) GetIEs() []ie.InformationElement {
+	var ies []ie.InformationElement
+	if msg.CPFSEID != nil {
+		ies = append(ies, *msg.CPFSEID)
+	}
+	for _, removePDR := range msg.RemovePDR {
+		ies = append(ies, removePDR)
+	}
+	for _, removeFAR := range msg.RemoveFAR {
+		ies = append(ies, removeFAR)
+	}
+	for _, removeURR := range msg.RemoveURR {
+		ies = append(ies, removeURR)
+	}
+	for _, removeQER := range msg.RemoveQER {
+		ies = append(ies, removeQER)
+	}
+	if msg.RemoveBAR != nil {
+		ies = append(ies, *msg.RemoveBAR)
+	}
+	for _, createPDR := range msg.CreatePDR {
+		ies = append(ies, createPDR)
+	}
+	for _, createFAR := range msg.CreateFAR {
+		ies = append(ies, createFAR)
+	}
+	for _, createURR := range msg.CreateURR {
+		ies = append(ies, createURR)
+	}
+	for _, createQER := range msg.CreateQER {
+		ies = append(ies, createQER)
+	}
+	if msg.CreateBAR != nil {
+		ies = append(ies, *msg.CreateBAR)
+	}
+	for _, updatePDR := range msg.UpdatePDR {
+		ies = append(ies, updatePDR)
+	}
+	for _, updateFAR := range msg.UpdateFAR {
+		ies = append(ies, updateFAR)
+	}
+	for _, updateURR := range msg.UpdateURR {
+		ies = append(ies, updateURR)
+	}
+	for _, updateQER := range msg.UpdateQER {
+		ies = append(ies, updateQER)
+	}
+	if msg.UpdateBAR != nil {
+		ies = append(ies, *msg.UpdateBAR)
+	}
+	return ies
+}
+
+func (msg PFCPSessionModificationResponse) GetIEs() []ie.InformationElement {
+	ies := []ie.InformationElement{msg.Cause}
+	if msg.OffendingIE != nil {
+		ies = append(ies, *msg.OffendingIE)
+	}
+	if msg.FailedRuleID != nil {
+		ies = append(ies, *msg.FailedRuleID)
+	}
+	return ies
+}
+
+func (msg PFCPSessionModificationRequest) GetMessageType() MessageType {
+	return PFCPSessionModificationRequestMessageType
+}
+
+func (msg PFCPSessionModificationResponse) GetMessageType() MessageType {
+	return PFCPSessionModificationResponseMessageType
+}
+
+func (msg PFCPSessionModificationRequest) GetMessageTypeString() string {
+	return "PFCP Session Modification Request"
+}
+
+func (msg PFCPSessionModificationResponse) GetMessageTypeString() string {
+	return "PFCP Session Modification Response"
+}
+
+func DeserializePFCPSessionModificationRequest(data []byte) (PFCPSessionModificationRequest, error) {
+	ies, err := ie.DeserializeInformationElements(data)
+	var msg PFCPSessionModificationRequest
+
+	for _, elem := range ies {
+		if controlPlaneFSEIDIE, ok := elem.(ie.FSEID); ok {
+			msg.CPFSEID = &controlPlaneFSEIDIE
+			continue
+		}
+		if removePDRIE, ok := elem.(ie.RemovePDR); ok {
+			msg.RemovePDR = append(msg.RemovePDR, removePDRIE)
+			continue
+		}
+		if removeFARIE, ok := elem.(ie.RemoveFAR); ok {
+			msg.RemoveFAR = append(msg.RemoveFAR, removeFARIE)
+			continue
+		}
+		if removeURRIE, ok := elem.(ie.RemoveURR); ok {
+			msg.RemoveURR = append(msg.RemoveURR, removeURRIE)
+			continue
+		}
+		if removeQERIE, ok := elem.(ie.RemoveQER); ok {
+			msg.RemoveQER = append(msg.RemoveQER, removeQERIE)
+			continue
+		}
+		if removeBARIE, ok := elem.(ie.RemoveBAR); ok {
+			msg.RemoveBAR = &removeBARIE
+			continue
+		}
+		if createPDRIE, ok := elem.(ie.CreatePDR); ok {
+			msg.CreatePDR = append(msg.CreatePDR, createPDRIE)
+			continue
+		}
+		if createFARIE, ok := elem.(ie.CreateFAR); ok {
+			msg.CreateFAR = append(msg.CreateFAR, createFARIE)
+			continue
+		}
+		if createURRIE, ok := elem.(ie.CreateURR); ok {
+			msg.CreateURR = append(msg.CreateURR, createURRIE)
+			continue
+		}
+		if createQERIE, ok := elem.(ie.CreateQER); ok {
+			msg.CreateQER = append(msg.CreateQER, createQERIE)
+			continue
+		}
+		if createBARIE, ok := elem.(ie.CreateBAR); ok {
+			msg.CreateBAR = &createBARIE
+			continue
+		}
+		if updatePDRIE, ok := elem.(ie.UpdatePDR); ok {
+			msg.UpdatePDR = append(msg.UpdatePDR, updatePDRIE)
+			continue
+		}
+		if updateFARIE, ok := elem.(ie.UpdateFAR); ok {
+			msg.UpdateFAR = append(msg.UpdateFAR, updateFARIE)
+			continue
+		}
+		if updateURRIE, ok := elem.(ie.UpdateURR); ok {
+			msg.UpdateURR = append(msg.UpdateURR, updateURRIE)
+			continue
+		}
+		if updateQERIE, ok := elem.(ie.UpdateQER); ok {
+			msg.UpdateQER = append(msg.UpdateQER, updateQERIE)
+			continue
+		}
+		if updateBARIE, ok := elem.(ie.UpdateBAR); ok {
+			msg.UpdateBAR = &updateBARIE
+			continue
+		}
+	}
+
+	return msg, err
+}
+
+func DeserializePFCPSessionModificationResponse(data []byte) (PFCPSessionModificationResponse, error) {
+	ies, err := ie.DeserializeInformationElements(data)
+	var cause ie.Cause
+	var offendingIE *ie.OffendingIE
+	var failedRuleID *ie.FailedRuleID
+
+	for _, elem := range ies {
+		if causeIE, ok := elem.(ie.Cause); ok {
+			cause = causeIE
+			continue
+		}
+		if offendingIEIE, ok := elem.(ie.OffendingIE); ok {
+			offendingIE = &offendingIEIE
+			continue
+		}
+		if failedRuleIDIE, ok := elem.(ie.FailedRuleID); ok {
+			failedRuleID = &failedRuleIDIE
+			continue
+		}
+	}
+
+	return PFCPSessionModificationResponse{
+		Cause:        cause,
+		OffendingIE:  offendingIE,
+		FailedRuleID: failedRuleID,
+		received:     receivedIEs(ies),
+	}, err
+}
@@ -4,10 +4,15 @@ import "github.com/dot-5g/pfcp/ie"
 
 type PFCPSessionReportRequest struct {
 	ReportType ie.ReportType // Mandatory
+
+	received ieSet
 }
 
 type PFCPSessionReportResponse struct {
-	Cause ie.Cause // Mandatory
+	Cause       ie.Cause        // Mandatory
+	OffendingIE *ie.OffendingIE // Conditional
+
+	received ieSet
 }
 
 func (msg PFCPSessionReportRequest) GetIEs() []ie.InformationElement {
@@ -15,7 +20,11 @@ func (msg PFCPSessionReportRequest) GetIEs() []ie.InformationElement {
 }
 
 func (msg PFCPSessionReportResponse) GetIEs() []ie.InformationElement {
-	return []ie.InformationElement{msg.Cause}
+	ies := []ie.InformationElement{msg.Cause}
+	if msg.OffendingIE != nil {
+		ies = append(ies, *msg.OffendingIE)
+	}
+	return ies
 }
 
 func (msg PFCPSessionReportRequest) GetMessageType() MessageType {
@@ -47,21 +56,29 @@ func DeserializePFCPSessionReportRequest(data []byte) (PFCPSessionReportRequest,
 
 	return PFCPSessionReportRequest{
 		ReportType: reportType,
+		received:   receivedIEs(ies),
 	}, err
 }
 
 func DeserializePFCPSessionReportResponse(data []byte) (PFCPSessionReportResponse, error) {
 	ies, err := ie.DeserializeInformationElements(data)
 	var cause ie.Cause
+	var offendingIE *ie.OffendingIE
 
 	for _, elem := range ies {
 		if causeIE, ok := elem.(ie.Cause); ok {
 			cause = causeIE
 			continue
 		}
+		if offendingIEIE, ok := elem.(ie.OffendingIE); ok {
+			offendingIE = &offendingIEIE
+			continue
+		}
 	}
 
 	return PFCPSessionReportResponse{
-		Cause: cause,
+		Cause:       cause,
+		OffendingIE: offendingIE,
+		received:    receivedIEs(ies),
 	}, err
 }
@@ -0,0 +1,210 @@
+package messages
+
+import (
+	"fmt"
+
+	"github.com/dot-5g/pfcp/ie"
+)
+
+// ValidationError reports a message whose IEs do not meet the presence requirements of its type.
+// Cause is MandatoryIEMissing, ConditionalIEMissing or MandatoryIEIncorrect and IEType is the type
+// of the offending IE, to be put in the Offending IE of the response.
+type ValidationError struct {
+	MessageType MessageType
+	Cause       ie.CauseValue
+	IEType      ie.IEType
+}
+
+func (e *ValidationError) Error() string {
+	switch e.Cause {
+	case ie.MandatoryIEMissing:
+		return fmt.Sprintf("mandatory IE %d missing from message type %d", e.IEType, e.MessageType)
+	case ie.ConditionalIEMissing:
+		return fmt.Sprintf("conditional IE %d missing from message type %d", e.IEType, e.MessageType)
+	default:
+		return fmt.Sprintf("mandatory IE %d incorrect in message type %d", e.IEType, e.MessageType)
+	}
+}
+
+// Validator is implemented by the messages that check the presence of their IEs. A deserialized
+// message reports the mandatory IEs it was received without, whatever the value of the others,
+// while a message built locally always carries its mandatory IEs.
+type Validator interface {
+	Validate() error
+}
+
+type presence int
+
+const (
+	mandatory presence = iota
+	conditional
+)
+
+// requirement is the presence requirement of an IE in a message. Conditional IEs are only
+// listed when the condition requiring them holds.
+type requirement struct {
+	ieType    ie.IEType
+	presence  presence
+	present   bool
+	incorrect bool
+}
+
+func mandatoryIE(ieType ie.IEType, present bool) requirement {
+	return requirement{ieType: ieType, presence: mandatory, present: present}
+}
+
+func conditionalIE(ieType ie.IEType, present bool) requirement {
+	return requirement{ieType: ieType, presence: conditional, present: present}
+}
+
+// correct marks the IE of requirement as incorrect unless ok.
+func (r requirement) correct(ok bool) requirement {
+	r.incorrect = !ok
+	return r
+}
+
+// validate returns a *ValidationError for the first requirement not met.
+func validate(messageType MessageType, requirements ...requirement) error {
+	for _, r := range requirements {
+		switch {
+		case !r.present && r.presence == mandatory:
+			return &ValidationError{MessageType: messageType, Cause: ie.MandatoryIEMissing, IEType: r.ieType}
+		case !r.present:
+			return &ValidationError{MessageType: messageType, Cause: ie.ConditionalIEMissing, IEType: r.ieType}
+		case r.incorrect:
+			return &ValidationError{MessageType: messageType, Cause: ie.MandatoryIEIncorrect, IEType: r.ieType}
+		}
+	}
+	return nil
+}
+
+// ieSet holds the types of the IEs found while deserializing a message. It is nil for the messages
+// built locally, whose mandatory IEs are always serialized.
+type ieSet map[ie.IEType]struct{}
+
+func receivedIEs(ies []ie.InformationElement) ieSet {
+	set := make(ieSet, len(ies))
+	for _, elem := range ies {
+		set[elem.GetType()] = struct{}{}
+	}
+	return set
+}
+
+// has reports whether the message carries an IE of type ieType.
+func (set ieSet) has(ieType ie.IEType) bool {
+	if set == nil {
+		return true
+	}
+	_, present := set[ieType]
+	return present
+}
+
+func nodeIDIE(received ieSet) requirement {
+	return mandatoryIE(ie.NodeIDIEType, received.has(ie.NodeIDIEType))
+}
+
+func recoveryTimeStampIE(received ieSet) requirement {
+	return mandatoryIE(ie.RecoveryTimeStampIEType, received.has(ie.RecoveryTimeStampIEType))
+}
+
+func causeIE(received ieSet, cause ie.Cause) requirement {
+	_, err := ie.NewCause(cause.Value)
+	return mandatoryIE(ie.CauseIEType, received.has(ie.CauseIEType)).correct(err == nil)
+}
+
+// rejectionIEs returns the requirements on the Offending IE and Failed Rule ID of a response carrying cause.
+func rejectionIEs(cause ie.Cause, offendingIE *ie.OffendingIE, failedRuleID *ie.FailedRuleID) []requirement {
+	switch cause.Value {
+	case ie.MandatoryIEMissing, ie.ConditionalIEMissing, ie.MandatoryIEIncorrect:
+		return []requirement{conditionalIE(ie.OffendingIEIEType, offendingIE != nil)}
+	case ie.RuleCreationFailure:
+		return []requirement{conditionalIE(ie.FailedRuleIDIEType, failedRuleID != nil)}
+	default:
+		return nil
+	}
+}
+
+func (msg HeartbeatRequest) Validate() error {
+	return validate(msg.GetMessageType(), recoveryTimeStampIE(msg.received))
+}
+
+func (msg HeartbeatResponse) Validate() error {
+	return validate(msg.GetMessageType(), recoveryTimeStampIE(msg.received))
+}
+
+func (msg PFCPAssociationSetupRequest) Validate() error {
+	return validate(msg.GetMessageType(), nodeIDIE(msg.received), recoveryTimeStampIE(msg.received))
+}
+
+func (msg PFCPAssociationSetupResponse) Validate() error {
+	return validate(msg.GetMessageType(), nodeIDIE(msg.received), causeIE(msg.received, msg.Cause), recoveryTimeStampIE(msg.received))
+}
+
+func (msg PFCPAssociationUpdateRequest) Validate() error {
+	return validate(msg.GetMessageType(), nodeIDIE(msg.received))
+}
+
+func (msg PFCPAssociationUpdateResponse) Validate() error {
+	return validate(msg.GetMessageType(), nodeIDIE(msg.received), causeIE(msg.received, msg.Cause))
+}
+
+func (msg PFCPAssociationReleaseRequest) Validate() error {
+	return validate(msg.GetMessageType(), nodeIDIE(msg.received))
+}
+
+func (msg PFCPAssociationReleaseResponse) Validate() error {
+	return validate(msg.GetMessageType(), nodeIDIE(msg.received), causeIE(msg.received, msg.Cause))
+}
+
+func (msg PFCPNodeReportRequest) Validate() error {
+	reportType := msg.NodeReportType
+	return validate(msg.GetMessageType(),
+		nodeIDIE(msg.received),
+		mandatoryIE(ie.NodeReportTypeIEType, msg.received.has(ie.NodeReportTypeIEType)).
+			correct(reportType.GPQR || reportType.CKDR || reportType.UPRR || reportType.UPFR),
+	)
+}
+
+func (msg PFCPNodeReportResponse) Validate() error {
+	requirements := append([]requirement{nodeIDIE(msg.received), causeIE(msg.received, msg.Cause)}, rejectionIEs(msg.Cause, msg.OffendingIE, nil)...)
+	return validate(msg.GetMessageType(), requirements...)
+}
+
+func (msg PFCPSessionEstablishmentRequest) Validate() error {
+	return validate(msg.GetMessageType(),
+		nodeIDIE(msg.received),
+		mandatoryIE(ie.FSEIDIEType, msg.received.has(ie.FSEIDIEType)).correct(msg.CPFSEID.V4 || msg.CPFSEID.V6),
+		mandatoryIE(ie.CreatePDRIEType, len(msg.CreatePDR) > 0),
+		mandatoryIE(ie.CreateFARIEType, len(msg.CreateFAR) > 0),
+	)
+}
+
+func (msg PFCPSessionEstablishmentResponse) Validate() error {
+	requirements := []requirement{nodeIDIE(msg.received), causeIE(msg.received, msg.Cause)}
+	if msg.Cause.Value == ie.RequestAccepted {
+		requirements = append(requirements, conditionalIE(ie.FSEIDIEType, msg.UPFSEID != nil))
+	}
+	requirements = append(requirements, rejectionIEs(msg.Cause, msg.OffendingIE, msg.FailedRuleID)...)
+	return validate(msg.GetMessageType(), requirements...)
+}
+
+func (msg PFCPSessionModificationResponse) Validate() error {
+	requirements := append([]requirement{causeIE(msg.received, msg.Cause)}, rejectionIEs(msg.Cause, msg.OffendingIE, msg.FailedRuleID)...)
+	return validate(msg.GetMessageType(), requirements...)
+}
+
+func (msg PFCPSessionDeletionResponse) Validate() error {
+	requirements := append([]requirement{causeIE(msg.received, msg.Cause)}, rejectionIEs(msg.Cause, msg.OffendingIE, nil)...)
+	return validate(msg.GetMessageType(), requirements...)
+}
+
+func (msg PFCPSessionReportRequest) Validate() error {
+	return validate(msg.GetMessageType(),
+		mandatoryIE(ie.ReportTypeIEType, msg.received.has(ie.ReportTypeIEType)).correct(len(msg.ReportType.Reports) > 0),
+	)
+}
+
+func (msg PFCPSessionReportResponse) Validate() error {
+	requirements := append([]requirement{causeIE(msg.received, msg.Cause)}, rejectionIEs(msg.Cause, msg.OffendingIE, nil)...)
+	return validate(msg.GetMessageType(), requirements...)
+}
@@ -0,0 +1,116 @@
+package messages_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+)
+
+func expectValidationError(t *testing.T, err error, cause ie.CauseValue, ieType ie.IEType) {
+	t.Helper()
+	var validationErr *messages.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a *ValidationError, got %v", err)
+	}
+	if validationErr.Cause != cause || validationErr.IEType != ieType {
+		t.Errorf("Expected cause %d for IE %d, got cause %d for IE %d", cause, ieType, validationErr.Cause, validationErr.IEType)
+	}
+}
+
+func TestGivenSetupRequestWithoutRecoveryTimeStampWhenValidateThenMandatoryIEMissing(t *testing.T) {
+	nodeID, err := ie.NewNodeID("1.2.3.4")
+	if err != nil {
+		t.Fatalf("Error creating NodeID: %v", err)
+	}
+
+	msg, err := messages.DeserializePFCPAssociationSetupRequest(ie.Serialize(nodeID))
+	if err != nil {
+		t.Fatalf("Error deserializing PFCPAssociationSetupRequest: %v", err)
+	}
+
+	expectValidationError(t, msg.Validate(), ie.MandatoryIEMissing, ie.RecoveryTimeStampIEType)
+}
+
+func TestGivenCompleteSetupRequestWhenValidateThenNoError(t *testing.T) {
+	nodeID, err := ie.NewNodeID("1.2.3.4")
+	if err != nil {
+		t.Fatalf("Error creating NodeID: %v", err)
+	}
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating RecoveryTimeStamp: %v", err)
+	}
+
+	err = messages.PFCPAssociationSetupRequest{NodeID: nodeID, RecoveryTimeStamp: recoveryTimeStamp}.Validate()
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestGivenAcceptedEstablishmentResponseWithoutUPFSEIDWhenValidateThenConditionalIEMissing(t *testing.T) {
+	nodeID, err := ie.NewNodeID("1.2.3.4")
+	if err != nil {
+		t.Fatalf("Error creating NodeID: %v", err)
+	}
+	cause, err := ie.NewCause(ie.RequestAccepted)
+	if err != nil {
+		t.Fatalf("Error creating Cause: %v", err)
+	}
+
+	err = messages.PFCPSessionEstablishmentResponse{NodeID: nodeID, Cause: cause}.Validate()
+
+	expectValidationError(t, err, ie.ConditionalIEMissing, ie.FSEIDIEType)
+}
+
+func TestGivenUnknownCauseWhenValidateThenMandatoryIEIncorrect(t *testing.T) {
+	err := messages.PFCPSessionDeletionResponse{Cause: ie.Cause{Value: 200}}.Validate()
+
+	expectValidationError(t, err, ie.MandatoryIEIncorrect, ie.CauseIEType)
+}
+
+func TestGivenEstablishmentRequestWithoutCPFSEIDWhenValidateThenMandatoryIEMissing(t *testing.T) {
+	nodeID, err := ie.NewNodeID("1.2.3.4")
+	if err != nil {
+		t.Fatalf("Error creating NodeID: %v", err)
+	}
+
+	msg, err := messages.DeserializePFCPSessionEstablishmentRequest(ie.Serialize(nodeID))
+	if err != nil {
+		t.Fatalf("Error deserializing PFCPSessionEstablishmentRequest: %v", err)
+	}
+
+	expectValidationError(t, msg.Validate(), ie.MandatoryIEMissing, ie.FSEIDIEType)
+}
+
+func TestGivenEstablishmentRequestWithFSEIDWithoutAddressWhenValidateThenMandatoryIEIncorrect(t *testing.T) {
+	nodeID, err := ie.NewNodeID("1.2.3.4")
+	if err != nil {
+		t.Fatalf("Error creating NodeID: %v", err)
+	}
+
+	err = messages.PFCPSessionEstablishmentRequest{NodeID: nodeID, CPFSEID: ie.FSEID{SEID: 1}}.Validate()
+
+	expectValidationError(t, err, ie.MandatoryIEIncorrect, ie.FSEIDIEType)
+}
+
+func TestGivenSetupRequestWithZeroRecoveryTimeStampWhenValidateThenNoError(t *testing.T) {
+	nodeID, err := ie.NewNodeID("1.2.3.4")
+	if err != nil {
+		t.Fatalf("Error creating NodeID: %v", err)
+	}
+	recoveryTimeStamp := ie.RecoveryTimeStamp{Value: 0}
+	payload := messages.Serialize(messages.PFCPAssociationSetupRequest{NodeID: nodeID, RecoveryTimeStamp: recoveryTimeStamp}, messages.NewNodeHeader(messages.PFCPAssociationSetupRequestMessageType, 1))
+
+	msg, err := messages.DeserializePFCPAssociationSetupRequest(payload[8:])
+	if err != nil {
+		t.Fatalf("Error deserializing PFCPAssociationSetupRequest: %v", err)
+	}
+
+	if err := msg.Validate(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+// Package metrics records the PFCP traffic and procedure outcomes of clients and servers.
+package metrics
+
+import (
+	"time"
+
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+)
+
+// Metrics is notified of the messages exchanged by clients and servers.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// MessageSent is called for every message sent, retransmissions included.
+	MessageSent(messageType messages.MessageType)
+	// MessageReceived is called for every message received and decoded.
+	MessageReceived(messageType messages.MessageType)
+	// DecodeError is called for every datagram received that could not be decoded.
+	DecodeError()
+	// Retransmission is called when a request is sent again after T1 expired.
+	Retransmission(requestType messages.MessageType)
+	// Timeout is called when no response is received after all retransmissions of a request.
+	Timeout(requestType messages.MessageType)
+	// ResponseLatency is called with the time from the first transmission of a request to its response.
+	ResponseLatency(requestType messages.MessageType, latency time.Duration)
+	// Cause is called for every message sent or received carrying a Cause.
+	Cause(messageType messages.MessageType, cause ie.CauseValue)
+}
+
+// Noop discards all metrics. It is used when no Metrics is configured.
+type Noop struct{}
+
+var _ Metrics = Noop{}
+
+func (Noop) MessageSent(messages.MessageType)                    {}
+func (Noop) MessageReceived(messages.MessageType)                {}
+func (Noop) DecodeError()                                        {}
+func (Noop) Retransmission(messages.MessageType)                 {}
+func (Noop) Timeout(messages.MessageType)                        {}
+func (Noop) ResponseLatency(messages.MessageType, time.Duration) {}
+func (Noop) Cause(messages.MessageType, ie.CauseValue)           {}
+
+// CauseOf returns the Cause carried by message, if any.
+func CauseOf(message messages.PFCPMessage) (ie.CauseValue, bool) {
+	for _, element := range message.GetIEs() {
+		if cause, ok := element.(ie.Cause); ok {
+			return cause.Value, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the response latency histogram.
+var DefaultLatencyBuckets = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 3, 10}
+
+// Prometheus keeps metrics in memory and serves them in the Prometheus text exposition format.
+type Prometheus struct {
+	mu              sync.Mutex
+	buckets         []float64
+	sent            map[string]uint64
+	received        map[string]uint64
+	decodeErrors    uint64
+	retransmissions map[string]uint64
+	timeouts        map[string]uint64
+	causes          map[string]uint64
+	latencies       map[string]*histogram
+}
+
+var _ Metrics = (*Prometheus)(nil)
+var _ http.Handler = (*Prometheus)(nil)
+
+type histogram struct {
+	counts []uint64 // Per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// NewPrometheus returns an empty Prometheus exporter using DefaultLatencyBuckets.
+func NewPrometheus() *Prometheus {
+	return &Prometheus{
+		buckets:         DefaultLatencyBuckets,
+		sent:            make(map[string]uint64),
+		received:        make(map[string]uint64),
+		retransmissions: make(map[string]uint64),
+		timeouts:        make(map[string]uint64),
+		causes:          make(map[string]uint64),
+		latencies:       make(map[string]*histogram),
+	}
+}
+
+func messageTypeLabels(messageType messages.MessageType) string {
+	return fmt.Sprintf(`message_type="%d"`, messageType)
+}
+
+func (p *Prometheus) MessageSent(messageType messages.MessageType) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent[messageTypeLabels(messageType)]++
+}
+
+func (p *Prometheus) MessageReceived(messageType messages.MessageType) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.received[messageTypeLabels(messageType)]++
+}
+
+func (p *Prometheus) DecodeError() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.decodeErrors++
+}
+
+func (p *Prometheus) Retransmission(requestType messages.MessageType) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retransmissions[messageTypeLabels(requestType)]++
+}
+
+func (p *Prometheus) Timeout(requestType messages.MessageType) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.timeouts[messageTypeLabels(requestType)]++
+}
+
+func (p *Prometheus) ResponseLatency(requestType messages.MessageType, latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	labels := messageTypeLabels(requestType)
+	h, exists := p.latencies[labels]
+	if !exists {
+		h = &histogram{counts: make([]uint64, len(p.buckets))}
+		p.latencies[labels] = h
+	}
+	seconds := latency.Seconds()
+	for i, bound := range p.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+func (p *Prometheus) Cause(messageType messages.MessageType, cause ie.CauseValue) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.causes[fmt.Sprintf(`message_type="%d",cause="%d"`, messageType, cause)]++
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var b strings.Builder
+	writeCounters(&b, "pfcp_messages_sent_total", "PFCP messages sent.", p.sent)
+	writeCounters(&b, "pfcp_messages_received_total", "PFCP messages received.", p.received)
+	writeCounters(&b, "pfcp_decode_errors_total", "PFCP datagrams that could not be decoded.", map[string]uint64{"": p.decodeErrors})
+	writeCounters(&b, "pfcp_retransmissions_total", "PFCP requests retransmitted.", p.retransmissions)
+	writeCounters(&b, "pfcp_request_timeouts_total", "PFCP requests left without response.", p.timeouts)
+	writeCounters(&b, "pfcp_causes_total", "PFCP messages carrying a Cause.", p.causes)
+	p.writeLatencies(&b)
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func writeCounters(b *strings.Builder, name string, help string, counters map[string]uint64) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	for _, labels := range sortedKeys(counters) {
+		fmt.Fprintf(b, "%s%s %d\n", name, braces(labels), counters[labels])
+	}
+}
+
+func (p *Prometheus) writeLatencies(b *strings.Builder) {
+	const name = "pfcp_response_latency_seconds"
+	fmt.Fprintf(b, "# HELP %s Time from the first transmission of a PFCP request to its response.\n# TYPE %s histogram\n", name, name)
+	for _, labels := range sortedKeys(p.latencies) {
+		h := p.latencies[labels]
+		var cumulative uint64
+		for i, bound := range p.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(b, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(b, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.count)
+		fmt.Fprintf(b, "%s_sum{%s} %s\n", name, labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(b, "%s_count{%s} %d\n", name, labels, h.count)
+	}
+}
+
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,85 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+	"github.com/dot-5g/pfcp/metrics"
+)
+
+func TestGivenRecordedMetricsWhenServeHTTPThenPrometheusTextReturned(t *testing.T) {
+	prometheus := metrics.NewPrometheus()
+	prometheus.MessageSent(messages.HeartbeatRequestMessageType)
+	prometheus.MessageSent(messages.HeartbeatRequestMessageType)
+	prometheus.MessageReceived(messages.HeartbeatResponseMessageType)
+	prometheus.DecodeError()
+	prometheus.Retransmission(messages.HeartbeatRequestMessageType)
+	prometheus.Timeout(messages.PFCPAssociationSetupRequestMessageType)
+	prometheus.Cause(messages.PFCPAssociationSetupResponseMessageType, ie.RequestAccepted)
+	prometheus.ResponseLatency(messages.HeartbeatRequestMessageType, 3*time.Millisecond)
+	prometheus.ResponseLatency(messages.HeartbeatRequestMessageType, 2*time.Second)
+
+	recorder := httptest.NewRecorder()
+	prometheus.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", recorder.Code)
+	}
+	if !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("Unexpected content type %s", recorder.Header().Get("Content-Type"))
+	}
+	body := recorder.Body.String()
+	for _, expected := range []string{
+		"# TYPE pfcp_messages_sent_total counter",
+		`pfcp_messages_sent_total{message_type="1"} 2`,
+		`pfcp_messages_received_total{message_type="2"} 1`,
+		"pfcp_decode_errors_total 1",
+		`pfcp_retransmissions_total{message_type="1"} 1`,
+		`pfcp_request_timeouts_total{message_type="5"} 1`,
+		`pfcp_causes_total{message_type="6",cause="1"} 1`,
+		"# TYPE pfcp_response_latency_seconds histogram",
+		`pfcp_response_latency_seconds_bucket{message_type="1",le="0.001"} 0`,
+		`pfcp_response_latency_seconds_bucket{message_type="1",le="0.005"} 1`,
+		`pfcp_response_latency_seconds_bucket{message_type="1",le="1"} 1`,
+		`pfcp_response_latency_seconds_bucket{message_type="1",le="3"} 2`,
+		`pfcp_response_latency_seconds_bucket{message_type="1",le="+Inf"} 2`,
+		`pfcp_response_latency_seconds_sum{message_type="1"} 2.003`,
+		`pfcp_response_latency_seconds_count{message_type="1"} 2`,
+	} {
+		if !strings.Contains(body, expected+"\n") {
+			t.Errorf("Expected %s in:\n%s", expected, body)
+		}
+	}
+}
+
+func TestGivenNoMetricsWhenServeHTTPThenZeroDecodeErrorsReturned(t *testing.T) {
+	prometheus := metrics.NewPrometheus()
+
+	recorder := httptest.NewRecorder()
+	prometheus.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if !strings.Contains(recorder.Body.String(), "pfcp_decode_errors_total 0\n") {
+		t.Errorf("Expected pfcp_decode_errors_total 0 in:\n%s", recorder.Body.String())
+	}
+}
+
+func TestGivenMessageWithCauseWhenCauseOfThenCauseReturned(t *testing.T) {
+	cause, err := ie.NewCause(ie.MandatoryIEMissing)
+	if err != nil {
+		t.Fatalf("Error creating Cause: %v", err)
+	}
+
+	value, ok := metrics.CauseOf(messages.PFCPSessionDeletionResponse{Cause: cause})
+
+	if !ok || value != ie.MandatoryIEMissing {
+		t.Errorf("Expected cause %d, got %d", ie.MandatoryIEMissing, value)
+	}
+	if _, ok := metrics.CauseOf(messages.PFCPSessionDeletionRequest{}); ok {
+		t.Errorf("Expected no cause in a PFCP Session Deletion Request")
+	}
+}
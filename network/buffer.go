@@ -0,0 +1,51 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// MaxDatagramSize is the default largest datagram read, enough for any UDP payload.
+const MaxDatagramSize = 64 * 1024
+
+// ErrTruncated is reported when a datagram larger than the maximum datagram size is received.
+var ErrTruncated = errors.New("datagram truncated")
+
+var defaultBufferPool = newBufferPool(MaxDatagramSize)
+
+// bufferPool reuses read buffers so that reading a datagram does not allocate the maximum datagram size.
+type bufferPool struct {
+	maxDatagramSize int
+	pool            sync.Pool
+}
+
+func newBufferPool(maxDatagramSize int) *bufferPool {
+	p := &bufferPool{maxDatagramSize: maxDatagramSize}
+	p.pool.New = func() any {
+		// One extra byte tells a datagram of the maximum size from a larger, truncated one.
+		buffer := make([]byte, maxDatagramSize+1)
+		return &buffer
+	}
+	return p
+}
+
+// read reads a datagram from conn and returns a copy of it that the caller owns,
+// as decoded messages keep references to the bytes they were decoded from.
+func (p *bufferPool) read(conn net.PacketConn) ([]byte, net.Addr, error) {
+	buffer := p.pool.Get().(*[]byte)
+	defer p.pool.Put(buffer)
+
+	length, address, err := conn.ReadFrom(*buffer)
+	if err != nil {
+		return nil, address, err
+	}
+	if length > p.maxDatagramSize {
+		return nil, address, fmt.Errorf("%w: received more than %d bytes from %s", ErrTruncated, p.maxDatagramSize, address)
+	}
+
+	datagram := make([]byte, length)
+	copy(datagram, (*buffer)[:length])
+	return datagram, address, nil
+}
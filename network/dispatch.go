@@ -0,0 +1,91 @@
+package network
+
+import (
+	"net"
+	"sync"
+)
+
+// DispatchKey returns the ordering key of a datagram. Datagrams with the same key
+// are handled in the order they were received. An empty key handles the datagram on the read loop.
+type DispatchKey func(address net.Addr, payload []byte) string
+
+type datagram struct {
+	address net.Addr
+	payload []byte
+}
+
+// dispatcher runs the handler on at most a fixed number of workers. Datagrams sharing a key
+// are handled one at a time in order, while datagrams with different keys are handled in parallel.
+// Dispatch blocks once queueSize datagrams are waiting or being handled.
+type dispatcher struct {
+	key     DispatchKey
+	handler func(net.Addr, []byte)
+	workers chan struct{}
+	queued  chan struct{}
+	mu      sync.Mutex
+	pending map[string][]datagram
+	wg      sync.WaitGroup
+}
+
+func newDispatcher(workers int, queueSize int, key DispatchKey, handler func(net.Addr, []byte)) *dispatcher {
+	if queueSize < workers {
+		queueSize = workers
+	}
+	return &dispatcher{
+		key:     key,
+		handler: handler,
+		workers: make(chan struct{}, workers),
+		queued:  make(chan struct{}, queueSize),
+		pending: make(map[string][]datagram),
+	}
+}
+
+func (d *dispatcher) dispatch(address net.Addr, payload []byte) {
+	key := address.String()
+	if d.key != nil {
+		key = d.key(address, payload)
+	}
+	if key == "" {
+		d.handler(address, payload)
+		return
+	}
+
+	d.queued <- struct{}{}
+
+	d.mu.Lock()
+	queue, active := d.pending[key]
+	d.pending[key] = append(queue, datagram{address: address, payload: payload})
+	d.mu.Unlock()
+
+	if !active {
+		d.wg.Add(1)
+		go d.drain(key)
+	}
+}
+
+// drain handles the datagrams queued for key until there are none left.
+func (d *dispatcher) drain(key string) {
+	defer d.wg.Done()
+	for {
+		d.mu.Lock()
+		queue := d.pending[key]
+		if len(queue) == 0 {
+			delete(d.pending, key)
+			d.mu.Unlock()
+			return
+		}
+		dg := queue[0]
+		d.pending[key] = queue[1:]
+		d.mu.Unlock()
+
+		d.workers <- struct{}{}
+		d.handler(dg.address, dg.payload)
+		<-d.workers
+		<-d.queued
+	}
+}
+
+// stop waits for the queued datagrams to be handled.
+func (d *dispatcher) stop() {
+	d.wg.Wait()
+}
@@ -2,43 +2,108 @@
 package network
 
 import (
-	"log"
+	"errors"
+	"fmt"
 	"net"
+	"sync"
 )
 
+// UDP sends datagrams to a single peer over a long-lived socket.
+// The socket is either owned by UDP, in which case datagrams received on it are passed to the handler,
+// or shared with another reader such as a UDPServer, in which case that reader receives them instead.
 type UDP struct {
 	address *net.UDPAddr
+	conn    net.PacketConn
+	owned   bool
+	mu      sync.Mutex
+	handler func(net.Addr, []byte)
 }
 
 type UDPSender interface {
 	Send(message []byte) error
 }
 
+// NewUDP opens a socket on an ephemeral local port for sending to address.
 func NewUDP(address string) (*UDP, error) {
+	return NewUDPWithLocalAddress(address, ":0")
+}
+
+// NewUDPWithLocalAddress opens a socket bound to localAddress for sending to address.
+// This allows sending from a fixed source port such as 8805.
+func NewUDPWithLocalAddress(address string, localAddress string) (*UDP, error) {
+	udpAddress, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve UDP address: %w", err)
+	}
+	conn, err := net.ListenPacket("udp", localAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on UDP address: %w", err)
+	}
+	udp := &UDP{
+		address: udpAddress,
+		conn:    conn,
+		owned:   true,
+	}
+	go udp.listen()
+	return udp, nil
+}
+
+// NewUDPWithConn sends to address over conn, which remains owned by the caller.
+// Datagrams received on conn are not read by UDP and Close leaves conn open.
+func NewUDPWithConn(address string, conn net.PacketConn) (*UDP, error) {
+	if conn == nil {
+		return nil, errors.New("connection is nil")
+	}
 	udpAddress, err := net.ResolveUDPAddr("udp", address)
 	if err != nil {
-		log.Printf("Error resolving UDP address: %s\n", err)
-		return nil, err
+		return nil, fmt.Errorf("failed to resolve UDP address: %w", err)
 	}
 	return &UDP{
 		address: udpAddress,
+		conn:    conn,
 	}, nil
 }
 
+// SetHandler registers the function called for every datagram received on an owned socket.
+func (udp *UDP) SetHandler(handler func(net.Addr, []byte)) {
+	udp.mu.Lock()
+	defer udp.mu.Unlock()
+	udp.handler = handler
+}
+
+// LocalAddr returns the local address datagrams are sent from.
+func (udp *UDP) LocalAddr() net.Addr {
+	return udp.conn.LocalAddr()
+}
+
 func (udp *UDP) Send(message []byte) error {
-	conn, err := net.DialUDP("udp", nil, udp.address)
+	_, err := udp.conn.WriteTo(message, udp.address)
+	return err
+}
 
-	if err != nil {
-		log.Printf("Error dialing UDP: %s\n", err)
-		return err
+// Close closes the socket if it is owned by UDP.
+func (udp *UDP) Close() error {
+	if !udp.owned {
+		return nil
 	}
-	defer conn.Close()
+	return udp.conn.Close()
+}
 
-	_, err = conn.Write(message)
-	if err != nil {
-		log.Printf("Error sending message: %s\n", err)
-		return err
-	}
+func (udp *UDP) listen() {
+	for {
+		payload, remoteAddress, err := defaultBufferPool.read(udp.conn)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
 
-	return nil
+		udp.mu.Lock()
+		handler := udp.handler
+		udp.mu.Unlock()
+		if handler != nil {
+			handler(remoteAddress, payload)
+		}
+	}
 }
@@ -1,68 +1,213 @@
 package network
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 	"strings"
+	"sync"
 )
 
 type UDPServer struct {
-	conn    *net.UDPConn
-	closeCh chan struct{}
-	Handler func(net.Addr, []byte)
+	mu          sync.Mutex
+	conn        net.PacketConn
+	closeCh     chan struct{}
+	Handler     func(net.Addr, []byte)
+	workers     int
+	queueSize   int
+	dispatchKey DispatchKey
+	buffers     *bufferPool
+	onError     func(net.Addr, error)
+	drainFilter func(net.Addr, []byte) bool
+	accepting   bool
+	inflight    sync.WaitGroup
+	logger      *slog.Logger
+}
+
+type UDPServerOption func(*UDPServer)
+
+// WithWorkers makes the server handle datagrams on up to workers goroutines instead of on the read loop.
+// Datagrams with the same key, or from the same peer if key is nil, are handled in order.
+// Once queueSize datagrams are waiting or being handled, reading blocks until one completes.
+func WithWorkers(workers int, queueSize int, key DispatchKey) UDPServerOption {
+	return func(udpServer *UDPServer) {
+		udpServer.workers = workers
+		udpServer.queueSize = queueSize
+		udpServer.dispatchKey = key
+	}
+}
+
+// WithMaxDatagramSize sets the largest datagram the server reads, MaxDatagramSize by default.
+// Larger datagrams are dropped and reported as ErrTruncated.
+func WithMaxDatagramSize(size int) UDPServerOption {
+	return func(udpServer *UDPServer) {
+		udpServer.buffers = newBufferPool(size)
+	}
+}
+
+// WithErrorHandler registers a function called when a datagram cannot be read, such as ErrTruncated.
+func WithErrorHandler(handler func(address net.Addr, err error)) UDPServerOption {
+	return func(udpServer *UDPServer) {
+		udpServer.onError = handler
+	}
+}
+
+// WithDrainFilter selects the datagrams still handled after StopAccepting,
+// typically responses to requests sent from the server socket.
+func WithDrainFilter(filter func(address net.Addr, payload []byte) bool) UDPServerOption {
+	return func(udpServer *UDPServer) {
+		udpServer.drainFilter = filter
+	}
+}
+
+// WithLogger sets the logger of the server, slog.Default() by default.
+func WithLogger(logger *slog.Logger) UDPServerOption {
+	return func(udpServer *UDPServer) {
+		udpServer.logger = logger
+	}
 }
 
 func (udpServer *UDPServer) SetHandler(handler func(net.Addr, []byte)) {
 	udpServer.Handler = handler
 }
 
-func NewUDPServer() *UDPServer {
-	return &UDPServer{
-		closeCh: make(chan struct{}),
+func NewUDPServer(opts ...UDPServerOption) *UDPServer {
+	udpServer := &UDPServer{
+		closeCh:   make(chan struct{}),
+		buffers:   defaultBufferPool,
+		accepting: true,
+		logger:    slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(udpServer)
 	}
+	return udpServer
 }
 
-func (udpServer *UDPServer) Run(address string) error {
-	var err error
+// Listen binds the server socket to address without reading from it yet.
+func (udpServer *UDPServer) Listen(address string) error {
 	addr, err := net.ResolveUDPAddr("udp", address)
 	if err != nil {
 		return fmt.Errorf("failed to resolve UDP address: %w", err)
 	}
 
-	udpServer.conn, err = net.ListenUDP("udp", addr)
+	conn, err := net.ListenUDP("udp", addr)
 	if err != nil {
 		return fmt.Errorf("failed to listen on UDP address: %w", err)
 	}
 
-	log.Printf("Running PFCP server on on %s\n", addr)
+	udpServer.mu.Lock()
+	udpServer.conn = conn
+	udpServer.mu.Unlock()
 
-	err = udpServer.listen()
+	udpServer.logger.Info("Running PFCP server", slog.String("address", conn.LocalAddr().String()))
+	return nil
+}
 
-	return err
+// Conn returns the server socket, or nil if the server is not listening.
+// Clients can send over it so that their requests originate from the server address.
+func (udpServer *UDPServer) Conn() net.PacketConn {
+	udpServer.mu.Lock()
+	defer udpServer.mu.Unlock()
+	return udpServer.conn
+}
+
+func (udpServer *UDPServer) Run(address string) error {
+	err := udpServer.Listen(address)
+	if err != nil {
+		return err
+	}
+	return udpServer.Serve()
 }
 
-func (udpServer *UDPServer) listen() error {
+// Serve reads datagrams from the socket opened by Listen until the server is closed.
+func (udpServer *UDPServer) Serve() error {
+	conn := udpServer.Conn()
+	if conn == nil {
+		return errors.New("server is not listening")
+	}
+
+	var handle func(net.Addr, []byte)
+	if udpServer.Handler != nil {
+		handle = func(address net.Addr, payload []byte) {
+			defer udpServer.inflight.Done()
+			udpServer.Handler(address, payload)
+		}
+	}
+	if udpServer.workers > 0 && handle != nil {
+		d := newDispatcher(udpServer.workers, udpServer.queueSize, udpServer.dispatchKey, handle)
+		defer d.stop()
+		handle = d.dispatch
+	}
+
 	for {
 		select {
 		case <-udpServer.closeCh:
 			return nil
 		default:
-			buffer := make([]byte, 1024)
-			length, remoteAddress, err := udpServer.conn.ReadFrom(buffer)
+			payload, remoteAddress, err := udpServer.buffers.read(conn)
+			if errors.Is(err, ErrTruncated) {
+				udpServer.logger.Warn("Error reading from UDP connection",
+					slog.String("peer", remoteAddress.String()), slog.Any("error", err))
+				if udpServer.onError != nil {
+					udpServer.onError(remoteAddress, err)
+				}
+				continue
+			}
 			if err != nil {
 				if !strings.Contains(err.Error(), "use of closed network connection") {
 					return fmt.Errorf("failed to read from UDP connection: %w", err)
 				}
 				continue
 			}
-			if udpServer.Handler != nil {
-				udpServer.Handler(remoteAddress, buffer[:length])
+			if handle == nil {
+				continue
+			}
+			if udpServer.accept() {
+				handle(remoteAddress, payload)
+			} else if udpServer.drainFilter != nil && udpServer.drainFilter(remoteAddress, payload) {
+				udpServer.Handler(remoteAddress, payload)
 			}
 		}
 	}
 }
 
+// accept reports whether a datagram just read should be handled, counting it as in flight if so.
+func (udpServer *UDPServer) accept() bool {
+	udpServer.mu.Lock()
+	defer udpServer.mu.Unlock()
+	if !udpServer.accepting {
+		return false
+	}
+	udpServer.inflight.Add(1)
+	return true
+}
+
+// StopAccepting stops handling new datagrams, except those selected by the drain filter.
+// The socket stays open until Close.
+func (udpServer *UDPServer) StopAccepting() {
+	udpServer.mu.Lock()
+	defer udpServer.mu.Unlock()
+	udpServer.accepting = false
+}
+
+// Wait blocks until the datagrams accepted before StopAccepting have been handled, or until ctx is done.
+func (udpServer *UDPServer) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		udpServer.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (udpServer *UDPServer) Close() error {
 	var err error
 	select {
@@ -71,9 +216,10 @@ func (udpServer *UDPServer) Close() error {
 		close(udpServer.closeCh)
 	}
 
-	if udpServer.conn != nil {
-		err = udpServer.conn.Close()
-		log.Printf("Closed PFCP server\n")
+	conn := udpServer.Conn()
+	if conn != nil {
+		err = conn.Close()
+		udpServer.logger.Info("Closed PFCP server", slog.String("address", conn.LocalAddr().String()))
 	}
 
 	return err
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+
+	"github.com/dot-5g/pfcp/client"
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+)
+
+// MalformedMessageError is reported to the error handler when a datagram cannot be decoded
+// or, with WithValidation, when the IEs of a message do not meet the requirements of its type.
+type MalformedMessageError struct {
+	Address net.Addr
+	Header  *messages.Header // Nil when the header itself could not be decoded
+	Cause   ie.CauseValue    // Cause used when rejecting the request
+	Err     error
+}
+
+func (e *MalformedMessageError) Error() string {
+	return fmt.Sprintf("malformed message from %s: %v", e.Address, e.Err)
+}
+
+func (e *MalformedMessageError) Unwrap() error {
+	return e.Err
+}
+
+// handleMalformedMessage reports a message that could not be decoded and, if enabled,
+// rejects it when it is a request whose response carries a Cause.
+func (server *Server) handleMalformedMessage(malformed *MalformedMessageError) {
+	server.reportMalformedMessage(malformed)
+	if !server.rejectMalformedRequests || malformed.Header == nil || !malformed.Header.MessageType.IsRequest() {
+		return
+	}
+	pfcpClient := server.clientForAddress(malformed.Address)
+	if pfcpClient == nil {
+		return
+	}
+	err := server.reject(pfcpClient, *malformed.Header, malformed.Cause)
+	if err != nil {
+		server.logger.Error("Error rejecting malformed request",
+			slog.String("peer", malformed.Address.String()), slog.Any("header", *malformed.Header), slog.Any("error", err))
+	}
+}
+
+// handleInvalidMessage reports a message whose IEs do not meet the requirements of its type
+// and answers it with the Cause and Offending IE of validationErr when it is a request.
+func (server *Server) handleInvalidMessage(incoming Incoming, message messages.PFCPMessage, validationErr *messages.ValidationError) {
+	server.reportMalformedMessage(&MalformedMessageError{
+		Address: incoming.Address,
+		Header:  &incoming.Header,
+		Cause:   validationErr.Cause,
+		Err:     validationErr,
+	})
+	if !incoming.Header.MessageType.IsRequest() {
+		return
+	}
+	err := server.respond(incoming, message, nil, &CauseError{Cause: validationErr.Cause, OffendingIE: &validationErr.IEType})
+	if err != nil {
+		server.logger.Error("Error rejecting invalid request",
+			slog.String("peer", incoming.Address.String()), slog.Any("header", incoming.Header), slog.Any("error", err))
+	}
+}
+
+func (server *Server) reportMalformedMessage(malformed *MalformedMessageError) {
+	server.malformedMessages.Add(1)
+	server.metrics.DecodeError()
+	attrs := []any{slog.String("peer", malformed.Address.String()), slog.Any("error", malformed.Err)}
+	if malformed.Header != nil {
+		attrs = append(attrs, slog.Any("header", *malformed.Header))
+	}
+	server.logger.Warn("Malformed message", attrs...)
+	if server.errorHandler != nil {
+		server.errorHandler(malformed)
+	}
+}
+
+// handleReadError reports a datagram that could not be read from the socket.
+func (server *Server) handleReadError(address net.Addr, err error) {
+	server.handleMalformedMessage(&MalformedMessageError{
+		Address: address,
+		Cause:   ie.InvalidLength,
+		Err:     err,
+	})
+}
+
+// reject answers the request described by header with a response carrying cause.
+func (server *Server) reject(pfcpClient *client.PFCP, header messages.Header, causeValue ie.CauseValue) error {
+	cause, err := ie.NewCause(causeValue)
+	if err != nil {
+		return err
+	}
+	response, ok := server.rejection(header.MessageType, cause, nil)
+	if !ok {
+		return fmt.Errorf("message type %d has no response carrying a cause", header.MessageType)
+	}
+	responseType := response.GetMessageType()
+	if header.S {
+		seid, _ := server.RemoteSEID(pfcpClient.ServerAddress, header.SEID)
+		return pfcpClient.Send(response, messages.NewSessionHeader(responseType, seid, header.SequenceNumber))
+	}
+	return pfcpClient.Send(response, messages.NewNodeHeader(responseType, header.SequenceNumber))
+}
+
+// rejection returns the response to a request of requestType carrying cause and,
+// for the responses that have one, offendingIE.
+func (server *Server) rejection(requestType messages.MessageType, cause ie.Cause, offendingIE *ie.OffendingIE) (messages.PFCPMessage, bool) {
+	switch requestType {
+	case messages.PFCPAssociationSetupRequestMessageType:
+		return messages.PFCPAssociationSetupResponse{NodeID: server.nodeID, Cause: cause, RecoveryTimeStamp: server.recoveryTimeStamp}, true
+	case messages.PFCPAssociationUpdateRequestMessageType:
+		return messages.PFCPAssociationUpdateResponse{NodeID: server.nodeID, Cause: cause}, true
+	case messages.PFCPAssociationReleaseRequestMessageType:
+		return messages.PFCPAssociationReleaseResponse{NodeID: server.nodeID, Cause: cause}, true
+	case messages.PFCPNodeReportRequestMessageType:
+		return messages.PFCPNodeReportResponse{NodeID: server.nodeID, Cause: cause, OffendingIE: offendingIE}, true
+	case messages.PFCPSessionEstablishmentRequestMessageType:
+		return messages.PFCPSessionEstablishmentResponse{NodeID: server.nodeID, Cause: cause, OffendingIE: offendingIE}, true
+	case messages.PFCPSessionModificationRequestMessageType:
+		return messages.PFCPSessionModificationResponse{Cause: cause, OffendingIE: offendingIE}, true
+	case messages.PFCPSessionDeletionRequestMessageType:
+		return messages.PFCPSessionDeletionResponse{Cause: cause, OffendingIE: offendingIE}, true
+	case messages.PFCPSessionReportRequestMessageType:
+		return messages.PFCPSessionReportResponse{Cause: cause, OffendingIE: offendingIE}, true
+	default:
+		return nil, false
+	}
+}
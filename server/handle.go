@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/dot-5g/pfcp/client"
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+)
+
+// Incoming describes the message being handled. Handlers get it from their context with IncomingFromContext.
+type Incoming struct {
+	Address  net.Addr
+	Header   messages.Header
+	Client   *client.PFCP
+	Received time.Time
+
+	remoteSEID uint64
+}
+
+type incomingKey struct{}
+
+// newIncoming describes a message received by the server. For session messages, it looks up the SEID
+// allocated by the peer before the message is handled, since handling it may remove the session.
+func (server *Server) newIncoming(address net.Addr, header messages.Header, pfcpClient *client.PFCP, received time.Time) Incoming {
+	incoming := Incoming{
+		Address:  address,
+		Header:   header,
+		Client:   pfcpClient,
+		Received: received,
+	}
+	if header.S {
+		incoming.remoteSEID, _ = server.RemoteSEID(address.String(), header.SEID)
+	}
+	return incoming
+}
+
+// IncomingFromContext returns the message being handled, as given to handlers registered with Handle.
+func IncomingFromContext(ctx context.Context) (Incoming, bool) {
+	incoming, ok := ctx.Value(incomingKey{}).(Incoming)
+	return incoming, ok
+}
+
+// Next continues the handling of an incoming message and returns the response to send, if any.
+type Next func(ctx context.Context, message messages.PFCPMessage) (messages.PFCPMessage, error)
+
+// Interceptor wraps the handling of incoming messages, described by IncomingFromContext.
+// It calls next to continue, or returns a response or an error without calling it to answer
+// the request itself, as a handler registered with HandleRequest does.
+type Interceptor func(ctx context.Context, message messages.PFCPMessage, next Next) (messages.PFCPMessage, error)
+
+// Handle registers handler for the messages of type T, replacing any handler registered for it.
+// T is the type returned by the deserializer registered with messages.Register, and its
+// GetMessageType must not depend on the value it is called on. A nil handler removes the registration.
+func Handle[T messages.PFCPMessage](server *Server, handler func(ctx context.Context, msg T)) {
+	if handler == nil {
+		register[T](server, nil)
+		return
+	}
+	register(server, func(ctx context.Context, msg T) (messages.PFCPMessage, error) {
+		handler(ctx, msg)
+		return nil, nil
+	})
+}
+
+// register sets the handler of the messages of type T, or removes it if handler is nil.
+func register[T messages.PFCPMessage](server *Server, handler func(ctx context.Context, msg T) (messages.PFCPMessage, error)) {
+	var zero T
+	messageType := zero.GetMessageType()
+
+	server.handlersMu.Lock()
+	defer server.handlersMu.Unlock()
+	if handler == nil {
+		delete(server.handlers, messageType)
+		return
+	}
+	server.handlers[messageType] = func(ctx context.Context, message messages.PFCPMessage) (messages.PFCPMessage, error) {
+		msg, ok := message.(T)
+		if !ok {
+			return nil, fmt.Errorf("handler for message type %d expects %T, got %T", messageType, zero, message)
+		}
+		return handler(ctx, msg)
+	}
+}
+
+// Use adds interceptors around the handling of incoming messages, after those already added.
+func (server *Server) Use(interceptors ...Interceptor) {
+	server.handlersMu.Lock()
+	defer server.handlersMu.Unlock()
+	server.interceptors = append(server.interceptors, interceptors...)
+}
+
+// UseOutbound adds interceptors around the messages sent by the clients of the server, inside those
+// added with WithOutboundInterceptors and after those already added. They also apply to the clients
+// created before.
+func (server *Server) UseOutbound(interceptors ...client.Interceptor) {
+	server.handlersMu.Lock()
+	defer server.handlersMu.Unlock()
+	server.outbound = append(server.outbound, interceptors...)
+}
+
+// interceptOutbound passes the messages sent by the clients of the server through the interceptors
+// added with UseOutbound.
+func (server *Server) interceptOutbound(ctx context.Context, peer net.Addr, message messages.PFCPMessage, header messages.Header, invoker client.Invoker) (messages.PFCPMessage, error) {
+	server.handlersMu.RLock()
+	interceptors := server.outbound
+	server.handlersMu.RUnlock()
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, message messages.PFCPMessage, header messages.Header) (messages.PFCPMessage, error) {
+			return interceptor(ctx, peer, message, header, next)
+		}
+	}
+	return invoker(ctx, message, header)
+}
+
+// HandleFallback registers handler for the messages with no handler registered with Handle.
+// Messages of a type with no registered deserializer are passed as an UnknownMessage.
+func (server *Server) HandleFallback(handler func(ctx context.Context, msg messages.PFCPMessage)) {
+	server.handlersMu.Lock()
+	defer server.handlersMu.Unlock()
+	if handler == nil {
+		server.fallback = nil
+		return
+	}
+	server.fallback = func(ctx context.Context, message messages.PFCPMessage) (messages.PFCPMessage, error) {
+		handler(ctx, message)
+		return nil, nil
+	}
+}
+
+func (server *Server) hasFallback() bool {
+	server.handlersMu.RLock()
+	defer server.handlersMu.RUnlock()
+	return server.fallback != nil
+}
+
+// dispatch passes message through the interceptors to the handler registered for its type,
+// or the fallback handler, and sends the response to requests they return.
+func (server *Server) dispatch(address net.Addr, pfcpClient *client.PFCP, header messages.Header, message messages.PFCPMessage, received time.Time) {
+	server.handlersMu.RLock()
+	handle, exists := server.handlers[header.MessageType]
+	if !exists {
+		handle = server.fallback
+	}
+	interceptors := server.interceptors
+	server.handlersMu.RUnlock()
+
+	if handle == nil {
+		handle = func(ctx context.Context, message messages.PFCPMessage) (messages.PFCPMessage, error) {
+			server.logger.Debug("No handler for message", slog.String("message", message.GetMessageTypeString()),
+				slog.String("peer", address.String()), slog.Any("header", header))
+			return nil, nil
+		}
+	}
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handle
+		handle = func(ctx context.Context, message messages.PFCPMessage) (messages.PFCPMessage, error) {
+			return interceptor(ctx, message, next)
+		}
+	}
+
+	incoming := server.newIncoming(address, header, pfcpClient, received)
+	response, err := handle(context.WithValue(server.ctx, incomingKey{}, incoming), message)
+	if response == nil && err == nil {
+		return
+	}
+	if !header.MessageType.IsRequest() {
+		server.logger.Warn("Not responding to message", slog.String("message", message.GetMessageTypeString()),
+			slog.String("peer", address.String()), slog.Any("header", header), slog.Any("error", err))
+		return
+	}
+	err = server.respond(incoming, message, response, err)
+	if err != nil {
+		server.logger.Error("Error responding to request", slog.String("message", message.GetMessageTypeString()),
+			slog.String("peer", address.String()), slog.Any("header", header), slog.Any("error", err))
+	}
+}
+
+// handleNode registers a handler with the signature of the node message setters.
+func handleNode[T messages.PFCPMessage](server *Server, handler func(client *client.PFCP, sequenceNumber uint32, msg T)) {
+	if handler == nil {
+		Handle[T](server, nil)
+		return
+	}
+	Handle(server, func(ctx context.Context, msg T) {
+		incoming, _ := IncomingFromContext(ctx)
+		handler(incoming.Client, incoming.Header.SequenceNumber, msg)
+	})
+}
+
+// handleSession registers a handler with the signature of the session message setters.
+func handleSession[T messages.PFCPMessage](server *Server, handler func(client *client.PFCP, sequenceNumber uint32, seid uint64, msg T)) {
+	if handler == nil {
+		Handle[T](server, nil)
+		return
+	}
+	Handle(server, func(ctx context.Context, msg T) {
+		incoming, _ := IncomingFromContext(ctx)
+		handler(incoming.Client, incoming.Header.SequenceNumber, incoming.Header.SEID, msg)
+	})
+}
+
+// UnknownMessage is a message of a type with no registered deserializer, passed to the fallback handler.
+type UnknownMessage struct {
+	MessageType messages.MessageType
+	Payload     []byte // The information elements following the header
+}
+
+func newUnknownMessage(header messages.Header, data []byte) UnknownMessage {
+	payloadOffset := 8
+	if header.S {
+		payloadOffset = 16
+	}
+	return UnknownMessage{
+		MessageType: header.MessageType,
+		Payload:     data[payloadOffset : int(header.MessageLength)+4],
+	}
+}
+
+// GetIEs returns the information elements of the payload that could be decoded.
+func (msg UnknownMessage) GetIEs() []ie.InformationElement {
+	ies, _ := ie.DeserializeInformationElements(msg.Payload)
+	return ies
+}
+
+func (msg UnknownMessage) GetMessageType() messages.MessageType {
+	return msg.MessageType
+}
+
+func (msg UnknownMessage) GetMessageTypeString() string {
+	return fmt.Sprintf("Unknown Message Type %d", msg.MessageType)
+}
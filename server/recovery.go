@@ -0,0 +1,118 @@
+package server
+
+import (
+	"log/slog"
+	"net"
+	"sync"
+
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+)
+
+// PeerRestart reports a peer whose Recovery Time Stamp increased, meaning it restarted
+// and lost the state of every session it had with the server.
+type PeerRestart struct {
+	Address  string
+	NodeID   *ie.NodeID // Nil when no association message was exchanged with the peer
+	Previous ie.RecoveryTimeStamp
+	Current  ie.RecoveryTimeStamp
+	Sessions []uint64 // Local SEIDs of the sessions with the peer, which must be considered lost
+}
+
+// WithPeerRestartHandler registers a function called when a peer is detected to have restarted
+// from the Recovery Time Stamp of its Heartbeat and PFCP Association Setup messages.
+// It is called on the goroutine handling the message and must not block.
+func WithPeerRestartHandler(handler func(PeerRestart)) Option {
+	return func(server *Server) {
+		server.onPeerRestart = handler
+	}
+}
+
+// recoveryTable keeps the last Recovery Time Stamp seen from each peer. Peers are identified by
+// their Node ID once an association message was exchanged with them and by their address until then.
+type recoveryTable struct {
+	mu         sync.Mutex
+	nodeIDs    map[string]ie.NodeID
+	timestamps map[string]ie.RecoveryTimeStamp
+}
+
+func newRecoveryTable() *recoveryTable {
+	return &recoveryTable{
+		nodeIDs:    make(map[string]ie.NodeID),
+		timestamps: make(map[string]ie.RecoveryTimeStamp),
+	}
+}
+
+func nodeKey(nodeID ie.NodeID) string {
+	if nodeID.Type == ie.FQDN {
+		return "fqdn/" + string(nodeID.Value)
+	}
+	return "ip/" + net.IP(nodeID.Value).String()
+}
+
+// observe records the Recovery Time Stamp sent by the peer and returns the one seen before it
+// if it is older. nodeID is nil for messages that do not carry a Node ID.
+func (table *recoveryTable) observe(peer string, nodeID *ie.NodeID, timestamp ie.RecoveryTimeStamp) (*ie.NodeID, ie.RecoveryTimeStamp, bool) {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	addressKey := "address/" + peer
+	if nodeID != nil {
+		table.nodeIDs[peer] = *nodeID
+		if previous, exists := table.timestamps[addressKey]; exists {
+			delete(table.timestamps, addressKey)
+			if _, known := table.timestamps[nodeKey(*nodeID)]; !known {
+				table.timestamps[nodeKey(*nodeID)] = previous
+			}
+		}
+	}
+
+	key := addressKey
+	if known, exists := table.nodeIDs[peer]; exists {
+		nodeID = &known
+		key = nodeKey(known)
+	}
+	previous, seen := table.timestamps[key]
+	if seen && timestamp.Value <= previous.Value {
+		return nodeID, previous, false
+	}
+	table.timestamps[key] = timestamp
+	return nodeID, previous, seen
+}
+
+// observeRecovery detects the restart of the peer from the Recovery Time Stamp carried by message.
+func (server *Server) observeRecovery(peer string, message messages.PFCPMessage) {
+	var nodeID *ie.NodeID
+	var timestamp ie.RecoveryTimeStamp
+	switch msg := message.(type) {
+	case messages.HeartbeatRequest:
+		timestamp = msg.RecoveryTimeStamp
+	case messages.HeartbeatResponse:
+		timestamp = msg.RecoveryTimeStamp
+	case messages.PFCPAssociationSetupRequest:
+		nodeID, timestamp = &msg.NodeID, msg.RecoveryTimeStamp
+	case messages.PFCPAssociationSetupResponse:
+		nodeID, timestamp = &msg.NodeID, msg.RecoveryTimeStamp
+	default:
+		return
+	}
+
+	nodeID, previous, restarted := server.recoveries.observe(peer, nodeID, timestamp)
+	if !restarted {
+		return
+	}
+	restart := PeerRestart{
+		Address:  peer,
+		NodeID:   nodeID,
+		Previous: previous,
+		Current:  timestamp,
+		Sessions: server.peerSessions(peer),
+	}
+	server.logger.Warn("Peer restarted",
+		slog.String("peer", peer),
+		slog.Int64("previous_recovery_time_stamp", previous.Value),
+		slog.Int64("recovery_time_stamp", timestamp.Value),
+		slog.Int("lost_sessions", len(restart.Sessions)))
+	if server.onPeerRestart != nil {
+		server.onPeerRestart(restart)
+	}
+}
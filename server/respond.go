@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+)
+
+// CauseError makes a handler registered with HandleRequest answer with the response carrying Cause
+// and, for the responses that have one, the Offending IE of type OffendingIE.
+type CauseError struct {
+	Cause       ie.CauseValue
+	OffendingIE *ie.IEType
+}
+
+func (e *CauseError) Error() string {
+	return fmt.Sprintf("request rejected with cause %d", e.Cause)
+}
+
+// Reject returns an error answering the request with the response carrying cause.
+func Reject(cause ie.CauseValue) error {
+	return &CauseError{Cause: cause}
+}
+
+// HandleRequest registers handler for the requests of type T. The response it returns is sent
+// with the sequence number of the request and, for session messages, the SEID allocated by the peer.
+// If it returns an error, the response carrying the Cause of a *CauseError, or Request rejected
+// for other errors, is sent instead. Nothing is sent when both are nil. Message types added
+// with messages.Register are only answered when registered with messages.AnsweredWith.
+func HandleRequest[T messages.PFCPMessage](server *Server, handler func(ctx context.Context, req T) (messages.PFCPMessage, error)) {
+	register(server, handler)
+}
+
+// respond sends the response to the request described by incoming, or the response carrying
+// the Cause of handlerErr if it is not nil.
+func (server *Server) respond(incoming Incoming, request messages.PFCPMessage, response messages.PFCPMessage, handlerErr error) error {
+	if handlerErr != nil {
+		causeValue := ie.RequestRejected
+		var offendingIE *ie.OffendingIE
+		var causeErr *CauseError
+		if errors.As(handlerErr, &causeErr) {
+			causeValue = causeErr.Cause
+			if causeErr.OffendingIE != nil {
+				offendingIE = &ie.OffendingIE{TypeOfOffendingIE: *causeErr.OffendingIE}
+			}
+		}
+		cause, err := ie.NewCause(causeValue)
+		if err != nil {
+			return err
+		}
+		var ok bool
+		response, ok = server.rejection(incoming.Header.MessageType, cause, offendingIE)
+		if !ok {
+			return fmt.Errorf("message type %d has no response carrying a cause: %w", incoming.Header.MessageType, handlerErr)
+		}
+	}
+	if response == nil {
+		return nil
+	}
+
+	responseType := response.GetMessageType()
+	if expected, _ := incoming.Header.MessageType.ResponseType(); responseType != expected {
+		return fmt.Errorf("%s does not answer %s", response.GetMessageTypeString(), request.GetMessageTypeString())
+	}
+	if incoming.Header.S {
+		seid := server.responseSEID(incoming, request, response)
+		return incoming.Client.Send(response, messages.NewSessionHeader(responseType, seid, incoming.Header.SequenceNumber))
+	}
+	return incoming.Client.Send(response, messages.NewNodeHeader(responseType, incoming.Header.SequenceNumber))
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/dot-5g/pfcp/messages"
+	"github.com/dot-5g/pfcp/network"
+)
+
+// responseCache remembers the requests received from each peer and the responses sent to them,
+// so that a retransmitted request is answered with the previous response instead of being handled again.
+type responseCache struct {
+	mu        sync.Mutex
+	window    time.Duration
+	entries   map[responseCacheKey]*responseCacheEntry
+	lastSweep time.Time
+}
+
+type responseCacheKey struct {
+	peer           string
+	sequenceNumber uint32
+}
+
+type responseCacheEntry struct {
+	response []byte
+	expires  time.Time
+}
+
+func newResponseCache(window time.Duration) *responseCache {
+	return &responseCache{
+		window:  window,
+		entries: make(map[responseCacheKey]*responseCacheEntry),
+	}
+}
+
+// begin records a request received from peer. If the request was already received within the window,
+// duplicate is true and response holds the bytes sent in reply, or nil if the request is still being handled.
+func (cache *responseCache) begin(peer string, sequenceNumber uint32) (response []byte, duplicate bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	now := time.Now()
+	cache.sweep(now)
+
+	key := responseCacheKey{peer: peer, sequenceNumber: sequenceNumber}
+	if entry, exists := cache.entries[key]; exists && now.Before(entry.expires) {
+		return entry.response, true
+	}
+	cache.entries[key] = &responseCacheEntry{expires: now.Add(cache.window)}
+	return nil, false
+}
+
+// store saves the response sent to peer for a request recorded with begin.
+func (cache *responseCache) store(peer string, sequenceNumber uint32, response []byte) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, exists := cache.entries[responseCacheKey{peer: peer, sequenceNumber: sequenceNumber}]
+	if !exists || entry.response != nil {
+		return
+	}
+	entry.response = append([]byte{}, response...)
+	entry.expires = time.Now().Add(cache.window)
+}
+
+func (cache *responseCache) sweep(now time.Time) {
+	if now.Sub(cache.lastSweep) < cache.window {
+		return
+	}
+	for key, entry := range cache.entries {
+		if !now.Before(entry.expires) {
+			delete(cache.entries, key)
+		}
+	}
+	cache.lastSweep = now
+}
+
+// responseRecorder stores the responses sent to a peer in the response cache.
+type responseRecorder struct {
+	network.UDPSender
+	cache *responseCache
+	peer  string
+}
+
+func (recorder *responseRecorder) Send(message []byte) error {
+	err := recorder.UDPSender.Send(message)
+	if err != nil {
+		return err
+	}
+	header, err := messages.DeserializeHeader(message)
+	if err == nil && header.MessageType.IsResponse() {
+		recorder.cache.store(recorder.peer, header.SequenceNumber, message)
+	}
+	return nil
+}
+
+func (recorder *responseRecorder) Close() error {
+	if closer, ok := recorder.UDPSender.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
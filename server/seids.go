@@ -0,0 +1,158 @@
+package server
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+)
+
+type seidKey struct {
+	peer  string
+	local uint64
+}
+
+// seidTable maps the local SEID of each session to the SEID allocated for it by the peer,
+// which is the SEID put in the header of the messages sent to the peer for that session.
+type seidTable struct {
+	mu     sync.Mutex
+	remote map[seidKey]uint64
+}
+
+func newSEIDTable() *seidTable {
+	return &seidTable{remote: make(map[seidKey]uint64)}
+}
+
+func (table *seidTable) learn(peer string, local uint64, remote uint64) {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	table.remote[seidKey{peer: peer, local: local}] = remote
+}
+
+func (table *seidTable) lookup(peer string, local uint64) (uint64, bool) {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	remote, exists := table.remote[seidKey{peer: peer, local: local}]
+	return remote, exists
+}
+
+func (table *seidTable) forget(peer string, local uint64) {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	delete(table.remote, seidKey{peer: peer, local: local})
+}
+
+// forgetPeer removes the sessions with the peer and returns their local SEIDs.
+func (table *seidTable) forgetPeer(peer string) []uint64 {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	var sessions []uint64
+	for key := range table.remote {
+		if key.peer == peer {
+			sessions = append(sessions, key.local)
+			delete(table.remote, key)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i] < sessions[j] })
+	return sessions
+}
+
+// SessionStore holds the sessions of a server with its peers. The server uses it to put the SEID
+// allocated by the peer in the header of its session responses and to report the sessions lost
+// when a peer restarts. By default, the server learns the SEIDs from the F-SEIDs it exchanges.
+type SessionStore interface {
+	// RemoteSEID returns the SEID allocated by the peer at address for the session with the local SEID seid.
+	RemoteSEID(address string, seid uint64) (uint64, bool)
+	// PeerSessions returns the local SEIDs of the sessions with the peer at address, in ascending order.
+	PeerSessions(address string) []uint64
+}
+
+// UseSessionStore makes store the only source of the sessions of the server, which stops learning SEIDs.
+func (server *Server) UseSessionStore(store SessionStore) {
+	server.handlersMu.Lock()
+	defer server.handlersMu.Unlock()
+	server.sessionStore = store
+}
+
+func (server *Server) sessions() SessionStore {
+	server.handlersMu.RLock()
+	defer server.handlersMu.RUnlock()
+	return server.sessionStore
+}
+
+// RemoteSEID returns the SEID allocated by the peer at address for the session with the local SEID seid.
+// Unless a SessionStore is used, it is learned from the F-SEIDs exchanged in Session Establishment and Modification.
+func (server *Server) RemoteSEID(address string, seid uint64) (uint64, bool) {
+	if store := server.sessions(); store != nil {
+		return store.RemoteSEID(address, seid)
+	}
+	return server.seids.lookup(address, seid)
+}
+
+// peerSessions returns the local SEIDs of the sessions with the peer, which the server forgets
+// unless they are held by a SessionStore.
+func (server *Server) peerSessions(peer string) []uint64 {
+	if store := server.sessions(); store != nil {
+		return store.PeerSessions(peer)
+	}
+	return server.seids.forgetPeer(peer)
+}
+
+// responseSEID returns the SEID of the response sent to the session request described by incoming
+// and, unless a SessionStore is used, learns the SEIDs carried by the request and its response.
+func (server *Server) responseSEID(incoming Incoming, request messages.PFCPMessage, response messages.PFCPMessage) uint64 {
+	learn := server.sessions() == nil
+	peer := incoming.Address.String()
+	switch req := request.(type) {
+	case messages.PFCPSessionEstablishmentRequest:
+		resp, ok := response.(messages.PFCPSessionEstablishmentResponse)
+		if learn && ok && resp.Cause.Value == ie.RequestAccepted && resp.UPFSEID != nil {
+			server.seids.learn(peer, resp.UPFSEID.SEID, req.CPFSEID.SEID)
+		}
+		return req.CPFSEID.SEID
+	case messages.PFCPSessionModificationRequest:
+		if req.CPFSEID != nil {
+			if learn {
+				server.seids.learn(peer, incoming.Header.SEID, req.CPFSEID.SEID)
+			}
+			return req.CPFSEID.SEID
+		}
+	case messages.PFCPSessionDeletionRequest:
+		resp, ok := response.(messages.PFCPSessionDeletionResponse)
+		if learn && ok && resp.Cause.Value == ie.RequestAccepted {
+			server.seids.forget(peer, incoming.Header.SEID)
+		}
+	}
+	return incoming.remoteSEID
+}
+
+// observeResponse learns the SEIDs carried by the session responses received for requests sent to the peer
+// and detects the restart of the peer from the Recovery Time Stamp of its node responses.
+func (server *Server) observeResponse(peer string, header messages.Header, payload []byte) {
+	learn := server.sessions() == nil
+	switch header.MessageType {
+	case messages.PFCPSessionEstablishmentResponseMessageType:
+		if !learn {
+			return
+		}
+		_, message, err := messages.Decode(payload)
+		if err != nil {
+			return
+		}
+		response, ok := message.(messages.PFCPSessionEstablishmentResponse)
+		if ok && response.Cause.Value == ie.RequestAccepted && response.UPFSEID != nil {
+			server.seids.learn(peer, header.SEID, response.UPFSEID.SEID)
+		}
+	case messages.PFCPSessionDeletionResponseMessageType:
+		if learn {
+			server.seids.forget(peer, header.SEID)
+		}
+	case messages.HeartbeatResponseMessageType, messages.PFCPAssociationSetupResponseMessageType:
+		_, message, err := messages.Decode(payload)
+		if err != nil {
+			return
+		}
+		server.observeRecovery(peer, message)
+	}
+}
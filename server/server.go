@@ -2,11 +2,19 @@
 package server
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/dot-5g/pfcp/client"
+	"github.com/dot-5g/pfcp/ie"
 	"github.com/dot-5g/pfcp/messages"
+	"github.com/dot-5g/pfcp/metrics"
 	"github.com/dot-5g/pfcp/network"
 )
 
@@ -22,54 +30,294 @@ type HandlePFCPNodeReportRequest func(client *client.PFCP, sequenceNumber uint32
 type HandlePFCPNodeReportResponse func(client *client.PFCP, sequenceNumber uint32, msg messages.PFCPNodeReportResponse)
 type HandlePFCPSessionEstablishmentRequest func(client *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionEstablishmentRequest)
 type HandlePFCPSessionEstablishmentResponse func(client *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionEstablishmentResponse)
+type HandlePFCPSessionModificationRequest func(client *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionModificationRequest)
+type HandlePFCPSessionModificationResponse func(client *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionModificationResponse)
 type HandlePFCPSessionDeletionRequest func(client *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionDeletionRequest)
 type HandlePFCPSessionDeletionResponse func(client *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionDeletionResponse)
 type HandlePFCPSessionReportRequest func(client *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionReportRequest)
 type HandlePFCPSessionReportResponse func(client *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionReportResponse)
 
+// DefaultResponseCacheWindow is how long a response is kept to answer retransmissions of its request.
+// It covers every retransmission a peer using the default T1 and N1 can make.
+const DefaultResponseCacheWindow = client.DefaultT1 * (client.DefaultN1 + 1)
+
+// DefaultShutdownTimeout bounds the graceful shutdown triggered by the context given to Run.
+// It leaves time for a request sent with the default T1 and N1 to be answered or to time out.
+const DefaultShutdownTimeout = client.DefaultT1 * (client.DefaultN1 + 1)
+
 type Server struct {
-	address   string
-	udpServer *network.UDPServer
-	clients   map[string]*client.PFCP
-
-	heartbeatRequestHandler                 HandleHeartbeatRequest
-	heartbeatResponseHandler                HandleHeartbeatResponse
-	pfcpAssociationSetupRequestHandler      HandlePFCPAssociationSetupRequest
-	pfcpAssociationSetupResponseHandler     HandlePFCPAssociationSetupResponse
-	pfcpAssociationUpdateRequestHandler     HandlePFCPAssociationUpdateRequest
-	pfcpAssociationUpdateResponseHandler    HandlePFCPAssociationUpdateResponse
-	pfcpAssociationReleaseRequestHandler    HandlePFCPAssociationReleaseRequest
-	pfcpAssociationReleaseResponseHandler   HandlePFCPAssociationReleaseResponse
-	pfcpNodeReportRequestHandler            HandlePFCPNodeReportRequest
-	pfcpNodeReportResponseHandler           HandlePFCPNodeReportResponse
-	pfcpSessionEstablishmentRequestHandler  HandlePFCPSessionEstablishmentRequest
-	pfcpSessionEstablishmentResponseHandler HandlePFCPSessionEstablishmentResponse
-	pfcpSessionDeletionRequestHandler       HandlePFCPSessionDeletionRequest
-	pfcpSessionDeletionResponseHandler      HandlePFCPSessionDeletionResponse
-	pfcpSessionReportRequestHandler         HandlePFCPSessionReportRequest
-	pfcpSessionReportResponseHandler        HandlePFCPSessionReportResponse
-}
-
-func New(address string) *Server {
+	address       string
+	udpServer     *network.UDPServer
+	clientsMu     sync.Mutex
+	clients       map[string]*client.PFCP
+	responseCache *responseCache
+	seids         *seidTable
+	sessionStore  SessionStore
+	recoveries    *recoveryTable
+
+	nodeID                  ie.NodeID
+	recoveryTimeStamp       ie.RecoveryTimeStamp
+	errorHandler            func(error)
+	onPeerRestart           func(PeerRestart)
+	rejectMalformedRequests bool
+	validateMessages        bool
+	malformedMessages       atomic.Uint64
+	udpServerOptions        []network.UDPServerOption
+	clientOptions           []client.Option
+	logger                  *slog.Logger
+	metrics                 metrics.Metrics
+	shutdownTimeout         time.Duration
+	ready                   chan struct{}
+	readyOnce               sync.Once
+
+	handlersMu   sync.RWMutex
+	handlers     map[messages.MessageType]Next
+	fallback     Next
+	interceptors []Interceptor
+	outbound     []client.Interceptor
+	ctx          context.Context
+	cancel       context.CancelFunc
+}
+
+type Option func(*Server)
+
+// WithResponseCacheWindow sets how long responses are kept to answer retransmitted requests.
+// A window of 0 disables duplicate request detection.
+func WithResponseCacheWindow(window time.Duration) Option {
+	return func(server *Server) {
+		if window <= 0 {
+			server.responseCache = nil
+			return
+		}
+		server.responseCache = newResponseCache(window)
+	}
+}
+
+// WithNodeID sets the Node ID used in the responses built by the server.
+// It defaults to the host of the listening address.
+func WithNodeID(nodeID ie.NodeID) Option {
+	return func(server *Server) {
+		server.nodeID = nodeID
+	}
+}
+
+// WithErrorHandler registers a function called with every error met while handling
+// an incoming datagram, such as a *MalformedMessageError.
+func WithErrorHandler(handler func(error)) Option {
+	return func(server *Server) {
+		server.errorHandler = handler
+	}
+}
+
+// WithRejectMalformedRequests makes the server answer malformed requests with a response
+// carrying the Cause of the error, for requests whose response has a Cause.
+func WithRejectMalformedRequests() Option {
+	return func(server *Server) {
+		server.rejectMalformedRequests = true
+	}
+}
+
+// WithValidation makes the server check that the IEs of incoming messages meet the requirements
+// of their type. Invalid messages are reported as a *MalformedMessageError wrapping a
+// *messages.ValidationError and requests are answered with its Cause and Offending IE.
+func WithValidation() Option {
+	return func(server *Server) {
+		server.validateMessages = true
+	}
+}
+
+// WithWorkers handles incoming messages on up to workers goroutines so that a slow handler does not
+// hold up other peers and sessions. Session messages are kept in order per peer and SEID and node
+// messages per peer. Once queueSize messages are waiting or being handled, reading from the socket
+// blocks. Responses are always handled as they are read.
+func WithWorkers(workers int, queueSize int) Option {
+	return func(server *Server) {
+		server.udpServerOptions = append(server.udpServerOptions, network.WithWorkers(workers, queueSize, dispatchKey))
+	}
+}
+
+// WithMaxDatagramSize sets the largest message the server reads, network.MaxDatagramSize by default.
+// Larger messages are dropped and reported as malformed.
+func WithMaxDatagramSize(size int) Option {
+	return func(server *Server) {
+		server.udpServerOptions = append(server.udpServerOptions, network.WithMaxDatagramSize(size))
+	}
+}
+
+// WithInterceptors adds interceptors around the handling of incoming messages.
+// The first interceptor is the outermost one.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(server *Server) {
+		server.interceptors = append(server.interceptors, interceptors...)
+	}
+}
+
+// WithOutboundInterceptors adds interceptors around the messages sent by the clients of the server,
+// including the responses to incoming requests.
+func WithOutboundInterceptors(interceptors ...client.Interceptor) Option {
+	return func(server *Server) {
+		server.clientOptions = append(server.clientOptions, client.WithInterceptors(interceptors...))
+	}
+}
+
+// WithLogger sets the logger of the server, its socket and its clients, slog.Default() by default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(server *Server) {
+		server.logger = logger
+	}
+}
+
+// WithMetrics records the traffic of the server and its clients in m.
+func WithMetrics(m metrics.Metrics) Option {
+	return func(server *Server) {
+		server.metrics = m
+	}
+}
+
+// WithShutdownTimeout bounds the graceful shutdown triggered when the context given to Run is done.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(server *Server) {
+		server.shutdownTimeout = timeout
+	}
+}
+
+func New(address string, opts ...Option) *Server {
 	server := &Server{
-		address:   address,
-		udpServer: network.NewUDPServer(),
-		clients:   make(map[string]*client.PFCP),
+		address:         address,
+		clients:         make(map[string]*client.PFCP),
+		responseCache:   newResponseCache(DefaultResponseCacheWindow),
+		seids:           newSEIDTable(),
+		recoveries:      newRecoveryTable(),
+		shutdownTimeout: DefaultShutdownTimeout,
+		ready:           make(chan struct{}),
+		handlers:        make(map[messages.MessageType]Next),
+		logger:          slog.Default(),
+		metrics:         metrics.Noop{},
+	}
+	server.ctx, server.cancel = context.WithCancel(context.Background())
+	host, _, err := net.SplitHostPort(address)
+	if err == nil {
+		server.nodeID, _ = ie.NewNodeID(host)
 	}
+	server.recoveryTimeStamp, _ = ie.NewRecoveryTimeStamp(time.Now())
+	for _, opt := range opts {
+		opt(server)
+	}
+	server.clientOptions = append([]client.Option{client.WithLogger(server.logger), client.WithMetrics(server.metrics)}, server.clientOptions...)
+	server.clientOptions = append(server.clientOptions, client.WithInterceptors(server.interceptOutbound))
+	server.udpServer = network.NewUDPServer(append(server.udpServerOptions,
+		network.WithLogger(server.logger),
+		network.WithErrorHandler(server.handleReadError),
+		network.WithDrainFilter(isResponse),
+	)...)
 	return server
 }
 
-func (server *Server) Run() error {
+func isResponse(address net.Addr, payload []byte) bool {
+	header, err := messages.DeserializeHeader(payload)
+	return err == nil && header.MessageType.IsResponse()
+}
+
+// dispatchKey orders session messages per peer and SEID and node messages per peer.
+// Responses are handled on the read loop so that a handler waiting for one cannot block it.
+func dispatchKey(address net.Addr, payload []byte) string {
+	header, err := messages.DeserializeHeader(payload)
+	if err != nil {
+		return address.String()
+	}
+	if header.MessageType.IsResponse() {
+		return ""
+	}
+	if header.S {
+		return fmt.Sprintf("%s/%d", address, header.SEID)
+	}
+	return address.String()
+}
+
+// Listen binds the server socket so that it can be shared with clients before Run is called.
+func (server *Server) Listen() error {
+	if server.udpServer.Conn() != nil {
+		return nil
+	}
+	err := server.udpServer.Listen(server.address)
+	if err != nil {
+		return err
+	}
+	server.readyOnce.Do(func() { close(server.ready) })
+	return nil
+}
+
+// Ready returns a channel closed once the server socket is bound.
+func (server *Server) Ready() <-chan struct{} {
+	return server.ready
+}
+
+// Conn returns the server socket, or nil if the server is not listening.
+func (server *Server) Conn() net.PacketConn {
+	return server.udpServer.Conn()
+}
+
+// Run handles incoming messages until ctx is done or the server is shut down or closed.
+// When ctx is done, the server is shut down gracefully within the shutdown timeout.
+func (server *Server) Run(ctx context.Context) error {
 	server.udpServer.SetHandler(server.handlePFCPMessage)
-	err := server.udpServer.Run(server.address)
+	err := server.Listen()
+	if err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), server.shutdownTimeout)
+		defer cancel()
+		err := server.Shutdown(shutdownCtx)
+		if err != nil {
+			server.logger.Error("Error shutting down PFCP server", slog.Any("error", err))
+		}
+	})
+	defer stop()
+	return server.udpServer.Serve()
+}
+
+// Shutdown stops handling new requests, waits for the messages being handled and for the requests
+// sent from the server socket to be answered or to time out, then closes the socket.
+// If ctx is done first, the socket is closed anyway and the context error is returned.
+func (server *Server) Shutdown(ctx context.Context) error {
+	server.udpServer.StopAccepting()
+	err := server.udpServer.Wait(ctx)
+	if err == nil {
+		for _, cl := range server.GetClients() {
+			if err = cl.Wait(ctx); err != nil {
+				break
+			}
+		}
+	}
+	server.Close()
 	return err
 }
 
+// Close closes the socket and cancels the context of the handlers still running.
 func (server *Server) Close() {
+	server.cancel()
 	server.udpServer.Close()
 }
 
+// NodeID returns the Node ID used in the messages built by the server.
+func (server *Server) NodeID() ie.NodeID {
+	return server.nodeID
+}
+
+// RecoveryTimeStamp returns the time the server was started, as sent to its peers.
+func (server *Server) RecoveryTimeStamp() ie.RecoveryTimeStamp {
+	return server.recoveryTimeStamp
+}
+
+// MalformedMessages returns the number of datagrams received that could not be decoded.
+func (server *Server) MalformedMessages() uint64 {
+	return server.malformedMessages.Load()
+}
+
 func (server *Server) GetClients() []*client.PFCP {
+	server.clientsMu.Lock()
+	defer server.clientsMu.Unlock()
 	clients := make([]*client.PFCP, 0)
 	for _, cl := range server.clients {
 		clients = append(clients, cl)
@@ -78,6 +326,8 @@ func (server *Server) GetClients() []*client.PFCP {
 }
 
 func (server *Server) GetClientForAddress(addr net.Addr) *client.PFCP {
+	server.clientsMu.Lock()
+	defer server.clientsMu.Unlock()
 	addrStr := addr.String()
 	if cl, exists := server.clients[addrStr]; exists {
 		return cl
@@ -94,279 +344,230 @@ func (server *Server) ClientExistsForAddress(addr net.Addr) bool {
 	}
 }
 
+// AddClient registers a client for the peer at addr. Once the server is listening,
+// the client sends from the server socket so that responses come from the server address.
 func (server *Server) AddClient(addr net.Addr) {
 	addrStr := addr.String()
-	cl := client.New(addrStr)
+	cl := server.newClient(addrStr)
+	if cl == nil {
+		return
+	}
+	server.clientsMu.Lock()
 	server.clients[addrStr] = cl
+	server.clientsMu.Unlock()
+}
+
+// NewClient returns a client for the peer at address that sends from the server socket.
+// Responses to its requests are received by the server and delivered to the client.
+// The server must be listening, see Listen.
+func (server *Server) NewClient(address string, opts ...client.Option) (*client.PFCP, error) {
+	if server.Conn() == nil {
+		return nil, errors.New("server is not listening")
+	}
+	peer, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	cl := server.newClient(peer.String(), opts...)
+	if cl == nil {
+		return nil, errors.New("failed to create client")
+	}
+	server.clientsMu.Lock()
+	server.clients[peer.String()] = cl
+	server.clientsMu.Unlock()
+	return cl, nil
+}
+
+// clientForAddress returns the client for the peer at addr, adding it if needed.
+func (server *Server) clientForAddress(addr net.Addr) *client.PFCP {
+	addrStr := addr.String()
+	server.clientsMu.Lock()
+	defer server.clientsMu.Unlock()
+	if cl, exists := server.clients[addrStr]; exists {
+		return cl
+	}
+	server.logger.Debug("Adding client", slog.String("peer", addrStr))
+	cl := server.newClient(addrStr)
+	if cl != nil {
+		server.clients[addrStr] = cl
+	}
+	return cl
+}
+
+// newClient creates a client for the peer at addrStr sending from the server socket,
+// recording its responses in the response cache.
+func (server *Server) newClient(addrStr string, opts ...client.Option) *client.PFCP {
+	opts = append(append([]client.Option{}, server.clientOptions...), opts...)
+	if conn := server.Conn(); conn != nil {
+		opts = append(opts, client.WithConn(conn))
+	}
+	cl := client.New(addrStr, opts...)
+	if cl == nil {
+		return nil
+	}
+	if server.responseCache != nil {
+		cl.Udp = &responseRecorder{UDPSender: cl.Udp, cache: server.responseCache, peer: addrStr}
+	}
+	return cl
 }
 
 func (server *Server) HeartbeatRequest(handler HandleHeartbeatRequest) {
-	server.heartbeatRequestHandler = handler
+	handleNode[messages.HeartbeatRequest](server, handler)
 }
 
 func (server *Server) HeartbeatResponse(handler HandleHeartbeatResponse) {
-	server.heartbeatResponseHandler = handler
+	handleNode[messages.HeartbeatResponse](server, handler)
 }
 
 func (server *Server) PFCPAssociationSetupRequest(handler HandlePFCPAssociationSetupRequest) {
-	server.pfcpAssociationSetupRequestHandler = handler
+	handleNode[messages.PFCPAssociationSetupRequest](server, handler)
 }
 
 func (server *Server) PFCPAssociationSetupResponse(handler HandlePFCPAssociationSetupResponse) {
-	server.pfcpAssociationSetupResponseHandler = handler
+	handleNode[messages.PFCPAssociationSetupResponse](server, handler)
 }
 
 func (server *Server) PFCPAssociationUpdateRequest(handler HandlePFCPAssociationUpdateRequest) {
-	server.pfcpAssociationUpdateRequestHandler = handler
+	handleNode[messages.PFCPAssociationUpdateRequest](server, handler)
 }
 
 func (server *Server) PFCPAssociationUpdateResponse(handler HandlePFCPAssociationUpdateResponse) {
-	server.pfcpAssociationUpdateResponseHandler = handler
+	handleNode[messages.PFCPAssociationUpdateResponse](server, handler)
 }
 
 func (server *Server) PFCPAssociationReleaseRequest(handler HandlePFCPAssociationReleaseRequest) {
-	server.pfcpAssociationReleaseRequestHandler = handler
+	handleNode[messages.PFCPAssociationReleaseRequest](server, handler)
 }
 
 func (server *Server) PFCPAssociationReleaseResponse(handler HandlePFCPAssociationReleaseResponse) {
-	server.pfcpAssociationReleaseResponseHandler = handler
+	handleNode[messages.PFCPAssociationReleaseResponse](server, handler)
 }
 
 func (server *Server) PFCPNodeReportRequest(handler HandlePFCPNodeReportRequest) {
-	server.pfcpNodeReportRequestHandler = handler
+	handleNode[messages.PFCPNodeReportRequest](server, handler)
 }
 
 func (server *Server) PFCPNodeReportResponse(handler HandlePFCPNodeReportResponse) {
-	server.pfcpNodeReportResponseHandler = handler
+	handleNode[messages.PFCPNodeReportResponse](server, handler)
 }
 
 func (server *Server) PFCPSessionEstablishmentRequest(handler HandlePFCPSessionEstablishmentRequest) {
-	server.pfcpSessionEstablishmentRequestHandler = handler
+	handleSession[messages.PFCPSessionEstablishmentRequest](server, handler)
 }
 
 func (server *Server) PFCPSessionEstablishmentResponse(handler HandlePFCPSessionEstablishmentResponse) {
-	server.pfcpSessionEstablishmentResponseHandler = handler
+	handleSession[messages.PFCPSessionEstablishmentResponse](server, handler)
+}
+
+func (server *Server) PFCPSessionModificationRequest(handler HandlePFCPSessionModificationRequest) {
+	handleSession[messages.PFCPSessionModificationRequest](server, handler)
+}
+
+func (server *Server) PFCPSessionModificationResponse(handler HandlePFCPSessionModificationResponse) {
+	handleSession[messages.PFCPSessionModificationResponse](server, handler)
 }
 
 func (server *Server) PFCPSessionDeletionRequest(handler HandlePFCPSessionDeletionRequest) {
-	server.pfcpSessionDeletionRequestHandler = handler
+	handleSession[messages.PFCPSessionDeletionRequest](server, handler)
 }
 
 func (server *Server) PFCPSessionDeletionResponse(handler HandlePFCPSessionDeletionResponse) {
-	server.pfcpSessionDeletionResponseHandler = handler
+	handleSession[messages.PFCPSessionDeletionResponse](server, handler)
 }
 
 func (server *Server) PFCPSessionReportRequest(handler HandlePFCPSessionReportRequest) {
-	server.pfcpSessionReportRequestHandler = handler
+	handleSession[messages.PFCPSessionReportRequest](server, handler)
 }
 
 func (server *Server) PFCPSessionReportResponse(handler HandlePFCPSessionReportResponse) {
-	server.pfcpSessionReportResponseHandler = handler
+	handleSession[messages.PFCPSessionReportResponse](server, handler)
 }
 
 func (server *Server) handlePFCPMessage(address net.Addr, payload []byte) {
+	received := time.Now()
 	header, err := messages.DeserializeHeader(payload)
 	if err != nil {
-		log.Fatalf("Error deserializing header: %v", err)
+		server.handleMalformedMessage(&MalformedMessageError{
+			Address: address,
+			Cause:   ie.InvalidLength,
+			Err:     fmt.Errorf("error deserializing header: %w", err),
+		})
 		return
 	}
 
-	payloadOffset := 8
-	if header.S {
-		payloadOffset = 16
-	}
-
-	if len(payload) < payloadOffset {
-		log.Fatalf("Error: payload length is less than payload offset")
+	pfcpClient := server.clientForAddress(address)
+	if pfcpClient == nil {
+		server.logger.Error("No client for peer", slog.String("peer", address.String()))
 		return
 	}
-	payloadMessage := payload[payloadOffset:]
 
-	if !server.ClientExistsForAddress(address) {
-		log.Printf("Adding client with address %s\n", address)
-		server.AddClient(address)
+	if header.MessageType.IsResponse() {
+		server.observeResponse(address.String(), header, payload)
 	}
 
-	pfcpClient := server.GetClientForAddress(address)
+	if pfcpClient.HandleMessage(address, payload) {
+		return
+	}
 
-	switch header.MessageType {
-	case messages.HeartbeatRequestMessageType:
-		if server.heartbeatRequestHandler == nil {
-			log.Printf("No handler for Heartbeat Request")
-			return
-		}
-		msg, err := messages.DeserializeHeartbeatRequest(payloadMessage)
-		if err != nil {
-			log.Printf("Error deserializing Heartbeat Request: %v", err)
-			return
-		}
-		server.heartbeatRequestHandler(pfcpClient, header.SequenceNumber, msg)
-	case messages.HeartbeatResponseMessageType:
-		if server.heartbeatResponseHandler == nil {
-			log.Printf("No handler for Heartbeat Response")
-			return
-		}
-		msg, err := messages.DeserializeHeartbeatResponse(payloadMessage)
-		if err != nil {
-			log.Printf("Error deserializing Heartbeat Response: %v", err)
-			return
-		}
-		server.heartbeatResponseHandler(pfcpClient, header.SequenceNumber, msg)
-	case messages.PFCPAssociationSetupRequestMessageType:
-		if server.pfcpAssociationSetupRequestHandler == nil {
-			log.Printf("No handler for PFCP Association Setup Request")
-			return
-		}
-		msg, err := messages.DeserializePFCPAssociationSetupRequest(payloadMessage)
-		if err != nil {
-			log.Printf("Error deserializing PFCP Association Setup Request: %v", err)
-			return
-		}
-		server.pfcpAssociationSetupRequestHandler(pfcpClient, header.SequenceNumber, msg)
-	case messages.PFCPAssociationSetupResponseMessageType:
-		if server.pfcpAssociationSetupResponseHandler == nil {
-			log.Printf("No handler for PFCP Association Setup Response")
-			return
-		}
-		msg, err := messages.DeserializePFCPAssociationSetupResponse(payloadMessage)
-		if err != nil {
-			log.Printf("Error deserializing PFCP Association Setup Response: %v", err)
-			return
-		}
-		server.pfcpAssociationSetupResponseHandler(pfcpClient, header.SequenceNumber, msg)
-	case messages.PFCPAssociationUpdateRequestMessageType:
-		if server.pfcpAssociationUpdateRequestHandler == nil {
-			log.Printf("No handler for PFCP Association Update Request")
-			return
-		}
-		msg, err := messages.DeserializePFCPAssociationUpdateRequest(payloadMessage)
-		if err != nil {
-			log.Printf("Error deserializing PFCP Association Update Request: %v", err)
-			return
-		}
-		server.pfcpAssociationUpdateRequestHandler(pfcpClient, header.SequenceNumber, msg)
-	case messages.PFCPAssociationUpdateResponseMessageType:
-		if server.pfcpAssociationUpdateResponseHandler == nil {
-			log.Printf("No handler for PFCP Association Update Response")
-			return
-		}
-		msg, err := messages.DeserializePFCPAssociationUpdateResponse(payloadMessage)
-		if err != nil {
-			log.Printf("Error deserializing PFCP Association Update Response: %v", err)
-			return
-		}
-		server.pfcpAssociationUpdateResponseHandler(pfcpClient, header.SequenceNumber, msg)
-	case messages.PFCPAssociationReleaseRequestMessageType:
-		if server.pfcpAssociationReleaseRequestHandler == nil {
-			log.Printf("No handler for PFCP Association Release Request")
-			return
-		}
-		msg, err := messages.DeserializePFCPAssociationReleaseRequest(payloadMessage)
-		if err != nil {
-			log.Printf("Error deserializing PFCP Association Release Request: %v", err)
-			return
-		}
-		server.pfcpAssociationReleaseRequestHandler(pfcpClient, header.SequenceNumber, msg)
-	case messages.PFCPAssociationReleaseResponseMessageType:
-		if server.pfcpAssociationReleaseResponseHandler == nil {
-			log.Printf("No handler for PFCP Association Release Response")
-			return
-		}
-		msg, err := messages.DeserializePFCPAssociationReleaseResponse(payloadMessage)
-		if err != nil {
-			log.Printf("Error deserializing PFCP Association Release Response: %v", err)
-			return
-		}
-		server.pfcpAssociationReleaseResponseHandler(pfcpClient, header.SequenceNumber, msg)
-	case messages.PFCPNodeReportRequestMessageType:
-		if server.pfcpNodeReportRequestHandler == nil {
-			log.Printf("No handler for PFCP Node Report Request")
-			return
-		}
-		msg, err := messages.DeserializePFCPNodeReportRequest(payloadMessage)
-		if err != nil {
-			log.Printf("Error deserializing PFCP Node Report Request: %v", err)
-			return
-		}
-		server.pfcpNodeReportRequestHandler(pfcpClient, header.SequenceNumber, msg)
-	case messages.PFCPNodeReportResponseMessageType:
-		if server.pfcpNodeReportResponseHandler == nil {
-			log.Printf("No handler for PFCP Node Report Response")
-			return
-		}
-		msg, err := messages.DeserializePFCPNodeReportResponse(payloadMessage)
-		if err != nil {
-			log.Printf("Error deserializing PFCP Node Report Response: %v", err)
-			return
-		}
-		server.pfcpNodeReportResponseHandler(pfcpClient, header.SequenceNumber, msg)
-	case messages.PFCPSessionEstablishmentRequestMessageType:
-		if server.pfcpSessionEstablishmentRequestHandler == nil {
-			log.Printf("No handler for PFCP Session Establishment Request")
-			return
-		}
-		msg, err := messages.DeserializePFCPSessionEstablishmentRequest(payloadMessage)
-		if err != nil {
-			log.Printf("Error deserializing PFCP Session Establishment Request: %v", err)
-			return
-		}
-		server.pfcpSessionEstablishmentRequestHandler(pfcpClient, header.SequenceNumber, header.SEID, msg)
-	case messages.PFCPSessionEstablishmentResponseMessageType:
-		if server.pfcpSessionEstablishmentResponseHandler == nil {
-			log.Printf("No handler for PFCP Session Establishment Response")
-			return
-		}
-		msg, err := messages.DeserializePFCPSessionEstablishmentResponse(payloadMessage)
-		if err != nil {
-			log.Printf("Error deserializing PFCP Session Establishment Response: %v", err)
-			return
-		}
-		server.pfcpSessionEstablishmentResponseHandler(pfcpClient, header.SequenceNumber, header.SEID, msg)
-	case messages.PFCPSessionDeletionRequestMessageType:
-		if server.pfcpSessionDeletionRequestHandler == nil {
-			log.Printf("No handler for PFCP Session Deletion Request")
-			return
-		}
-		msg, err := messages.DeserializePFCPSessionDeletionRequest(payloadMessage)
-		if err != nil {
-			log.Printf("Error deserializing PFCP Session Deletion Request: %v", err)
-			return
-		}
-		server.pfcpSessionDeletionRequestHandler(pfcpClient, header.SequenceNumber, header.SEID, msg)
-	case messages.PFCPSessionDeletionResponseMessageType:
-		if server.pfcpSessionDeletionResponseHandler == nil {
-			log.Printf("No handler for PFCP Session Deletion Response")
-			return
-		}
-		msg, err := messages.DeserializePFCPSessionDeletionResponse(payloadMessage)
-		if err != nil {
-			log.Printf("Error deserializing PFCP Session Deletion Response: %v", err)
-			return
-		}
-		server.pfcpSessionDeletionResponseHandler(pfcpClient, header.SequenceNumber, header.SEID, msg)
-	case messages.PFCPSessionReportRequestMessageType:
-		if server.pfcpSessionReportRequestHandler == nil {
-			log.Printf("No handler for PFCP Session Report Request")
-			return
-		}
-		msg, err := messages.DeserializePFCPSessionReportRequest(payloadMessage)
-		if err != nil {
-			log.Printf("Error deserializing PFCP Session Report Request: %v", err)
-			return
+	_, message, err := messages.Decode(payload)
+	if errors.Is(err, messages.ErrUnknownMessageType) && server.hasFallback() {
+		message = newUnknownMessage(header, payload)
+		err = nil
+	}
+	if errors.Is(err, messages.ErrUnknownMessageType) {
+		server.logger.Warn("Ignoring message of unknown type",
+			slog.String("peer", address.String()), slog.Any("header", header))
+		if server.errorHandler != nil {
+			server.errorHandler(err)
 		}
-		server.pfcpSessionReportRequestHandler(pfcpClient, header.SequenceNumber, header.SEID, msg)
-	case messages.PFCPSessionReportResponseMessageType:
-		if server.pfcpSessionReportResponseHandler == nil {
-			log.Printf("No handler for PFCP Session Report Response")
-			return
+		return
+	}
+	if err != nil {
+		cause := ie.MandatoryIEIncorrect
+		if errors.Is(err, messages.ErrInvalidLength) {
+			cause = ie.InvalidLength
+		}
+		server.handleMalformedMessage(&MalformedMessageError{
+			Address: address,
+			Header:  &header,
+			Cause:   cause,
+			Err:     err,
+		})
+		return
+	}
+
+	if server.validateMessages {
+		if validator, ok := message.(messages.Validator); ok {
+			var validationErr *messages.ValidationError
+			if errors.As(validator.Validate(), &validationErr) {
+				server.handleInvalidMessage(server.newIncoming(address, header, pfcpClient, received), message, validationErr)
+				return
+			}
 		}
-		msg, err := messages.DeserializePFCPSessionReportResponse(payloadMessage)
-		if err != nil {
-			log.Printf("Error deserializing PFCP Session Report Response: %v", err)
+	}
+
+	server.metrics.MessageReceived(header.MessageType)
+	if cause, ok := metrics.CauseOf(message); ok {
+		server.metrics.Cause(header.MessageType, cause)
+	}
+
+	if server.responseCache != nil && header.MessageType.IsRequest() {
+		response, duplicate := server.responseCache.begin(address.String(), header.SequenceNumber)
+		if duplicate {
+			if response != nil {
+				server.logger.Debug("Resending response to retransmitted request",
+					slog.String("peer", address.String()), slog.Any("header", header))
+				if err := pfcpClient.Udp.Send(response); err != nil {
+					server.logger.Error("Error resending response",
+						slog.String("peer", address.String()), slog.Any("header", header), slog.Any("error", err))
+				}
+			}
 			return
 		}
-		server.pfcpSessionReportResponseHandler(pfcpClient, header.SequenceNumber, header.SEID, msg)
-	default:
-		log.Printf("Unknown PFCP message type: %v", header.MessageType)
 	}
+
+	if header.MessageType.IsRequest() {
+		server.observeRecovery(address.String(), message)
+	}
+	server.dispatch(address, pfcpClient, header, message, received)
 }
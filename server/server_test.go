@@ -1,15 +1,55 @@
 package server_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/dot-5g/pfcp/client"
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/internal/pfcptest"
+	"github.com/dot-5g/pfcp/messages"
+	"github.com/dot-5g/pfcp/metrics"
+	"github.com/dot-5g/pfcp/network"
 	"github.com/dot-5g/pfcp/server"
 )
 
 func TestServer(t *testing.T) {
 	t.Run("TestMoreThanOneServer", MoreThanOneServer)
 	t.Run("TestServerClosedNoError", ServerClosedNoError)
+	t.Run("TestClientSharesServerSocket", ClientSharesServerSocket)
+	t.Run("TestClientsSharingServerSocketReceiveTheirResponses", ClientsSharingServerSocketReceiveTheirResponses)
+	t.Run("TestPeerRequestSharingSequenceNumberOfPendingRequestHandled", PeerRequestSharingSequenceNumberOfPendingRequestHandled)
+	t.Run("TestRetransmittedRequestAnsweredFromResponseCache", RetransmittedRequestAnsweredFromResponseCache)
+	t.Run("TestMalformedMessagesReported", MalformedMessagesReported)
+	t.Run("TestMalformedRequestsRejected", MalformedRequestsRejected)
+	t.Run("TestSlowSessionHandlerDoesNotBlockHeartbeats", SlowSessionHandlerDoesNotBlockHeartbeats)
+	t.Run("TestSessionMessagesHandledInOrder", SessionMessagesHandledInOrder)
+	t.Run("TestOversizedDatagramReportedAsTruncated", OversizedDatagramReportedAsTruncated)
+	t.Run("TestRunReturnsWhenContextDone", RunReturnsWhenContextDone)
+	t.Run("TestShutdownWaitsForHandlers", ShutdownWaitsForHandlers)
+	t.Run("TestShutdownReturnsWhenContextDone", ShutdownReturnsWhenContextDone)
+	t.Run("TestShutdownWaitsForPendingRequests", ShutdownWaitsForPendingRequests)
+	t.Run("TestHandleDispatchesRegisteredMessageType", HandleDispatchesRegisteredMessageType)
+	t.Run("TestRegisteredRequestAnswered", RegisteredRequestAnswered)
+	t.Run("TestFallbackHandlesUnknownMessageType", FallbackHandlesUnknownMessageType)
+	t.Run("TestFallbackHandlesMessagesWithoutHandler", FallbackHandlesMessagesWithoutHandler)
+	t.Run("TestRequestHandlerResponseSent", RequestHandlerResponseSent)
+	t.Run("TestRequestHandlerErrorAnsweredWithCause", RequestHandlerErrorAnsweredWithCause)
+	t.Run("TestSessionResponsesUsePeerSEID", SessionResponsesUsePeerSEID)
+	t.Run("TestInterceptorShortCircuitsRequest", InterceptorShortCircuitsRequest)
+	t.Run("TestOutboundInterceptorSeesResponses", OutboundInterceptorSeesResponses)
+	t.Run("TestLoggerReceivesStructuredRecords", LoggerReceivesStructuredRecords)
+	t.Run("TestMetricsRecordReceivedMessagesAndDecodeErrors", MetricsRecordReceivedMessagesAndDecodeErrors)
+	t.Run("TestPeerRestartReportsLostSessions", PeerRestartReportsLostSessions)
+	t.Run("TestValidationRejectsRequestWithOffendingIE", ValidationRejectsRequestWithOffendingIE)
 }
 
 func MoreThanOneServer(t *testing.T) {
@@ -18,7 +58,7 @@ func MoreThanOneServer(t *testing.T) {
 	server1 := server.New(address)
 	server2 := server.New(address)
 	go func() {
-		err := server1.Run()
+		err := server1.Run(context.Background())
 		if err != nil {
 			t.Errorf("Expected no error to be returned")
 		}
@@ -26,9 +66,9 @@ func MoreThanOneServer(t *testing.T) {
 
 	defer server1.Close()
 
-	time.Sleep(time.Second)
+	<-server1.Ready()
 
-	err2 := server2.Run()
+	err2 := server2.Run(context.Background())
 	defer server2.Close()
 
 	if err2 == nil {
@@ -39,15 +79,1223 @@ func MoreThanOneServer(t *testing.T) {
 func ServerClosedNoError(t *testing.T) {
 	server := server.New("127.0.0.1:8805")
 	go func() {
-		err := server.Run()
+		err := server.Run(context.Background())
 		if err != nil {
 			t.Errorf("Expected no error to be returned")
 		}
 	}()
 
-	time.Sleep(time.Second)
+	<-server.Ready()
 	server.Close()
 
-	go server.Run()
+	go server.Run(context.Background())
 	defer server.Close()
 }
+
+func ClientSharesServerSocket(t *testing.T) {
+	peerConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening on UDP: %v", err)
+	}
+	defer peerConn.Close()
+
+	pfcpServer := server.New("127.0.0.1:0")
+	err = pfcpServer.Listen()
+	if err != nil {
+		t.Fatalf("Error listening: %v", err)
+	}
+	go pfcpServer.Run(context.Background())
+	defer pfcpServer.Close()
+
+	sources := make(chan net.Addr, 1)
+	go func() {
+		buffer := make([]byte, 1024)
+		length, address, err := peerConn.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+		sources <- address
+		header, err := messages.DeserializeHeader(buffer[:length])
+		if err != nil {
+			return
+		}
+		responseHeader := messages.NewNodeHeader(messages.HeartbeatResponseMessageType, header.SequenceNumber)
+		peerConn.WriteTo(messages.Serialize(messages.HeartbeatResponse{}, responseHeader), address)
+	}()
+
+	pfcpClient, err := pfcpServer.NewClient(peerConn.LocalAddr().String(), client.WithRetransmission(time.Second, 0))
+	if err != nil {
+		t.Fatalf("Error creating client: %v", err)
+	}
+
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+
+	_, err = pfcpClient.SendHeartbeatRequestAndWait(messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp})
+	if err != nil {
+		t.Fatalf("Expected response to be delivered through the server socket: %v", err)
+	}
+
+	source := <-sources
+	if source.String() != pfcpServer.Conn().LocalAddr().String() {
+		t.Errorf("Expected request to be sent from %s, got %s", pfcpServer.Conn().LocalAddr(), source)
+	}
+}
+
+func ClientsSharingServerSocketReceiveTheirResponses(t *testing.T) {
+	pfcpServer := server.New("127.0.0.1:0")
+	err := pfcpServer.Listen()
+	if err != nil {
+		t.Fatalf("Error listening: %v", err)
+	}
+	go pfcpServer.Run(context.Background())
+	defer pfcpServer.Close()
+
+	clients := make([]*client.PFCP, 0, 2)
+	timestamps := make([]ie.RecoveryTimeStamp, 0, 2)
+	for i := 0; i < 2; i++ {
+		peerConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Error listening on UDP: %v", err)
+		}
+		defer peerConn.Close()
+		recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Unix(int64(1700000000+i), 0))
+		if err != nil {
+			t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+		}
+		timestamps = append(timestamps, recoveryTimeStamp)
+		go func() {
+			buffer := make([]byte, 1024)
+			length, address, err := peerConn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			header, err := messages.DeserializeHeader(buffer[:length])
+			if err != nil {
+				return
+			}
+			responseHeader := messages.NewNodeHeader(messages.HeartbeatResponseMessageType, header.SequenceNumber)
+			peerConn.WriteTo(messages.Serialize(messages.HeartbeatResponse{RecoveryTimeStamp: recoveryTimeStamp}, responseHeader), address)
+		}()
+
+		pfcpClient, err := pfcpServer.NewClient(peerConn.LocalAddr().String(), client.WithRetransmission(time.Second, 0))
+		if err != nil {
+			t.Fatalf("Error creating client: %v", err)
+		}
+		clients = append(clients, pfcpClient)
+	}
+
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+	for i, pfcpClient := range clients {
+		response, err := pfcpClient.SendHeartbeatRequestAndWait(messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp})
+		if err != nil {
+			t.Fatalf("Expected client %d to receive its response: %v", i, err)
+		}
+		if response.RecoveryTimeStamp != timestamps[i] {
+			t.Errorf("Expected client %d to receive Recovery Time Stamp %v, got %v", i, timestamps[i], response.RecoveryTimeStamp)
+		}
+	}
+}
+
+func PeerRequestSharingSequenceNumberOfPendingRequestHandled(t *testing.T) {
+	pfcpServer := pfcptest.StartServer(t)
+	setupRequests := make(chan uint32, 1)
+	server.HandleRequest(pfcpServer, func(ctx context.Context, req messages.PFCPAssociationSetupRequest) (messages.PFCPMessage, error) {
+		incoming, _ := server.IncomingFromContext(ctx)
+		setupRequests <- incoming.Header.SequenceNumber
+		cause, err := ie.NewCause(ie.RequestAccepted)
+		if err != nil {
+			return nil, err
+		}
+		return messages.PFCPAssociationSetupResponse{NodeID: pfcpServer.NodeID(), Cause: cause, RecoveryTimeStamp: pfcpServer.RecoveryTimeStamp()}, nil
+	})
+	peerConn := pfcptest.NewPeer(t)
+	nodeID, err := ie.NewNodeID("upf.example.com")
+	if err != nil {
+		t.Fatalf("Error creating Node ID: %v", err)
+	}
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("Error creating Recovery Time Stamp: %v", err)
+	}
+	go func() {
+		header, _ := pfcptest.ReadMessage(t, peerConn)
+		address := pfcpServer.Conn().LocalAddr()
+		setupHeader := messages.NewNodeHeader(messages.PFCPAssociationSetupRequestMessageType, header.SequenceNumber)
+		peerConn.WriteTo(messages.Serialize(messages.PFCPAssociationSetupRequest{NodeID: nodeID, RecoveryTimeStamp: recoveryTimeStamp}, setupHeader), address)
+		pfcptest.ReadMessage(t, peerConn)
+		responseHeader := messages.NewNodeHeader(messages.HeartbeatResponseMessageType, header.SequenceNumber)
+		peerConn.WriteTo(messages.Serialize(messages.HeartbeatResponse{RecoveryTimeStamp: recoveryTimeStamp}, responseHeader), address)
+	}()
+	pfcpClient, err := pfcpServer.NewClient(peerConn.LocalAddr().String(), client.WithRetransmission(time.Second, 0))
+	if err != nil {
+		t.Fatalf("Error creating client: %v", err)
+	}
+
+	response, err := pfcpClient.SendHeartbeatRequestAndWait(messages.HeartbeatRequest{RecoveryTimeStamp: pfcpServer.RecoveryTimeStamp()})
+
+	if err != nil {
+		t.Fatalf("Expected the Heartbeat Response, got %v", err)
+	}
+	if response.RecoveryTimeStamp != recoveryTimeStamp {
+		t.Errorf("Expected Recovery Time Stamp %v, got %v", recoveryTimeStamp, response.RecoveryTimeStamp)
+	}
+	select {
+	case sequenceNumber := <-setupRequests:
+		if sequenceNumber != 1 {
+			t.Errorf("Expected the Association Setup Request to share sequence number 1, got %d", sequenceNumber)
+		}
+	default:
+		t.Errorf("Expected the Association Setup Request to be handled")
+	}
+}
+
+func RetransmittedRequestAnsweredFromResponseCache(t *testing.T) {
+	window := 300 * time.Millisecond
+	pfcpServer := server.New("127.0.0.1:0", server.WithResponseCacheWindow(window))
+	var handlerCalls atomic.Int32
+	pfcpServer.HeartbeatRequest(func(pfcpClient *client.PFCP, sequenceNumber uint32, msg messages.HeartbeatRequest) {
+		calls := handlerCalls.Add(1)
+		recoveryTimeStamp, _ := ie.NewRecoveryTimeStamp(time.Unix(int64(1700000000+calls), 0))
+		pfcpClient.SendHeartbeatResponse(messages.HeartbeatResponse{RecoveryTimeStamp: recoveryTimeStamp}, sequenceNumber)
+	})
+	err := pfcpServer.Listen()
+	if err != nil {
+		t.Fatalf("Error listening: %v", err)
+	}
+	go pfcpServer.Run(context.Background())
+	defer pfcpServer.Close()
+
+	peerConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening on UDP: %v", err)
+	}
+	defer peerConn.Close()
+
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+	request := messages.Serialize(messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp}, messages.NewNodeHeader(messages.HeartbeatRequestMessageType, 42))
+
+	exchange := func() []byte {
+		_, err := peerConn.WriteTo(request, pfcpServer.Conn().LocalAddr())
+		if err != nil {
+			t.Fatalf("Error sending request: %v", err)
+		}
+		peerConn.SetReadDeadline(time.Now().Add(time.Second))
+		buffer := make([]byte, 1024)
+		length, _, err := peerConn.ReadFrom(buffer)
+		if err != nil {
+			t.Fatalf("Error reading response: %v", err)
+		}
+		return buffer[:length]
+	}
+
+	first := exchange()
+	retransmitted := exchange()
+
+	if handlerCalls.Load() != 1 {
+		t.Errorf("Expected handler to be called once, got %d", handlerCalls.Load())
+	}
+	if !bytes.Equal(first, retransmitted) {
+		t.Errorf("Expected the cached response to be replayed.\n- First response: %v\n- Replayed response: %v", first, retransmitted)
+	}
+
+	time.Sleep(2 * window)
+
+	afterWindow := exchange()
+
+	if handlerCalls.Load() != 2 {
+		t.Errorf("Expected handler to be called again once the window expired, got %d calls", handlerCalls.Load())
+	}
+	if bytes.Equal(first, afterWindow) {
+		t.Errorf("Expected a new response once the window expired")
+	}
+}
+
+func MalformedMessagesReported(t *testing.T) {
+	var mu sync.Mutex
+	var reported []error
+	heartbeats := make(chan uint32, 1)
+	pfcpServer := pfcptest.StartServer(t, server.WithErrorHandler(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, err)
+	}))
+	pfcpServer.HeartbeatRequest(func(pfcpClient *client.PFCP, sequenceNumber uint32, msg messages.HeartbeatRequest) {
+		heartbeats <- sequenceNumber
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+	heartbeatRequest := messages.Serialize(messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp}, messages.NewNodeHeader(messages.HeartbeatRequestMessageType, 7))
+
+	garbage := [][]byte{
+		{},
+		{0x20},
+		{0x21, 0x32, 0x00, 0x0C, 0x00, 0x00},
+		heartbeatRequest[:len(heartbeatRequest)-3],
+		{0x20, 0x01, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00},
+		{0x20, 0x01, 0x00, 0x08, 0x00, 0x00, 0x01, 0x00, 0x00, 0x60, 0x00, 0x09},
+	}
+	for _, datagram := range garbage {
+		_, err := peerConn.WriteTo(datagram, pfcpServer.Conn().LocalAddr())
+		if err != nil {
+			t.Fatalf("Error sending garbage: %v", err)
+		}
+	}
+
+	_, err = peerConn.WriteTo(heartbeatRequest, pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending Heartbeat Request: %v", err)
+	}
+
+	select {
+	case sequenceNumber := <-heartbeats:
+		if sequenceNumber != 7 {
+			t.Errorf("Expected sequence number 7, got %d", sequenceNumber)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the server to keep handling messages after garbage")
+	}
+
+	if pfcpServer.MalformedMessages() != uint64(len(garbage)) {
+		t.Errorf("Expected %d malformed messages, got %d", len(garbage), pfcpServer.MalformedMessages())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reported) != len(garbage) {
+		t.Errorf("Expected %d errors to be reported, got %d", len(garbage), len(reported))
+	}
+	for _, err := range reported {
+		var malformed *server.MalformedMessageError
+		if !errors.As(err, &malformed) {
+			t.Errorf("Expected a MalformedMessageError, got %v", err)
+		}
+	}
+}
+
+func MalformedRequestsRejected(t *testing.T) {
+	pfcpServer := pfcptest.StartServer(t, server.WithRejectMalformedRequests())
+	pfcpServer.PFCPAssociationSetupRequest(func(pfcpClient *client.PFCP, sequenceNumber uint32, msg messages.PFCPAssociationSetupRequest) {
+		t.Errorf("Expected the handler not to be called for a malformed request")
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	// Node ID IE with an IPv4 type and a 2 bytes address
+	invalidNodeID := []byte{0x00, 0x3C, 0x00, 0x03, 0x00, 0x0A, 0x00}
+	setupRequestHeader := messages.NewNodeHeader(messages.PFCPAssociationSetupRequestMessageType, 12)
+	setupRequestHeader.MessageLength = uint16(4 + len(invalidNodeID))
+	setupRequest := append(setupRequestHeader.Serialize(), invalidNodeID...)
+
+	_, err := peerConn.WriteTo(setupRequest, pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending request: %v", err)
+	}
+
+	header, payload := pfcptest.ReadMessage(t, peerConn)
+	if header.MessageType != messages.PFCPAssociationSetupResponseMessageType {
+		t.Fatalf("Expected PFCP Association Setup Response, got message type %d", header.MessageType)
+	}
+	if header.SequenceNumber != 12 {
+		t.Errorf("Expected sequence number 12, got %d", header.SequenceNumber)
+	}
+	setupResponse, err := messages.DeserializePFCPAssociationSetupResponse(payload)
+	if err != nil {
+		t.Fatalf("Error deserializing response: %v", err)
+	}
+	if setupResponse.Cause.Value != ie.MandatoryIEIncorrect {
+		t.Errorf("Expected cause %d, got %d", ie.MandatoryIEIncorrect, setupResponse.Cause.Value)
+	}
+
+	deletionRequest := messages.Serialize(messages.PFCPSessionDeletionRequest{}, messages.NewSessionHeader(messages.PFCPSessionDeletionRequestMessageType, 99, 13))
+	deletionRequest[3] += 10
+
+	_, err = peerConn.WriteTo(deletionRequest, pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending request: %v", err)
+	}
+
+	header, payload = pfcptest.ReadMessage(t, peerConn)
+	if header.MessageType != messages.PFCPSessionDeletionResponseMessageType {
+		t.Fatalf("Expected PFCP Session Deletion Response, got message type %d", header.MessageType)
+	}
+	deletionResponse, err := messages.DeserializePFCPSessionDeletionResponse(payload)
+	if err != nil {
+		t.Fatalf("Error deserializing response: %v", err)
+	}
+	if deletionResponse.Cause.Value != ie.InvalidLength {
+		t.Errorf("Expected cause %d, got %d", ie.InvalidLength, deletionResponse.Cause.Value)
+	}
+}
+
+func SlowSessionHandlerDoesNotBlockHeartbeats(t *testing.T) {
+	pfcpServer := pfcptest.StartServer(t, server.WithWorkers(4, 16))
+	release := make(chan struct{})
+	defer close(release)
+	establishmentStarted := make(chan struct{}, 1)
+	pfcpServer.PFCPSessionEstablishmentRequest(func(pfcpClient *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionEstablishmentRequest) {
+		establishmentStarted <- struct{}{}
+		<-release
+	})
+	heartbeats := make(chan uint32, 1)
+	pfcpServer.HeartbeatRequest(func(pfcpClient *client.PFCP, sequenceNumber uint32, msg messages.HeartbeatRequest) {
+		heartbeats <- sequenceNumber
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	establishmentRequest := messages.Serialize(messages.PFCPSessionEstablishmentRequest{}, messages.NewSessionHeader(messages.PFCPSessionEstablishmentRequestMessageType, 0, 1))
+	_, err := peerConn.WriteTo(establishmentRequest, pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending request: %v", err)
+	}
+
+	select {
+	case <-establishmentStarted:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the Session Establishment Request handler to be called")
+	}
+
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+	heartbeatRequest := messages.Serialize(messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp}, messages.NewNodeHeader(messages.HeartbeatRequestMessageType, 2))
+	_, err = peerConn.WriteTo(heartbeatRequest, pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending request: %v", err)
+	}
+
+	select {
+	case <-heartbeats:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the Heartbeat Request to be handled while the Session Establishment Request handler is blocked")
+	}
+}
+
+func SessionMessagesHandledInOrder(t *testing.T) {
+	pfcpServer := pfcptest.StartServer(t, server.WithWorkers(4, 16))
+	var mu sync.Mutex
+	handled := make(map[uint64][]uint32)
+	done := make(chan struct{}, 20)
+	pfcpServer.PFCPSessionDeletionRequest(func(pfcpClient *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionDeletionRequest) {
+		time.Sleep(time.Duration(10-sequenceNumber%10) * time.Millisecond)
+		mu.Lock()
+		handled[seid] = append(handled[seid], sequenceNumber)
+		mu.Unlock()
+		done <- struct{}{}
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	for sequenceNumber := uint32(1); sequenceNumber <= 10; sequenceNumber++ {
+		for _, seid := range []uint64{5, 6} {
+			deletionRequest := messages.Serialize(messages.PFCPSessionDeletionRequest{}, messages.NewSessionHeader(messages.PFCPSessionDeletionRequestMessageType, seid, uint32(seid)*100+sequenceNumber))
+			_, err := peerConn.WriteTo(deletionRequest, pfcpServer.Conn().LocalAddr())
+			if err != nil {
+				t.Fatalf("Error sending request: %v", err)
+			}
+		}
+	}
+
+	for i := 0; i < 20; i++ {
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected 20 requests to be handled, got %d", i)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for seid, sequenceNumbers := range handled {
+		for i, sequenceNumber := range sequenceNumbers {
+			if sequenceNumber != uint32(seid)*100+uint32(i+1) {
+				t.Fatalf("Expected requests for SEID %d to be handled in order, got %v", seid, sequenceNumbers)
+			}
+		}
+	}
+}
+
+func OversizedDatagramReportedAsTruncated(t *testing.T) {
+	reported := make(chan error, 1)
+	pfcpServer := pfcptest.StartServer(t, server.WithMaxDatagramSize(512), server.WithErrorHandler(func(err error) {
+		reported <- err
+	}))
+	pfcpServer.PFCPSessionDeletionRequest(func(pfcpClient *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionDeletionRequest) {
+		t.Errorf("Expected the handler not to be called for a truncated request")
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	header := messages.NewSessionHeader(messages.PFCPSessionDeletionRequestMessageType, 1, 1)
+	header.MessageLength = 1024 - 4
+	datagram := append(header.Serialize(), make([]byte, 1024-16)...)
+
+	_, err := peerConn.WriteTo(datagram, pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending datagram: %v", err)
+	}
+
+	select {
+	case err := <-reported:
+		if !errors.Is(err, network.ErrTruncated) {
+			t.Errorf("Expected ErrTruncated, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the oversized datagram to be reported")
+	}
+}
+
+func RunReturnsWhenContextDone(t *testing.T) {
+	pfcpServer := server.New("127.0.0.1:0")
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- pfcpServer.Run(ctx)
+	}()
+
+	<-pfcpServer.Ready()
+	cancel()
+
+	select {
+	case err := <-runErr:
+		if err != nil {
+			t.Errorf("Expected no error to be returned, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected Run to return once the context is done")
+	}
+}
+
+func ShutdownWaitsForHandlers(t *testing.T) {
+	pfcpServer := pfcptest.StartServer(t, server.WithWorkers(2, 4))
+	started := make(chan struct{})
+	var finished atomic.Bool
+	pfcpServer.PFCPSessionDeletionRequest(func(pfcpClient *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionDeletionRequest) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		finished.Store(true)
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	deletionRequest := messages.Serialize(messages.PFCPSessionDeletionRequest{}, messages.NewSessionHeader(messages.PFCPSessionDeletionRequestMessageType, 1, 1))
+	_, err := peerConn.WriteTo(deletionRequest, pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending request: %v", err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err = pfcpServer.Shutdown(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error to be returned, got %v", err)
+	}
+
+	if !finished.Load() {
+		t.Errorf("Expected Shutdown to wait for the handler to finish")
+	}
+}
+
+func ShutdownReturnsWhenContextDone(t *testing.T) {
+	pfcpServer := pfcptest.StartServer(t, server.WithWorkers(2, 4))
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	pfcpServer.PFCPSessionDeletionRequest(func(pfcpClient *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionDeletionRequest) {
+		close(started)
+		<-release
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	deletionRequest := messages.Serialize(messages.PFCPSessionDeletionRequest{}, messages.NewSessionHeader(messages.PFCPSessionDeletionRequestMessageType, 1, 1))
+	_, err := peerConn.WriteTo(deletionRequest, pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending request: %v", err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = pfcpServer.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func ShutdownWaitsForPendingRequests(t *testing.T) {
+	pfcpServer := pfcptest.StartServer(t)
+	peerConn := pfcptest.NewPeer(t)
+	received := make(chan struct{})
+
+	go func() {
+		buffer := make([]byte, 1024)
+		length, address, err := peerConn.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+		header, err := messages.DeserializeHeader(buffer[:length])
+		if err != nil {
+			return
+		}
+		close(received)
+		time.Sleep(200 * time.Millisecond)
+		responseHeader := messages.NewNodeHeader(messages.HeartbeatResponseMessageType, header.SequenceNumber)
+		peerConn.WriteTo(messages.Serialize(messages.HeartbeatResponse{}, responseHeader), address)
+	}()
+
+	pfcpClient, err := pfcpServer.NewClient(peerConn.LocalAddr().String(), client.WithRetransmission(time.Second, 0))
+	if err != nil {
+		t.Fatalf("Error creating client: %v", err)
+	}
+
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery TimeStamp: %v", err)
+	}
+
+	requestErr := make(chan error, 1)
+	go func() {
+		_, err := pfcpClient.SendHeartbeatRequestAndWait(messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp})
+		requestErr <- err
+	}()
+
+	<-received
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	err = pfcpServer.Shutdown(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error to be returned, got %v", err)
+	}
+
+	select {
+	case err := <-requestErr:
+		if err != nil {
+			t.Errorf("Expected the pending request to be answered during shutdown, got %v", err)
+		}
+	default:
+		t.Errorf("Expected Shutdown to wait for the pending request")
+	}
+}
+
+type pfdManagementRequest struct{}
+
+func (msg pfdManagementRequest) GetIEs() []ie.InformationElement {
+	return nil
+}
+
+func (msg pfdManagementRequest) GetMessageType() messages.MessageType {
+	return messages.MessageType(3)
+}
+
+func (msg pfdManagementRequest) GetMessageTypeString() string {
+	return "PFCP PFD Management Request"
+}
+
+type pfdManagementResponse struct {
+	Cause ie.Cause
+}
+
+func (msg pfdManagementResponse) GetIEs() []ie.InformationElement {
+	return []ie.InformationElement{msg.Cause}
+}
+
+func (msg pfdManagementResponse) GetMessageType() messages.MessageType {
+	return messages.MessageType(4)
+}
+
+func (msg pfdManagementResponse) GetMessageTypeString() string {
+	return "PFCP PFD Management Response"
+}
+
+func registerPFDManagement() {
+	messages.Register(messages.MessageType(3), func(data []byte) (pfdManagementRequest, error) {
+		return pfdManagementRequest{}, nil
+	}, messages.AnsweredWith(messages.MessageType(4)))
+	messages.Register(messages.MessageType(4), func(data []byte) (pfdManagementResponse, error) {
+		ies, err := ie.DeserializeInformationElements(data)
+		if err != nil {
+			return pfdManagementResponse{}, err
+		}
+		var response pfdManagementResponse
+		for _, element := range ies {
+			if cause, ok := element.(ie.Cause); ok {
+				response.Cause = cause
+			}
+		}
+		return response, nil
+	})
+}
+
+func HandleDispatchesRegisteredMessageType(t *testing.T) {
+	registerPFDManagement()
+	received := make(chan server.Incoming, 1)
+	pfcpServer := pfcptest.StartServer(t)
+	server.Handle(pfcpServer, func(ctx context.Context, msg pfdManagementRequest) {
+		incoming, _ := server.IncomingFromContext(ctx)
+		received <- incoming
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	payload := messages.Serialize(pfdManagementRequest{}, messages.NewNodeHeader(messages.MessageType(3), 17))
+	_, err := peerConn.WriteTo(payload, pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending request: %v", err)
+	}
+
+	select {
+	case incoming := <-received:
+		if incoming.Header.SequenceNumber != 17 {
+			t.Errorf("Expected sequence number 17, got %d", incoming.Header.SequenceNumber)
+		}
+		if incoming.Address.String() != peerConn.LocalAddr().String() {
+			t.Errorf("Expected address %s, got %s", peerConn.LocalAddr(), incoming.Address)
+		}
+		if incoming.Client == nil {
+			t.Errorf("Expected client for the peer")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Handler was not called")
+	}
+}
+
+func RegisteredRequestAnswered(t *testing.T) {
+	registerPFDManagement()
+	pfcpServer := pfcptest.StartServer(t)
+	accepted, err := ie.NewCause(ie.RequestAccepted)
+	if err != nil {
+		t.Fatalf("Error creating Cause: %v", err)
+	}
+	server.HandleRequest(pfcpServer, func(ctx context.Context, req pfdManagementRequest) (messages.PFCPMessage, error) {
+		return pfdManagementResponse{Cause: accepted}, nil
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	payload := messages.Serialize(pfdManagementRequest{}, messages.NewNodeHeader(messages.MessageType(3), 21))
+	_, err = peerConn.WriteTo(payload, pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending request: %v", err)
+	}
+
+	header, ies := pfcptest.ReadMessage(t, peerConn)
+	if header.MessageType != 4 || header.SequenceNumber != 21 {
+		t.Fatalf("Expected PFCP PFD Management Response with sequence number 21, got %+v", header)
+	}
+	deserialize, _ := messages.DeserializerFor(header.MessageType)
+	response, err := deserialize(ies)
+	if err != nil {
+		t.Fatalf("Error deserializing response: %v", err)
+	}
+	if cause := response.(pfdManagementResponse).Cause.Value; cause != ie.RequestAccepted {
+		t.Errorf("Expected cause Request accepted, got %d", cause)
+	}
+}
+
+func FallbackHandlesUnknownMessageType(t *testing.T) {
+	received := make(chan messages.PFCPMessage, 1)
+	pfcpServer := pfcptest.StartServer(t)
+	pfcpServer.HandleFallback(func(ctx context.Context, msg messages.PFCPMessage) {
+		received <- msg
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery Time Stamp: %v", err)
+	}
+	heartbeat := messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp}
+	payload := messages.Serialize(heartbeat, messages.NewNodeHeader(messages.MessageType(99), 18))
+	_, err = peerConn.WriteTo(payload, pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending message: %v", err)
+	}
+
+	select {
+	case msg := <-received:
+		unknown, ok := msg.(server.UnknownMessage)
+		if !ok {
+			t.Fatalf("Expected UnknownMessage, got %T", msg)
+		}
+		if unknown.MessageType != 99 {
+			t.Errorf("Expected message type 99, got %d", unknown.MessageType)
+		}
+		ies := unknown.GetIEs()
+		if len(ies) == 0 || ies[0].GetType() != recoveryTimeStamp.GetType() {
+			t.Errorf("Expected the Recovery Time Stamp IE, got %v", ies)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Fallback handler was not called")
+	}
+}
+
+func FallbackHandlesMessagesWithoutHandler(t *testing.T) {
+	received := make(chan messages.PFCPMessage, 1)
+	pfcpServer := pfcptest.StartServer(t)
+	pfcpServer.HandleFallback(func(ctx context.Context, msg messages.PFCPMessage) {
+		received <- msg
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		t.Fatalf("Error creating Recovery Time Stamp: %v", err)
+	}
+	heartbeat := messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp}
+	payload := messages.Serialize(heartbeat, messages.NewNodeHeader(messages.HeartbeatRequestMessageType, 19))
+	_, err = peerConn.WriteTo(payload, pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending message: %v", err)
+	}
+
+	select {
+	case msg := <-received:
+		if _, ok := msg.(messages.HeartbeatRequest); !ok {
+			t.Errorf("Expected HeartbeatRequest, got %T", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Fallback handler was not called")
+	}
+}
+
+func RequestHandlerResponseSent(t *testing.T) {
+	pfcpServer := pfcptest.StartServer(t)
+	nodeID, err := ie.NewNodeID("127.0.0.1")
+	if err != nil {
+		t.Fatalf("Error creating Node ID: %v", err)
+	}
+	server.HandleRequest(pfcpServer, func(ctx context.Context, req messages.PFCPAssociationReleaseRequest) (messages.PFCPMessage, error) {
+		cause, err := ie.NewCause(ie.RequestAccepted)
+		if err != nil {
+			return nil, err
+		}
+		return messages.PFCPAssociationReleaseResponse{NodeID: nodeID, Cause: cause}, nil
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	request := messages.PFCPAssociationReleaseRequest{NodeID: nodeID}
+	payload := messages.Serialize(request, messages.NewNodeHeader(messages.PFCPAssociationReleaseRequestMessageType, 42))
+	_, err = peerConn.WriteTo(payload, pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending request: %v", err)
+	}
+
+	header, body := pfcptest.ReadMessage(t, peerConn)
+	if header.MessageType != messages.PFCPAssociationReleaseResponseMessageType {
+		t.Fatalf("Expected PFCP Association Release Response, got message type %d", header.MessageType)
+	}
+	if header.SequenceNumber != 42 {
+		t.Errorf("Expected sequence number 42, got %d", header.SequenceNumber)
+	}
+	response, err := messages.DeserializePFCPAssociationReleaseResponse(body)
+	if err != nil {
+		t.Fatalf("Error deserializing response: %v", err)
+	}
+	if response.Cause.Value != ie.RequestAccepted {
+		t.Errorf("Expected cause Request accepted, got %d", response.Cause.Value)
+	}
+}
+
+func RequestHandlerErrorAnsweredWithCause(t *testing.T) {
+	pfcpServer := pfcptest.StartServer(t)
+	server.HandleRequest(pfcpServer, func(ctx context.Context, req messages.PFCPSessionDeletionRequest) (messages.PFCPMessage, error) {
+		return nil, server.Reject(ie.NoEstablishedPFCPAssociation)
+	})
+	server.HandleRequest(pfcpServer, func(ctx context.Context, req messages.PFCPSessionReportRequest) (messages.PFCPMessage, error) {
+		return nil, errors.New("report failed")
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	for i, test := range []struct {
+		request      messages.PFCPMessage
+		responseType messages.MessageType
+		cause        ie.CauseValue
+	}{
+		{messages.PFCPSessionDeletionRequest{}, messages.PFCPSessionDeletionResponseMessageType, ie.NoEstablishedPFCPAssociation},
+		{messages.PFCPSessionReportRequest{}, messages.PFCPSessionReportResponseMessageType, ie.RequestRejected},
+	} {
+		sequenceNumber := uint32(50 + i)
+		payload := messages.Serialize(test.request, messages.NewSessionHeader(test.request.GetMessageType(), 9, sequenceNumber))
+		_, err := peerConn.WriteTo(payload, pfcpServer.Conn().LocalAddr())
+		if err != nil {
+			t.Fatalf("Error sending request: %v", err)
+		}
+
+		header, body := pfcptest.ReadMessage(t, peerConn)
+		if header.MessageType != test.responseType {
+			t.Fatalf("Expected message type %d, got %d", test.responseType, header.MessageType)
+		}
+		if header.SequenceNumber != sequenceNumber {
+			t.Errorf("Expected sequence number %d, got %d", sequenceNumber, header.SequenceNumber)
+		}
+		if header.SEID != 0 {
+			t.Errorf("Expected SEID 0 for an unknown session, got %d", header.SEID)
+		}
+		ies, err := ie.DeserializeInformationElements(body)
+		if err != nil || len(ies) == 0 {
+			t.Fatalf("Error deserializing response: %v", err)
+		}
+		if cause, ok := ies[0].(ie.Cause); !ok || cause.Value != test.cause {
+			t.Errorf("Expected cause %d, got %v", test.cause, ies[0])
+		}
+	}
+}
+
+func SessionResponsesUsePeerSEID(t *testing.T) {
+	pfcpServer := pfcptest.StartServer(t)
+	nodeID, err := ie.NewNodeID("127.0.0.1")
+	if err != nil {
+		t.Fatalf("Error creating Node ID: %v", err)
+	}
+	accepted, err := ie.NewCause(ie.RequestAccepted)
+	if err != nil {
+		t.Fatalf("Error creating Cause: %v", err)
+	}
+	server.HandleRequest(pfcpServer, func(ctx context.Context, req messages.PFCPSessionEstablishmentRequest) (messages.PFCPMessage, error) {
+		upFSEID, err := ie.NewFSEID(2222, "127.0.0.1", "")
+		if err != nil {
+			return nil, err
+		}
+		return messages.PFCPSessionEstablishmentResponse{NodeID: nodeID, Cause: accepted, UPFSEID: &upFSEID}, nil
+	})
+	server.HandleRequest(pfcpServer, func(ctx context.Context, req messages.PFCPSessionModificationRequest) (messages.PFCPMessage, error) {
+		return messages.PFCPSessionModificationResponse{Cause: accepted}, nil
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	cpFSEID, err := ie.NewFSEID(1111, "127.0.0.1", "")
+	if err != nil {
+		t.Fatalf("Error creating F-SEID: %v", err)
+	}
+	establishment := messages.PFCPSessionEstablishmentRequest{NodeID: nodeID, CPFSEID: cpFSEID}
+	modification := messages.PFCPSessionModificationRequest{}
+	for i, request := range []struct {
+		message messages.PFCPMessage
+		seid    uint64
+	}{
+		{establishment, 0},
+		{modification, 2222},
+	} {
+		payload := messages.Serialize(request.message, messages.NewSessionHeader(request.message.GetMessageType(), request.seid, uint32(60+i)))
+		_, err = peerConn.WriteTo(payload, pfcpServer.Conn().LocalAddr())
+		if err != nil {
+			t.Fatalf("Error sending request: %v", err)
+		}
+
+		header, _ := pfcptest.ReadMessage(t, peerConn)
+		if header.MessageType != request.message.GetMessageType()+1 {
+			t.Fatalf("Expected message type %d, got %d", request.message.GetMessageType()+1, header.MessageType)
+		}
+		if header.SEID != 1111 {
+			t.Errorf("Expected SEID 1111 in the response to %s, got %d", request.message.GetMessageTypeString(), header.SEID)
+		}
+	}
+
+	remote, exists := pfcpServer.RemoteSEID(peerConn.LocalAddr().String(), 2222)
+	if !exists || remote != 1111 {
+		t.Errorf("Expected remote SEID 1111, got %d", remote)
+	}
+}
+
+func InterceptorShortCircuitsRequest(t *testing.T) {
+	var handled atomic.Bool
+	intercepted := make(chan server.Incoming, 1)
+	pfcpServer := pfcptest.StartServer(t, server.WithInterceptors(
+		func(ctx context.Context, message messages.PFCPMessage, next server.Next) (messages.PFCPMessage, error) {
+			incoming, _ := server.IncomingFromContext(ctx)
+			intercepted <- incoming
+			if message.GetMessageType() == messages.PFCPSessionDeletionRequestMessageType {
+				return nil, server.Reject(ie.NoEstablishedPFCPAssociation)
+			}
+			return next(ctx, message)
+		},
+	))
+	server.Handle(pfcpServer, func(ctx context.Context, msg messages.PFCPSessionDeletionRequest) {
+		handled.Store(true)
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	payload := messages.Serialize(messages.PFCPSessionDeletionRequest{}, messages.NewSessionHeader(messages.PFCPSessionDeletionRequestMessageType, 7, 70))
+	_, err := peerConn.WriteTo(payload, pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending request: %v", err)
+	}
+
+	header, body := pfcptest.ReadMessage(t, peerConn)
+	if header.MessageType != messages.PFCPSessionDeletionResponseMessageType || header.SequenceNumber != 70 {
+		t.Fatalf("Expected PFCP Session Deletion Response 70, got message type %d sequence number %d", header.MessageType, header.SequenceNumber)
+	}
+	response, err := messages.DeserializePFCPSessionDeletionResponse(body)
+	if err != nil {
+		t.Fatalf("Error deserializing response: %v", err)
+	}
+	if response.Cause.Value != ie.NoEstablishedPFCPAssociation {
+		t.Errorf("Expected cause No established PFCP Association, got %d", response.Cause.Value)
+	}
+	if handled.Load() {
+		t.Errorf("Expected the handler not to be called")
+	}
+	incoming := <-intercepted
+	if incoming.Header.SEID != 7 || incoming.Address.String() != peerConn.LocalAddr().String() || incoming.Received.IsZero() {
+		t.Errorf("Unexpected incoming message %+v", incoming)
+	}
+}
+
+func OutboundInterceptorSeesResponses(t *testing.T) {
+	sent := make(chan messages.Header, 1)
+	pfcpServer := pfcptest.StartServer(t, server.WithOutboundInterceptors(
+		func(ctx context.Context, peer net.Addr, message messages.PFCPMessage, header messages.Header, invoker client.Invoker) (messages.PFCPMessage, error) {
+			sent <- header
+			return invoker(ctx, message, header)
+		},
+	))
+	server.HandleRequest(pfcpServer, func(ctx context.Context, req messages.PFCPSessionDeletionRequest) (messages.PFCPMessage, error) {
+		return nil, server.Reject(ie.SessionContextNotFound)
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	payload := messages.Serialize(messages.PFCPSessionDeletionRequest{}, messages.NewSessionHeader(messages.PFCPSessionDeletionRequestMessageType, 7, 71))
+	_, err := peerConn.WriteTo(payload, pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending request: %v", err)
+	}
+
+	pfcptest.ReadMessage(t, peerConn)
+	header := <-sent
+	if header.MessageType != messages.PFCPSessionDeletionResponseMessageType || header.SequenceNumber != 71 {
+		t.Errorf("Expected PFCP Session Deletion Response 71, got message type %d sequence number %d", header.MessageType, header.SequenceNumber)
+	}
+}
+
+type lockedBuffer struct {
+	mu     sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buffer.String()
+}
+
+func LoggerReceivesStructuredRecords(t *testing.T) {
+	var buffer lockedBuffer
+	reported := make(chan error, 1)
+	logger := slog.New(slog.NewJSONHandler(&buffer, nil))
+	pfcpServer := pfcptest.StartServer(t, server.WithLogger(logger), server.WithErrorHandler(func(err error) {
+		reported <- err
+	}))
+	peerConn := pfcptest.NewPeer(t)
+
+	payload := messages.Serialize(messages.PFCPSessionDeletionRequest{}, messages.NewSessionHeader(messages.PFCPSessionDeletionRequestMessageType, 12, 80))
+	payload[3] += 4 // Message Length beyond the end of the datagram
+	_, err := peerConn.WriteTo(payload, pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending message: %v", err)
+	}
+
+	select {
+	case <-reported:
+	case <-time.After(time.Second):
+		t.Fatalf("Malformed message was not reported")
+	}
+	logs := buffer.String()
+	for _, expected := range []string{
+		`"msg":"Malformed message"`,
+		`"peer":"` + peerConn.LocalAddr().String() + `"`,
+		`"header":{"message_type":54,"sequence_number":80,"seid":12}`,
+	} {
+		if !strings.Contains(logs, expected) {
+			t.Errorf("Expected %s in logs %s", expected, logs)
+		}
+	}
+}
+
+func MetricsRecordReceivedMessagesAndDecodeErrors(t *testing.T) {
+	prometheus := metrics.NewPrometheus()
+	handled := make(chan struct{}, 1)
+	pfcpServer := pfcptest.StartServer(t, server.WithMetrics(prometheus))
+	server.Handle(pfcpServer, func(ctx context.Context, msg messages.PFCPSessionDeletionRequest) {
+		handled <- struct{}{}
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	for _, payload := range [][]byte{
+		{0x21, 0x01},
+		messages.Serialize(messages.PFCPSessionDeletionRequest{}, messages.NewSessionHeader(messages.PFCPSessionDeletionRequestMessageType, 3, 90)),
+	} {
+		_, err := peerConn.WriteTo(payload, pfcpServer.Conn().LocalAddr())
+		if err != nil {
+			t.Fatalf("Error sending message: %v", err)
+		}
+	}
+
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Fatalf("Handler was not called")
+	}
+	var output strings.Builder
+	prometheus.WriteTo(&output)
+	for _, expected := range []string{
+		"pfcp_decode_errors_total 1",
+		`pfcp_messages_received_total{message_type="54"} 1`,
+	} {
+		if !strings.Contains(output.String(), expected+"\n") {
+			t.Errorf("Expected %s in metrics:\n%s", expected, output.String())
+		}
+	}
+}
+
+func PeerRestartReportsLostSessions(t *testing.T) {
+	restarts := make(chan server.PeerRestart, 2)
+	pfcpServer := pfcptest.StartServer(t, server.WithPeerRestartHandler(func(restart server.PeerRestart) {
+		restarts <- restart
+	}))
+	nodeID, err := ie.NewNodeID("upf.example.com")
+	if err != nil {
+		t.Fatalf("Error creating Node ID: %v", err)
+	}
+	accepted, err := ie.NewCause(ie.RequestAccepted)
+	if err != nil {
+		t.Fatalf("Error creating Cause: %v", err)
+	}
+	server.HandleRequest(pfcpServer, func(ctx context.Context, req messages.PFCPSessionEstablishmentRequest) (messages.PFCPMessage, error) {
+		upFSEID, err := ie.NewFSEID(2222, "127.0.0.1", "")
+		if err != nil {
+			return nil, err
+		}
+		return messages.PFCPSessionEstablishmentResponse{NodeID: nodeID, Cause: accepted, UPFSEID: &upFSEID}, nil
+	})
+	server.HandleRequest(pfcpServer, func(ctx context.Context, req messages.HeartbeatRequest) (messages.PFCPMessage, error) {
+		return messages.HeartbeatResponse{RecoveryTimeStamp: pfcpServer.RecoveryTimeStamp()}, nil
+	})
+	peerConn := pfcptest.NewPeer(t)
+	started := time.Now()
+	firstStart, err := ie.NewRecoveryTimeStamp(started)
+	if err != nil {
+		t.Fatalf("Error creating Recovery Time Stamp: %v", err)
+	}
+	restart, err := ie.NewRecoveryTimeStamp(started.Add(10 * time.Second))
+	if err != nil {
+		t.Fatalf("Error creating Recovery Time Stamp: %v", err)
+	}
+	cpFSEID, err := ie.NewFSEID(1111, "127.0.0.1", "")
+	if err != nil {
+		t.Fatalf("Error creating F-SEID: %v", err)
+	}
+	features, err := ie.NewUPFunctionFeatures([]ie.UPFeature{ie.FTUP})
+	if err != nil {
+		t.Fatalf("Error creating UP Function Features: %v", err)
+	}
+
+	for i, request := range []struct {
+		message messages.PFCPMessage
+		header  messages.Header
+	}{
+		{messages.PFCPAssociationSetupRequest{NodeID: nodeID, RecoveryTimeStamp: firstStart, UPFunctionFeatures: &features}, messages.NewNodeHeader(messages.PFCPAssociationSetupRequestMessageType, 100)},
+		{messages.PFCPSessionEstablishmentRequest{NodeID: nodeID, CPFSEID: cpFSEID}, messages.NewSessionHeader(messages.PFCPSessionEstablishmentRequestMessageType, 0, 101)},
+		{messages.HeartbeatRequest{RecoveryTimeStamp: firstStart}, messages.NewNodeHeader(messages.HeartbeatRequestMessageType, 102)},
+		{messages.HeartbeatRequest{RecoveryTimeStamp: restart}, messages.NewNodeHeader(messages.HeartbeatRequestMessageType, 103)},
+	} {
+		_, err = peerConn.WriteTo(messages.Serialize(request.message, request.header), pfcpServer.Conn().LocalAddr())
+		if err != nil {
+			t.Fatalf("Error sending request: %v", err)
+		}
+		if i > 0 {
+			pfcptest.ReadMessage(t, peerConn)
+		}
+	}
+
+	select {
+	case event := <-restarts:
+		if event.Address != peerConn.LocalAddr().String() {
+			t.Errorf("Expected address %s, got %s", peerConn.LocalAddr(), event.Address)
+		}
+		if event.NodeID == nil || string(event.NodeID.Value) != string(nodeID.Value) {
+			t.Errorf("Expected Node ID %v, got %v", nodeID, event.NodeID)
+		}
+		if event.Previous != firstStart || event.Current != restart {
+			t.Errorf("Expected Recovery Time Stamp %v then %v, got %v then %v", firstStart, restart, event.Previous, event.Current)
+		}
+		if len(event.Sessions) != 1 || event.Sessions[0] != 2222 {
+			t.Errorf("Expected lost session 2222, got %v", event.Sessions)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Peer restart was not reported")
+	}
+	if len(restarts) != 0 {
+		t.Errorf("Expected a single peer restart, got %v", <-restarts)
+	}
+	if _, exists := pfcpServer.RemoteSEID(peerConn.LocalAddr().String(), 2222); exists {
+		t.Errorf("Expected the sessions with the restarted peer to be forgotten")
+	}
+}
+
+func ValidationRejectsRequestWithOffendingIE(t *testing.T) {
+	var handled atomic.Bool
+	errs := make(chan error, 1)
+	pfcpServer := pfcptest.StartServer(t, server.WithValidation(), server.WithErrorHandler(func(err error) {
+		errs <- err
+	}))
+	server.Handle(pfcpServer, func(ctx context.Context, msg messages.PFCPSessionEstablishmentRequest) {
+		handled.Store(true)
+	})
+	peerConn := pfcptest.NewPeer(t)
+	nodeID, err := ie.NewNodeID("127.0.0.1")
+	if err != nil {
+		t.Fatalf("Error creating Node ID: %v", err)
+	}
+	cpFSEID, err := ie.NewFSEID(1111, "127.0.0.1", "")
+	if err != nil {
+		t.Fatalf("Error creating F-SEID: %v", err)
+	}
+	request := messages.PFCPSessionEstablishmentRequest{NodeID: nodeID, CPFSEID: cpFSEID}
+
+	_, err = peerConn.WriteTo(messages.Serialize(request, messages.NewSessionHeader(request.GetMessageType(), 0, 120)), pfcpServer.Conn().LocalAddr())
+	if err != nil {
+		t.Fatalf("Error sending request: %v", err)
+	}
+
+	header, payload := pfcptest.ReadMessage(t, peerConn)
+	if header.MessageType != messages.PFCPSessionEstablishmentResponseMessageType || header.SEID != 1111 || header.SequenceNumber != 120 {
+		t.Fatalf("Unexpected response header %+v", header)
+	}
+	response, err := messages.DeserializePFCPSessionEstablishmentResponse(payload)
+	if err != nil {
+		t.Fatalf("Error deserializing response: %v", err)
+	}
+	if response.Cause.Value != ie.MandatoryIEMissing {
+		t.Errorf("Expected cause Mandatory IE missing, got %d", response.Cause.Value)
+	}
+	if response.OffendingIE == nil || response.OffendingIE.TypeOfOffendingIE != ie.CreatePDRIEType {
+		t.Errorf("Expected Offending IE Create PDR, got %v", response.OffendingIE)
+	}
+	select {
+	case err := <-errs:
+		var validationErr *messages.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("Expected a *messages.ValidationError, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Error handler was not called")
+	}
+	if handled.Load() {
+		t.Errorf("Expected the invalid request not to be handled")
+	}
+}
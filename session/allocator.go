@@ -0,0 +1,49 @@
+package session
+
+import "sync"
+
+// Allocator hands out local SEIDs that are unique among the sessions in use.
+// SEID 0 is never allocated since it is reserved for the PFCP Session Establishment Request.
+type Allocator struct {
+	mu   sync.Mutex
+	last uint64
+	used map[uint64]struct{}
+}
+
+func NewAllocator() *Allocator {
+	return &Allocator{used: make(map[uint64]struct{})}
+}
+
+// Allocate returns a SEID not in use and marks it as used until it is released.
+func (allocator *Allocator) Allocate() uint64 {
+	allocator.mu.Lock()
+	defer allocator.mu.Unlock()
+	for {
+		allocator.last++
+		if allocator.last == 0 {
+			continue
+		}
+		if _, used := allocator.used[allocator.last]; !used {
+			allocator.used[allocator.last] = struct{}{}
+			return allocator.last
+		}
+	}
+}
+
+// Release makes seid available to later allocations.
+func (allocator *Allocator) Release(seid uint64) {
+	allocator.mu.Lock()
+	defer allocator.mu.Unlock()
+	delete(allocator.used, seid)
+}
+
+// Reserve marks seid, chosen outside of the allocator, as used and reports whether it was available.
+func (allocator *Allocator) Reserve(seid uint64) bool {
+	allocator.mu.Lock()
+	defer allocator.mu.Unlock()
+	if _, used := allocator.used[seid]; used || seid == 0 {
+		return false
+	}
+	allocator.used[seid] = struct{}{}
+	return true
+}
@@ -0,0 +1,30 @@
+package session_test
+
+import (
+	"testing"
+
+	"github.com/dot-5g/pfcp/session"
+)
+
+func TestGivenAllocatedSEIDsWhenAllocateThenNoCollision(t *testing.T) {
+	allocator := session.NewAllocator()
+	allocated := make(map[uint64]bool)
+	for i := 0; i < 100; i++ {
+		seid := allocator.Allocate()
+		if seid == 0 {
+			t.Fatalf("Expected SEID 0 never to be allocated")
+		}
+		if allocated[seid] {
+			t.Fatalf("SEID %d allocated twice", seid)
+		}
+		allocated[seid] = true
+	}
+
+	allocator.Release(50)
+	for i := 0; i < 10; i++ {
+		seid := allocator.Allocate()
+		if allocated[seid] {
+			t.Fatalf("SEID %d still in use was allocated again", seid)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+)
+
+// Rules are the rules installed in a session by the CP function, keyed by their IDs.
+type Rules struct {
+	PDRs map[uint16]ie.CreatePDR
+	FARs map[uint32]ie.CreateFAR
+	URRs map[uint32]ie.CreateURR
+	QERs map[uint32]ie.CreateQER
+	BARs map[uint8]ie.CreateBAR
+}
+
+// RuleError reports a rule that cannot be created, updated or removed. The request carrying it
+// is answered with Cause and Failed Rule ID, as described in TS 29.244 section 7.5.3.
+type RuleError struct {
+	Cause        ie.CauseValue
+	FailedRuleID ie.FailedRuleID
+	Reason       string
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("rule %d of type %d failed: %s", e.FailedRuleID.RuleIDValue, e.FailedRuleID.RuleIDType, e.Reason)
+}
+
+func ruleError(ruleIDType ie.RuleIDType, ruleID uint32, format string, args ...any) error {
+	return &RuleError{
+		Cause:        ie.RuleCreationFailure,
+		FailedRuleID: ie.FailedRuleID{RuleIDType: ruleIDType, RuleIDValue: ruleID},
+		Reason:       fmt.Sprintf(format, args...),
+	}
+}
+
+// Clone returns a copy of rules that can be changed without changing rules.
+func (rules Rules) Clone() Rules {
+	clone := Rules{
+		PDRs: make(map[uint16]ie.CreatePDR, len(rules.PDRs)),
+		FARs: make(map[uint32]ie.CreateFAR, len(rules.FARs)),
+		URRs: make(map[uint32]ie.CreateURR, len(rules.URRs)),
+		QERs: make(map[uint32]ie.CreateQER, len(rules.QERs)),
+		BARs: make(map[uint8]ie.CreateBAR, len(rules.BARs)),
+	}
+	for id, pdr := range rules.PDRs {
+		clone.PDRs[id] = pdr
+	}
+	for id, far := range rules.FARs {
+		clone.FARs[id] = far
+	}
+	for id, urr := range rules.URRs {
+		clone.URRs[id] = urr
+	}
+	for id, qer := range rules.QERs {
+		clone.QERs[id] = qer
+	}
+	for id, bar := range rules.BARs {
+		clone.BARs[id] = bar
+	}
+	return clone
+}
+
+// Establish returns the rules created by a PFCP Session Establishment Request.
+// It fails with a *RuleError if a rule ID is duplicated or a PDR refers to a missing rule.
+func Establish(req messages.PFCPSessionEstablishmentRequest) (Rules, error) {
+	rules := Rules{}.Clone()
+	err := rules.create(req.CreatePDR, req.CreateFAR, req.CreateURR, req.CreateQER, req.CreateBAR)
+	if err == nil {
+		err = rules.validate()
+	}
+	if err != nil {
+		return Rules{}, err
+	}
+	return rules, nil
+}
+
+// Modify returns the rules resulting from applying a PFCP Session Modification Request to rules.
+// Rules are removed, then created, then updated, and the result is validated as a whole so that
+// rules is left unchanged when a *RuleError is returned.
+func (rules Rules) Modify(req messages.PFCPSessionModificationRequest) (Rules, error) {
+	modified := rules.Clone()
+	err := modified.remove(req)
+	if err != nil {
+		return Rules{}, err
+	}
+	err = modified.create(req.CreatePDR, req.CreateFAR, req.CreateURR, req.CreateQER, req.CreateBAR)
+	if err != nil {
+		return Rules{}, err
+	}
+	err = modified.update(req)
+	if err == nil {
+		err = modified.validate()
+	}
+	if err != nil {
+		return Rules{}, err
+	}
+	return modified, nil
+}
+
+func (rules Rules) remove(req messages.PFCPSessionModificationRequest) error {
+	for _, remove := range req.RemovePDR {
+		id := remove.PDRID.RuleID
+		if _, exists := rules.PDRs[id]; !exists {
+			return ruleError(ie.PDRRuleIDType, uint32(id), "PDR to remove does not exist")
+		}
+		delete(rules.PDRs, id)
+	}
+	for _, remove := range req.RemoveFAR {
+		id := remove.FARID.Value
+		if _, exists := rules.FARs[id]; !exists {
+			return ruleError(ie.FARRuleIDType, id, "FAR to remove does not exist")
+		}
+		delete(rules.FARs, id)
+	}
+	for _, remove := range req.RemoveURR {
+		id := remove.URRID.Value
+		if _, exists := rules.URRs[id]; !exists {
+			return ruleError(ie.URRRuleIDType, id, "URR to remove does not exist")
+		}
+		delete(rules.URRs, id)
+	}
+	for _, remove := range req.RemoveQER {
+		id := remove.QERID.Value
+		if _, exists := rules.QERs[id]; !exists {
+			return ruleError(ie.QERRuleIDType, id, "QER to remove does not exist")
+		}
+		delete(rules.QERs, id)
+	}
+	if req.RemoveBAR != nil {
+		id := req.RemoveBAR.BARID.Value
+		if _, exists := rules.BARs[id]; !exists {
+			return ruleError(ie.BARRuleIDType, uint32(id), "BAR to remove does not exist")
+		}
+		delete(rules.BARs, id)
+	}
+	return nil
+}
+
+func (rules Rules) create(pdrs []ie.CreatePDR, fars []ie.CreateFAR, urrs []ie.CreateURR, qers []ie.CreateQER, bar *ie.CreateBAR) error {
+	for _, far := range fars {
+		if _, exists := rules.FARs[far.FARID.Value]; exists {
+			return ruleError(ie.FARRuleIDType, far.FARID.Value, "duplicate FAR ID")
+		}
+		rules.FARs[far.FARID.Value] = far
+	}
+	for _, urr := range urrs {
+		if _, exists := rules.URRs[urr.URRID.Value]; exists {
+			return ruleError(ie.URRRuleIDType, urr.URRID.Value, "duplicate URR ID")
+		}
+		rules.URRs[urr.URRID.Value] = urr
+	}
+	for _, qer := range qers {
+		if _, exists := rules.QERs[qer.QERID.Value]; exists {
+			return ruleError(ie.QERRuleIDType, qer.QERID.Value, "duplicate QER ID")
+		}
+		rules.QERs[qer.QERID.Value] = qer
+	}
+	if bar != nil {
+		if _, exists := rules.BARs[bar.BARID.Value]; exists {
+			return ruleError(ie.BARRuleIDType, uint32(bar.BARID.Value), "duplicate BAR ID")
+		}
+		rules.BARs[bar.BARID.Value] = *bar
+	}
+	for _, pdr := range pdrs {
+		if _, exists := rules.PDRs[pdr.PDRID.RuleID]; exists {
+			return ruleError(ie.PDRRuleIDType, uint32(pdr.PDRID.RuleID), "duplicate PDR ID")
+		}
+		rules.PDRs[pdr.PDRID.RuleID] = pdr
+	}
+	return nil
+}
+
+func (rules Rules) update(req messages.PFCPSessionModificationRequest) error {
+	for _, update := range req.UpdateFAR {
+		far, exists := rules.FARs[update.FARID.Value]
+		if !exists {
+			return ruleError(ie.FARRuleIDType, update.FARID.Value, "FAR to update does not exist")
+		}
+		if update.ApplyAction != nil {
+			far.ApplyAction = *update.ApplyAction
+		}
+		rules.FARs[update.FARID.Value] = far
+	}
+	for _, update := range req.UpdateURR {
+		urr, exists := rules.URRs[update.URRID.Value]
+		if !exists {
+			return ruleError(ie.URRRuleIDType, update.URRID.Value, "URR to update does not exist")
+		}
+		if update.MeasurementMethod != nil {
+			urr.MeasurementMethod = *update.MeasurementMethod
+		}
+		if update.ReportingTriggers != nil {
+			urr.ReportingTriggers = *update.ReportingTriggers
+		}
+		rules.URRs[update.URRID.Value] = urr
+	}
+	for _, update := range req.UpdateQER {
+		qer, exists := rules.QERs[update.QERID.Value]
+		if !exists {
+			return ruleError(ie.QERRuleIDType, update.QERID.Value, "QER to update does not exist")
+		}
+		if update.GateStatus != nil {
+			qer.GateStatus = *update.GateStatus
+		}
+		rules.QERs[update.QERID.Value] = qer
+	}
+	if update := req.UpdateBAR; update != nil {
+		bar, exists := rules.BARs[update.BARID.Value]
+		if !exists {
+			return ruleError(ie.BARRuleIDType, uint32(update.BARID.Value), "BAR to update does not exist")
+		}
+		if update.SuggestedBufferingPacketsCount != nil {
+			bar.SuggestedBufferingPacketsCount = update.SuggestedBufferingPacketsCount
+		}
+		rules.BARs[update.BARID.Value] = bar
+	}
+	for _, update := range req.UpdatePDR {
+		pdr, exists := rules.PDRs[update.PDRID.RuleID]
+		if !exists {
+			return ruleError(ie.PDRRuleIDType, uint32(update.PDRID.RuleID), "PDR to update does not exist")
+		}
+		if update.Precedence != nil {
+			pdr.Precedence = *update.Precedence
+		}
+		if update.PDI != nil {
+			pdr.PDI = *update.PDI
+		}
+		if update.FARID != nil {
+			pdr.FARID = update.FARID
+		}
+		if len(update.URRID) > 0 {
+			pdr.URRID = update.URRID
+		}
+		if len(update.QERID) > 0 {
+			pdr.QERID = update.QERID
+		}
+		rules.PDRs[update.PDRID.RuleID] = pdr
+	}
+	return nil
+}
+
+// validate checks that the rules referred to by every PDR exist, PDRs being checked by ascending ID.
+func (rules Rules) validate() error {
+	ids := make([]uint16, 0, len(rules.PDRs))
+	for id := range rules.PDRs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		pdr := rules.PDRs[id]
+		if pdr.FARID != nil {
+			if _, exists := rules.FARs[pdr.FARID.Value]; !exists {
+				return ruleError(ie.PDRRuleIDType, uint32(id), "FAR %d does not exist", pdr.FARID.Value)
+			}
+		}
+		for _, urrID := range pdr.URRID {
+			if _, exists := rules.URRs[urrID.Value]; !exists {
+				return ruleError(ie.PDRRuleIDType, uint32(id), "URR %d does not exist", urrID.Value)
+			}
+		}
+		for _, qerID := range pdr.QERID {
+			if _, exists := rules.QERs[qerID.Value]; !exists {
+				return ruleError(ie.PDRRuleIDType, uint32(id), "QER %d does not exist", qerID.Value)
+			}
+		}
+	}
+	return nil
+}
+
+// ruleRejection returns the response to the request carrying the Cause and Failed Rule ID of err.
+func ruleRejection(nodeID ie.NodeID, request messages.PFCPMessage, err *RuleError) (messages.PFCPMessage, error) {
+	cause, causeErr := ie.NewCause(err.Cause)
+	if causeErr != nil {
+		return nil, causeErr
+	}
+	failedRuleID := err.FailedRuleID
+	switch request.(type) {
+	case messages.PFCPSessionEstablishmentRequest:
+		return messages.PFCPSessionEstablishmentResponse{NodeID: nodeID, Cause: cause, FailedRuleID: &failedRuleID}, nil
+	case messages.PFCPSessionModificationRequest:
+		return messages.PFCPSessionModificationResponse{Cause: cause, FailedRuleID: &failedRuleID}, nil
+	default:
+		return nil, err
+	}
+}
@@ -0,0 +1,237 @@
+package session_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+	"github.com/dot-5g/pfcp/session"
+)
+
+func newCreatePDR(t *testing.T, id uint16, farID uint32) ie.CreatePDR {
+	pdrID, err := ie.NewPDRID(id)
+	if err != nil {
+		t.Fatalf("Error creating PDR ID: %v", err)
+	}
+	precedence, err := ie.NewPrecedence(uint32(id))
+	if err != nil {
+		t.Fatalf("Error creating Precedence: %v", err)
+	}
+	sourceInterface, err := ie.NewSourceInterface(0)
+	if err != nil {
+		t.Fatalf("Error creating Source Interface: %v", err)
+	}
+	ueIPAddress, err := ie.NewUEIPAddress("1.2.3.4", "", ie.SourceDestination{}, 0, 0, false, false)
+	if err != nil {
+		t.Fatalf("Error creating UE IP Address: %v", err)
+	}
+	pdi, err := ie.NewPDI(sourceInterface, &ueIPAddress)
+	if err != nil {
+		t.Fatalf("Error creating PDI: %v", err)
+	}
+	createPDR, err := ie.NewCreatePDR(pdrID, precedence, pdi)
+	if err != nil {
+		t.Fatalf("Error creating Create PDR: %v", err)
+	}
+	createPDR.FARID = &ie.FARID{Value: farID}
+	return createPDR
+}
+
+func newCreateFAR(t *testing.T, id uint32) ie.CreateFAR {
+	applyAction, err := ie.NewApplyAction(ie.FORW, nil)
+	if err != nil {
+		t.Fatalf("Error creating Apply Action: %v", err)
+	}
+	createFAR, err := ie.NewCreateFAR(ie.FARID{Value: id}, applyAction)
+	if err != nil {
+		t.Fatalf("Error creating Create FAR: %v", err)
+	}
+	return createFAR
+}
+
+func expectRuleError(t *testing.T, err error, ruleIDType ie.RuleIDType, ruleID uint32) {
+	t.Helper()
+	var ruleErr *session.RuleError
+	if !errors.As(err, &ruleErr) {
+		t.Fatalf("Expected a *RuleError, got %v", err)
+	}
+	if ruleErr.Cause != ie.RuleCreationFailure {
+		t.Errorf("Expected cause Rule creation/modification Failure, got %d", ruleErr.Cause)
+	}
+	if ruleErr.FailedRuleID.RuleIDType != ruleIDType || ruleErr.FailedRuleID.RuleIDValue != ruleID {
+		t.Errorf("Expected failed rule %d of type %d, got %+v", ruleID, ruleIDType, ruleErr.FailedRuleID)
+	}
+}
+
+func TestGivenValidRulesWhenEstablishThenRulesKeyedByID(t *testing.T) {
+	rules, err := session.Establish(messages.PFCPSessionEstablishmentRequest{
+		CreatePDR: []ie.CreatePDR{newCreatePDR(t, 1, 10), newCreatePDR(t, 2, 10)},
+		CreateFAR: []ie.CreateFAR{newCreateFAR(t, 10)},
+	})
+
+	if err != nil {
+		t.Fatalf("Establish failed: %v", err)
+	}
+	if len(rules.PDRs) != 2 || len(rules.FARs) != 1 {
+		t.Fatalf("Expected 2 PDRs and 1 FAR, got %d and %d", len(rules.PDRs), len(rules.FARs))
+	}
+	if rules.PDRs[2].PDRID.RuleID != 2 || rules.FARs[10].FARID.Value != 10 {
+		t.Errorf("Unexpected rules %+v", rules)
+	}
+}
+
+func TestGivenPDRReferringToMissingFARWhenEstablishThenFailedRuleIsPDR(t *testing.T) {
+	_, err := session.Establish(messages.PFCPSessionEstablishmentRequest{
+		CreatePDR: []ie.CreatePDR{newCreatePDR(t, 1, 10), newCreatePDR(t, 2, 11)},
+		CreateFAR: []ie.CreateFAR{newCreateFAR(t, 10)},
+	})
+
+	expectRuleError(t, err, ie.PDRRuleIDType, 2)
+}
+
+func TestGivenDuplicatePDRIDWhenEstablishThenFailedRuleIsPDR(t *testing.T) {
+	_, err := session.Establish(messages.PFCPSessionEstablishmentRequest{
+		CreatePDR: []ie.CreatePDR{newCreatePDR(t, 1, 10), newCreatePDR(t, 1, 10)},
+		CreateFAR: []ie.CreateFAR{newCreateFAR(t, 10)},
+	})
+
+	expectRuleError(t, err, ie.PDRRuleIDType, 1)
+}
+
+func TestGivenRulesWhenModifyThenRemoveCreateAndUpdateApplied(t *testing.T) {
+	rules, err := session.Establish(messages.PFCPSessionEstablishmentRequest{
+		CreatePDR: []ie.CreatePDR{newCreatePDR(t, 1, 10), newCreatePDR(t, 2, 10)},
+		CreateFAR: []ie.CreateFAR{newCreateFAR(t, 10)},
+	})
+	if err != nil {
+		t.Fatalf("Establish failed: %v", err)
+	}
+
+	modified, err := rules.Modify(messages.PFCPSessionModificationRequest{
+		RemovePDR: []ie.RemovePDR{{PDRID: ie.PDRID{RuleID: 2}}},
+		CreateFAR: []ie.CreateFAR{newCreateFAR(t, 11)},
+		UpdatePDR: []ie.UpdatePDR{{PDRID: ie.PDRID{RuleID: 1}, FARID: &ie.FARID{Value: 11}}},
+	})
+
+	if err != nil {
+		t.Fatalf("Modify failed: %v", err)
+	}
+	if _, exists := modified.PDRs[2]; exists {
+		t.Errorf("Expected PDR 2 to be removed")
+	}
+	if far := modified.PDRs[1].FARID; far == nil || far.Value != 11 {
+		t.Errorf("Expected PDR 1 to refer to FAR 11, got %v", far)
+	}
+	if len(modified.FARs) != 2 {
+		t.Errorf("Expected 2 FARs, got %d", len(modified.FARs))
+	}
+	if len(rules.PDRs) != 2 || len(rules.FARs) != 1 {
+		t.Errorf("Expected the original rules to be left unchanged, got %+v", rules)
+	}
+}
+
+func TestGivenFARInUseWhenModifyRemovesItThenFailedRuleIsReferringPDR(t *testing.T) {
+	rules, err := session.Establish(messages.PFCPSessionEstablishmentRequest{
+		CreatePDR: []ie.CreatePDR{newCreatePDR(t, 1, 10)},
+		CreateFAR: []ie.CreateFAR{newCreateFAR(t, 10)},
+	})
+	if err != nil {
+		t.Fatalf("Establish failed: %v", err)
+	}
+
+	_, err = rules.Modify(messages.PFCPSessionModificationRequest{
+		RemoveFAR: []ie.RemoveFAR{{FARID: ie.FARID{Value: 10}}},
+	})
+
+	expectRuleError(t, err, ie.PDRRuleIDType, 1)
+	if len(rules.FARs) != 1 {
+		t.Errorf("Expected the original rules to be left unchanged")
+	}
+}
+
+func TestGivenUnknownFARWhenModifyUpdatesItThenFailedRuleIsFAR(t *testing.T) {
+	rules, err := session.Establish(messages.PFCPSessionEstablishmentRequest{})
+	if err != nil {
+		t.Fatalf("Establish failed: %v", err)
+	}
+
+	_, err = rules.Modify(messages.PFCPSessionModificationRequest{
+		UpdateFAR: []ie.UpdateFAR{{FARID: ie.FARID{Value: 7}}},
+	})
+
+	expectRuleError(t, err, ie.FARRuleIDType, 7)
+}
+
+func TestGivenBARWhenModifyUpdatesItThenBARUpdated(t *testing.T) {
+	rules, err := session.Establish(messages.PFCPSessionEstablishmentRequest{CreateBAR: &ie.CreateBAR{BARID: ie.BARID{Value: 1}}})
+	if err != nil {
+		t.Fatalf("Establish failed: %v", err)
+	}
+	count, err := ie.NewSuggestedBufferingPacketsCount(8)
+	if err != nil {
+		t.Fatalf("Error creating Suggested Buffering Packets Count: %v", err)
+	}
+
+	modified, err := rules.Modify(messages.PFCPSessionModificationRequest{
+		UpdateBAR: &ie.UpdateBAR{BARID: ie.BARID{Value: 1}, SuggestedBufferingPacketsCount: &count},
+	})
+
+	if err != nil {
+		t.Fatalf("Modify failed: %v", err)
+	}
+	if bar := modified.BARs[1]; bar.SuggestedBufferingPacketsCount == nil || *bar.SuggestedBufferingPacketsCount != count {
+		t.Errorf("Expected BAR 1 to suggest buffering %d packets, got %+v", count.PacketCount, bar)
+	}
+	if rules.BARs[1].SuggestedBufferingPacketsCount != nil {
+		t.Errorf("Expected the original rules to be left unchanged, got %+v", rules.BARs[1])
+	}
+}
+
+func TestGivenBARWhenModifyRemovesAndCreatesItThenBARReplaced(t *testing.T) {
+	rules, err := session.Establish(messages.PFCPSessionEstablishmentRequest{CreateBAR: &ie.CreateBAR{BARID: ie.BARID{Value: 1}}})
+	if err != nil {
+		t.Fatalf("Establish failed: %v", err)
+	}
+
+	modified, err := rules.Modify(messages.PFCPSessionModificationRequest{
+		RemoveBAR: &ie.RemoveBAR{BARID: ie.BARID{Value: 1}},
+		CreateBAR: &ie.CreateBAR{BARID: ie.BARID{Value: 2}},
+	})
+
+	if err != nil {
+		t.Fatalf("Modify failed: %v", err)
+	}
+	if _, exists := modified.BARs[1]; exists {
+		t.Errorf("Expected BAR 1 to be removed")
+	}
+	if _, exists := modified.BARs[2]; !exists {
+		t.Errorf("Expected BAR 2 to be created")
+	}
+}
+
+func TestGivenExistingBARWhenModifyCreatesItAgainThenFailedRuleIsBAR(t *testing.T) {
+	rules, err := session.Establish(messages.PFCPSessionEstablishmentRequest{CreateBAR: &ie.CreateBAR{BARID: ie.BARID{Value: 3}}})
+	if err != nil {
+		t.Fatalf("Establish failed: %v", err)
+	}
+
+	_, err = rules.Modify(messages.PFCPSessionModificationRequest{
+		CreateBAR: &ie.CreateBAR{BARID: ie.BARID{Value: 3}},
+	})
+
+	expectRuleError(t, err, ie.BARRuleIDType, 3)
+}
+
+func TestGivenUnknownBARWhenModifyRemovesItThenFailedRuleIsBAR(t *testing.T) {
+	rules, err := session.Establish(messages.PFCPSessionEstablishmentRequest{})
+	if err != nil {
+		t.Fatalf("Establish failed: %v", err)
+	}
+
+	_, err = rules.Modify(messages.PFCPSessionModificationRequest{
+		RemoveBAR: &ie.RemoveBAR{BARID: ie.BARID{Value: 5}},
+	})
+
+	expectRuleError(t, err, ie.BARRuleIDType, 5)
+}
@@ -0,0 +1,299 @@
+// Package session keeps track of the PFCP sessions of a server, keyed by the SEID allocated for them locally.
+package session
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"sync"
+
+	"github.com/dot-5g/pfcp/client"
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+	"github.com/dot-5g/pfcp/server"
+)
+
+// Session is a PFCP session with a peer.
+type Session struct {
+	LocalSEID   uint64
+	RemoteFSEID ie.FSEID
+	Peer        string
+	NodeID      ie.NodeID
+	Rules       Rules
+}
+
+type contextKey struct{}
+
+// FromContext returns the session targeted by the session request being handled.
+// For a PFCP Session Establishment Request, it is the session about to be created
+// and its LocalSEID is the SEID to put in the UP F-SEID of the response.
+func FromContext(ctx context.Context) (Session, bool) {
+	session, ok := ctx.Value(contextKey{}).(Session)
+	return session, ok
+}
+
+// Table holds the sessions of a server. It allocates the local SEID of the sessions established
+// by peers, keeps their rules and answers the session requests for unknown SEIDs with the Cause
+// Session context not found and the requests carrying invalid rules with the Cause Rule
+// creation/modification Failure. The handlers registered with the setter methods of the server,
+// such as PFCPSessionEstablishmentRequest, must send their response before returning for the
+// table to see it; the table then keeps an established session under the SEID of the UP F-SEID
+// they sent.
+type Table struct {
+	server    *server.Server
+	allocator *Allocator
+	mu        sync.RWMutex
+	sessions  map[uint64]*Session
+	sentMu    sync.Mutex
+	sent      map[sentKey]messages.PFCPMessage
+}
+
+// sentKey identifies the response to the request with a sequence number from a peer.
+type sentKey struct {
+	peer           string
+	sequenceNumber uint32
+}
+
+type Option func(*Table)
+
+// WithAllocator sets the allocator of the local SEIDs, for instance to share it between tables.
+func WithAllocator(allocator *Allocator) Option {
+	return func(table *Table) {
+		table.allocator = allocator
+	}
+}
+
+// New creates the session table of pfcpServer, registers its interceptor and makes it the session store of the server.
+func New(pfcpServer *server.Server, opts ...Option) *Table {
+	table := &Table{
+		server:   pfcpServer,
+		sessions: make(map[uint64]*Session),
+		sent:     make(map[sentKey]messages.PFCPMessage),
+	}
+	for _, opt := range opts {
+		opt(table)
+	}
+	if table.allocator == nil {
+		table.allocator = NewAllocator()
+	}
+	pfcpServer.Use(table.intercept)
+	pfcpServer.UseOutbound(table.recordSent)
+	pfcpServer.UseSessionStore(table)
+	return table
+}
+
+// Create adds a session with the peer at address under a newly allocated local SEID.
+func (table *Table) Create(peer string, nodeID ie.NodeID, remote ie.FSEID, rules Rules) Session {
+	session := &Session{
+		LocalSEID:   table.allocator.Allocate(),
+		RemoteFSEID: remote,
+		Peer:        peer,
+		NodeID:      nodeID,
+		Rules:       rules,
+	}
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	table.sessions[session.LocalSEID] = session
+	return *session
+}
+
+// Get returns the session with the local SEID seid.
+func (table *Table) Get(seid uint64) (Session, bool) {
+	table.mu.RLock()
+	defer table.mu.RUnlock()
+	session, exists := table.sessions[seid]
+	if !exists {
+		return Session{}, false
+	}
+	return *session, true
+}
+
+// Update calls update with the session with the local SEID seid and reports whether it exists.
+func (table *Table) Update(seid uint64, update func(*Session)) bool {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	session, exists := table.sessions[seid]
+	if !exists {
+		return false
+	}
+	update(session)
+	session.LocalSEID = seid
+	return true
+}
+
+// Delete removes the session with the local SEID seid and releases its SEID.
+func (table *Table) Delete(seid uint64) bool {
+	table.mu.Lock()
+	_, exists := table.sessions[seid]
+	delete(table.sessions, seid)
+	table.mu.Unlock()
+	if exists {
+		table.allocator.Release(seid)
+	}
+	return exists
+}
+
+// Sessions returns the sessions ordered by local SEID.
+func (table *Table) Sessions() []Session {
+	table.mu.RLock()
+	defer table.mu.RUnlock()
+	sessions := make([]Session, 0, len(table.sessions))
+	for _, session := range table.sessions {
+		sessions = append(sessions, *session)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].LocalSEID < sessions[j].LocalSEID })
+	return sessions
+}
+
+// RemoteSEID returns the SEID of the F-SEID of the peer at address for the session with the local SEID seid.
+func (table *Table) RemoteSEID(address string, seid uint64) (uint64, bool) {
+	session, exists := table.Get(seid)
+	if !exists || session.Peer != address {
+		return 0, false
+	}
+	return session.RemoteFSEID.SEID, true
+}
+
+// PeerSessions returns the local SEIDs of the sessions with the peer at address, in ascending order.
+func (table *Table) PeerSessions(address string) []uint64 {
+	var seids []uint64
+	for _, session := range table.Sessions() {
+		if session.Peer == address {
+			seids = append(seids, session.LocalSEID)
+		}
+	}
+	return seids
+}
+
+// HandlePeerRestart removes the sessions lost by a restarted peer, for use with server.WithPeerRestartHandler.
+func (table *Table) HandlePeerRestart(restart server.PeerRestart) {
+	for _, seid := range restart.Sessions {
+		if session, exists := table.Get(seid); exists && session.Peer == restart.Address {
+			table.Delete(seid)
+		}
+	}
+}
+
+// intercept creates the sessions established by peers, applies the rules they modify, deletes
+// the sessions they delete and rejects the session requests for SEIDs that are not in the table.
+// The rules of a request are checked before its handler is called and stored once it is accepted.
+func (table *Table) intercept(ctx context.Context, message messages.PFCPMessage, next server.Next) (messages.PFCPMessage, error) {
+	incoming, ok := server.IncomingFromContext(ctx)
+	if !ok || !incoming.Header.S || !incoming.Header.MessageType.IsRequest() {
+		return next(ctx, message)
+	}
+	peer := incoming.Address.String()
+
+	if req, ok := message.(messages.PFCPSessionEstablishmentRequest); ok {
+		return table.establish(ctx, incoming, req, next)
+	}
+
+	session, exists := table.Get(incoming.Header.SEID)
+	if !exists || session.Peer != peer {
+		return nil, server.Reject(ie.SessionContextNotFound)
+	}
+	req, modification := message.(messages.PFCPSessionModificationRequest)
+	if modification {
+		rules, err := session.Rules.Modify(req)
+		var ruleErr *RuleError
+		if errors.As(err, &ruleErr) {
+			return ruleRejection(table.server.NodeID(), req, ruleErr)
+		}
+		session.Rules = rules
+	}
+	response, answer, err := table.handle(context.WithValue(ctx, contextKey{}, session), incoming, message, next)
+	if err != nil || !accepted(answer) {
+		return response, err
+	}
+	switch message.(type) {
+	case messages.PFCPSessionModificationRequest:
+		table.Update(session.LocalSEID, func(updated *Session) {
+			updated.Rules = session.Rules
+			if req.CPFSEID != nil {
+				updated.RemoteFSEID = *req.CPFSEID
+			}
+		})
+	case messages.PFCPSessionDeletionRequest:
+		table.Delete(session.LocalSEID)
+	}
+	return response, nil
+}
+
+func (table *Table) establish(ctx context.Context, incoming server.Incoming, req messages.PFCPSessionEstablishmentRequest, next server.Next) (messages.PFCPMessage, error) {
+	rules, err := Establish(req)
+	var ruleErr *RuleError
+	if errors.As(err, &ruleErr) {
+		return ruleRejection(table.server.NodeID(), req, ruleErr)
+	}
+	session := Session{
+		LocalSEID:   table.allocator.Allocate(),
+		RemoteFSEID: req.CPFSEID,
+		Peer:        incoming.Address.String(),
+		NodeID:      req.NodeID,
+		Rules:       rules,
+	}
+	response, answer, err := table.handle(context.WithValue(ctx, contextKey{}, session), incoming, req, next)
+	if err != nil || !accepted(answer) {
+		table.allocator.Release(session.LocalSEID)
+		return response, err
+	}
+	if upFSEID := answer.(messages.PFCPSessionEstablishmentResponse).UPFSEID; upFSEID != nil && upFSEID.SEID != session.LocalSEID {
+		table.allocator.Release(session.LocalSEID)
+		if !table.allocator.Reserve(upFSEID.SEID) {
+			return response, nil
+		}
+		session.LocalSEID = upFSEID.SEID
+	}
+	table.mu.Lock()
+	table.sessions[session.LocalSEID] = &session
+	table.mu.Unlock()
+	return response, nil
+}
+
+// handle calls next and also returns the response answering message: the one returned by next or,
+// for the handlers that send their response themselves and return none, the one they sent.
+func (table *Table) handle(ctx context.Context, incoming server.Incoming, message messages.PFCPMessage, next server.Next) (messages.PFCPMessage, messages.PFCPMessage, error) {
+	key := sentKey{peer: incoming.Address.String(), sequenceNumber: incoming.Header.SequenceNumber}
+	table.sentMu.Lock()
+	table.sent[key] = nil
+	table.sentMu.Unlock()
+	response, err := next(ctx, message)
+	table.sentMu.Lock()
+	sent := table.sent[key]
+	delete(table.sent, key)
+	table.sentMu.Unlock()
+	if response == nil && err == nil {
+		return nil, sent, nil
+	}
+	return response, response, err
+}
+
+// recordSent keeps the responses sent to the requests being handled by handle.
+func (table *Table) recordSent(ctx context.Context, peer net.Addr, message messages.PFCPMessage, header messages.Header, invoker client.Invoker) (messages.PFCPMessage, error) {
+	response, err := invoker(ctx, message, header)
+	if err != nil || !header.MessageType.IsResponse() {
+		return response, err
+	}
+	key := sentKey{peer: peer.String(), sequenceNumber: header.SequenceNumber}
+	table.sentMu.Lock()
+	defer table.sentMu.Unlock()
+	if _, awaited := table.sent[key]; awaited {
+		table.sent[key] = message
+	}
+	return response, err
+}
+
+// accepted reports whether response carries the Cause Request accepted.
+func accepted(response messages.PFCPMessage) bool {
+	switch resp := response.(type) {
+	case messages.PFCPSessionEstablishmentResponse:
+		return resp.Cause.Value == ie.RequestAccepted
+	case messages.PFCPSessionModificationResponse:
+		return resp.Cause.Value == ie.RequestAccepted
+	case messages.PFCPSessionDeletionResponse:
+		return resp.Cause.Value == ie.RequestAccepted
+	default:
+		return false
+	}
+}
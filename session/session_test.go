@@ -0,0 +1,319 @@
+package session_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dot-5g/pfcp/client"
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/internal/pfcptest"
+	"github.com/dot-5g/pfcp/messages"
+	"github.com/dot-5g/pfcp/server"
+	"github.com/dot-5g/pfcp/session"
+)
+
+// startUserPlane starts a server with a session table accepting every session request.
+func startUserPlane(t *testing.T) (*server.Server, *session.Table) {
+	upServer := pfcptest.StartServer(t)
+	table := session.New(upServer)
+	accepted, err := ie.NewCause(ie.RequestAccepted)
+	if err != nil {
+		t.Fatalf("Error creating Cause: %v", err)
+	}
+	server.HandleRequest(upServer, func(ctx context.Context, req messages.PFCPSessionEstablishmentRequest) (messages.PFCPMessage, error) {
+		s, ok := session.FromContext(ctx)
+		if !ok {
+			return nil, server.Reject(ie.SystemFailure)
+		}
+		upFSEID, err := ie.NewFSEID(s.LocalSEID, "127.0.0.1", "")
+		if err != nil {
+			return nil, err
+		}
+		return messages.PFCPSessionEstablishmentResponse{NodeID: upServer.NodeID(), Cause: accepted, UPFSEID: &upFSEID}, nil
+	})
+	server.HandleRequest(upServer, func(ctx context.Context, req messages.PFCPSessionModificationRequest) (messages.PFCPMessage, error) {
+		return messages.PFCPSessionModificationResponse{Cause: accepted}, nil
+	})
+	server.HandleRequest(upServer, func(ctx context.Context, req messages.PFCPSessionDeletionRequest) (messages.PFCPMessage, error) {
+		return messages.PFCPSessionDeletionResponse{Cause: accepted}, nil
+	})
+	return upServer, table
+}
+
+func establish(t *testing.T, conn net.PacketConn, to net.Addr, cpSEID uint64) uint64 {
+	nodeID, err := ie.NewNodeID("smf.example.com")
+	if err != nil {
+		t.Fatalf("Error creating NodeID: %v", err)
+	}
+	cpFSEID, err := ie.NewFSEID(cpSEID, "127.0.0.1", "")
+	if err != nil {
+		t.Fatalf("Error creating F-SEID: %v", err)
+	}
+	farID, err := ie.NewFarID(1)
+	if err != nil {
+		t.Fatalf("Error creating FAR ID: %v", err)
+	}
+	applyAction, err := ie.NewApplyAction(ie.FORW, nil)
+	if err != nil {
+		t.Fatalf("Error creating Apply Action: %v", err)
+	}
+	createFAR, err := ie.NewCreateFAR(farID, applyAction)
+	if err != nil {
+		t.Fatalf("Error creating Create FAR: %v", err)
+	}
+
+	header, response := pfcptest.SendRequest(t, conn, to,
+		messages.PFCPSessionEstablishmentRequest{NodeID: nodeID, CPFSEID: cpFSEID, CreateFAR: []ie.CreateFAR{createFAR}},
+		messages.NewSessionHeader(messages.PFCPSessionEstablishmentRequestMessageType, 0, 1))
+	if header.SEID != cpSEID {
+		t.Errorf("Expected response SEID %d, got %d", cpSEID, header.SEID)
+	}
+	establishment := response.(messages.PFCPSessionEstablishmentResponse)
+	if establishment.Cause.Value != ie.RequestAccepted || establishment.UPFSEID == nil {
+		t.Fatalf("Expected establishment to be accepted with a UP F-SEID, got %+v", establishment)
+	}
+	return establishment.UPFSEID.SEID
+}
+
+func TestGivenEstablishmentRequestWhenAcceptedThenSessionStored(t *testing.T) {
+	upServer, table := startUserPlane(t)
+	peerConn := pfcptest.NewPeer(t)
+
+	seid := establish(t, peerConn, upServer.Conn().LocalAddr(), 1111)
+
+	s, exists := table.Get(seid)
+	if !exists {
+		t.Fatalf("Expected session %d to be stored", seid)
+	}
+	if s.RemoteFSEID.SEID != 1111 {
+		t.Errorf("Expected remote SEID 1111, got %d", s.RemoteFSEID.SEID)
+	}
+	if s.Peer != peerConn.LocalAddr().String() || string(s.NodeID.Value) != "smf.example.com" {
+		t.Errorf("Unexpected peer %s with Node ID %v", s.Peer, s.NodeID)
+	}
+	if len(s.Rules.FARs) != 1 {
+		t.Errorf("Expected 1 FAR, got %d", len(s.Rules.FARs))
+	}
+}
+
+func TestGivenUnknownSEIDWhenSessionRequestThenRejectedWithSessionContextNotFound(t *testing.T) {
+	upServer, _ := startUserPlane(t)
+	peerConn := pfcptest.NewPeer(t)
+
+	header, response := pfcptest.SendRequest(t, peerConn, upServer.Conn().LocalAddr(),
+		messages.PFCPSessionModificationRequest{}, messages.NewSessionHeader(messages.PFCPSessionModificationRequestMessageType, 42, 1))
+
+	if header.MessageType != messages.PFCPSessionModificationResponseMessageType {
+		t.Fatalf("Expected PFCP Session Modification Response, got message type %d", header.MessageType)
+	}
+	if cause := response.(messages.PFCPSessionModificationResponse).Cause.Value; cause != ie.SessionContextNotFound {
+		t.Errorf("Expected cause Session context not found, got %d", cause)
+	}
+}
+
+func TestGivenSessionOfAnotherPeerWhenSessionRequestThenRejectedWithSessionContextNotFound(t *testing.T) {
+	upServer, _ := startUserPlane(t)
+	seid := establish(t, pfcptest.NewPeer(t), upServer.Conn().LocalAddr(), 1111)
+
+	_, response := pfcptest.SendRequest(t, pfcptest.NewPeer(t), upServer.Conn().LocalAddr(),
+		messages.PFCPSessionDeletionRequest{}, messages.NewSessionHeader(messages.PFCPSessionDeletionRequestMessageType, seid, 2))
+
+	if cause := response.(messages.PFCPSessionDeletionResponse).Cause.Value; cause != ie.SessionContextNotFound {
+		t.Errorf("Expected cause Session context not found, got %d", cause)
+	}
+}
+
+func TestGivenEstablishedSessionWhenModifiedAndDeletedThenTableUpdated(t *testing.T) {
+	upServer, table := startUserPlane(t)
+	peerConn := pfcptest.NewPeer(t)
+	seid := establish(t, peerConn, upServer.Conn().LocalAddr(), 1111)
+	cpFSEID, err := ie.NewFSEID(3333, "127.0.0.1", "")
+	if err != nil {
+		t.Fatalf("Error creating F-SEID: %v", err)
+	}
+
+	header, _ := pfcptest.SendRequest(t, peerConn, upServer.Conn().LocalAddr(),
+		messages.PFCPSessionModificationRequest{CPFSEID: &cpFSEID}, messages.NewSessionHeader(messages.PFCPSessionModificationRequestMessageType, seid, 2))
+	if header.SEID != 3333 {
+		t.Errorf("Expected response SEID 3333, got %d", header.SEID)
+	}
+	if s, _ := table.Get(seid); s.RemoteFSEID.SEID != 3333 {
+		t.Errorf("Expected remote SEID 3333, got %d", s.RemoteFSEID.SEID)
+	}
+
+	_, response := pfcptest.SendRequest(t, peerConn, upServer.Conn().LocalAddr(),
+		messages.PFCPSessionDeletionRequest{}, messages.NewSessionHeader(messages.PFCPSessionDeletionRequestMessageType, seid, 3))
+
+	if cause := response.(messages.PFCPSessionDeletionResponse).Cause.Value; cause != ie.RequestAccepted {
+		t.Errorf("Expected deletion to be accepted, got cause %d", cause)
+	}
+	if _, exists := table.Get(seid); exists {
+		t.Errorf("Expected session %d to be deleted", seid)
+	}
+}
+
+func TestGivenHandlersSendingTheirResponseWhenSessionEstablishedAndDeletedThenTableUpdated(t *testing.T) {
+	upServer := pfcptest.StartServer(t)
+	table := session.New(upServer)
+	accepted, err := ie.NewCause(ie.RequestAccepted)
+	if err != nil {
+		t.Fatalf("Error creating Cause: %v", err)
+	}
+	upServer.PFCPSessionEstablishmentRequest(func(c *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionEstablishmentRequest) {
+		upFSEID, err := ie.NewFSEID(7777, "127.0.0.1", "")
+		if err != nil {
+			t.Errorf("Error creating F-SEID: %v", err)
+			return
+		}
+		response := messages.PFCPSessionEstablishmentResponse{NodeID: upServer.NodeID(), Cause: accepted, UPFSEID: &upFSEID}
+		if err := c.SendPFCPSessionEstablishmentResponse(response, msg.CPFSEID.SEID, sequenceNumber); err != nil {
+			t.Errorf("Error sending response: %v", err)
+		}
+	})
+	upServer.PFCPSessionDeletionRequest(func(c *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionDeletionRequest) {
+		if err := c.SendPFCPSessionDeletionResponse(messages.PFCPSessionDeletionResponse{Cause: accepted}, 1111, sequenceNumber); err != nil {
+			t.Errorf("Error sending response: %v", err)
+		}
+	})
+	peerConn := pfcptest.NewPeer(t)
+
+	seid := establish(t, peerConn, upServer.Conn().LocalAddr(), 1111)
+
+	if seid != 7777 {
+		t.Fatalf("Expected UP SEID 7777, got %d", seid)
+	}
+	if s, exists := table.Get(seid); !exists || s.RemoteFSEID.SEID != 1111 {
+		t.Fatalf("Expected session 7777 with remote SEID 1111 to be stored, got %+v", s)
+	}
+
+	_, response := pfcptest.SendRequest(t, peerConn, upServer.Conn().LocalAddr(),
+		messages.PFCPSessionDeletionRequest{}, messages.NewSessionHeader(messages.PFCPSessionDeletionRequestMessageType, seid, 2))
+
+	if cause := response.(messages.PFCPSessionDeletionResponse).Cause.Value; cause != ie.RequestAccepted {
+		t.Errorf("Expected deletion to be accepted, got cause %d", cause)
+	}
+	if _, exists := table.Get(seid); exists {
+		t.Errorf("Expected session %d to be deleted", seid)
+	}
+}
+
+func TestGivenRestartedPeerWhenHandlePeerRestartThenLostSessionsDeleted(t *testing.T) {
+	upServer, table := startUserPlane(t)
+	peerConn := pfcptest.NewPeer(t)
+	seid := establish(t, peerConn, upServer.Conn().LocalAddr(), 1111)
+
+	table.HandlePeerRestart(server.PeerRestart{Address: peerConn.LocalAddr().String(), Sessions: []uint64{seid}})
+
+	if sessions := table.Sessions(); len(sessions) != 0 {
+		t.Errorf("Expected no session, got %v", sessions)
+	}
+}
+
+func TestGivenSessionInTableWhenPeerRestartsThenServerReportsSessionAsLost(t *testing.T) {
+	restarts := make(chan server.PeerRestart, 1)
+	upServer := pfcptest.StartServer(t, server.WithPeerRestartHandler(func(restart server.PeerRestart) {
+		restarts <- restart
+	}))
+	table := session.New(upServer)
+	server.HandleRequest(upServer, func(ctx context.Context, req messages.HeartbeatRequest) (messages.PFCPMessage, error) {
+		return messages.HeartbeatResponse{RecoveryTimeStamp: upServer.RecoveryTimeStamp()}, nil
+	})
+	peerConn := pfcptest.NewPeer(t)
+	nodeID, err := ie.NewNodeID("smf.example.com")
+	if err != nil {
+		t.Fatalf("Error creating NodeID: %v", err)
+	}
+	cpFSEID, err := ie.NewFSEID(1111, "127.0.0.1", "")
+	if err != nil {
+		t.Fatalf("Error creating F-SEID: %v", err)
+	}
+	s := table.Create(peerConn.LocalAddr().String(), nodeID, cpFSEID, session.Rules{})
+
+	started := time.Now()
+	for i, offset := range []time.Duration{0, 10 * time.Second} {
+		recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(started.Add(offset))
+		if err != nil {
+			t.Fatalf("Error creating Recovery Time Stamp: %v", err)
+		}
+		pfcptest.SendRequest(t, peerConn, upServer.Conn().LocalAddr(),
+			messages.HeartbeatRequest{RecoveryTimeStamp: recoveryTimeStamp}, messages.NewNodeHeader(messages.HeartbeatRequestMessageType, uint32(i+1)))
+	}
+
+	select {
+	case restart := <-restarts:
+		if len(restart.Sessions) != 1 || restart.Sessions[0] != s.LocalSEID {
+			t.Errorf("Expected lost session %d, got %v", s.LocalSEID, restart.Sessions)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the peer restart to be reported")
+	}
+}
+
+func TestGivenPDRReferringToMissingFARWhenEstablishmentRequestThenRejectedWithFailedRuleID(t *testing.T) {
+	upServer, table := startUserPlane(t)
+	peerConn := pfcptest.NewPeer(t)
+	nodeID, err := ie.NewNodeID("smf.example.com")
+	if err != nil {
+		t.Fatalf("Error creating NodeID: %v", err)
+	}
+	cpFSEID, err := ie.NewFSEID(1111, "127.0.0.1", "")
+	if err != nil {
+		t.Fatalf("Error creating F-SEID: %v", err)
+	}
+
+	_, response := pfcptest.SendRequest(t, peerConn, upServer.Conn().LocalAddr(),
+		messages.PFCPSessionEstablishmentRequest{NodeID: nodeID, CPFSEID: cpFSEID, CreatePDR: []ie.CreatePDR{newCreatePDR(t, 5, 10)}},
+		messages.NewSessionHeader(messages.PFCPSessionEstablishmentRequestMessageType, 0, 1))
+
+	establishment := response.(messages.PFCPSessionEstablishmentResponse)
+	if establishment.Cause.Value != ie.RuleCreationFailure {
+		t.Errorf("Expected cause Rule creation/modification Failure, got %d", establishment.Cause.Value)
+	}
+	if establishment.FailedRuleID == nil || establishment.FailedRuleID.RuleIDType != ie.PDRRuleIDType || establishment.FailedRuleID.RuleIDValue != 5 {
+		t.Errorf("Expected failed PDR 5, got %v", establishment.FailedRuleID)
+	}
+	if sessions := table.Sessions(); len(sessions) != 0 {
+		t.Errorf("Expected no session, got %v", sessions)
+	}
+}
+
+func TestGivenInvalidModificationWhenModificationRequestThenRejectedAndRulesUnchanged(t *testing.T) {
+	upServer, table := startUserPlane(t)
+	peerConn := pfcptest.NewPeer(t)
+	seid := establish(t, peerConn, upServer.Conn().LocalAddr(), 1111)
+
+	_, response := pfcptest.SendRequest(t, peerConn, upServer.Conn().LocalAddr(),
+		messages.PFCPSessionModificationRequest{CreatePDR: []ie.CreatePDR{newCreatePDR(t, 1, 2)}},
+		messages.NewSessionHeader(messages.PFCPSessionModificationRequestMessageType, seid, 2))
+
+	modification := response.(messages.PFCPSessionModificationResponse)
+	if modification.Cause.Value != ie.RuleCreationFailure {
+		t.Errorf("Expected cause Rule creation/modification Failure, got %d", modification.Cause.Value)
+	}
+	if modification.FailedRuleID == nil || modification.FailedRuleID.RuleIDValue != 1 {
+		t.Errorf("Expected failed PDR 1, got %v", modification.FailedRuleID)
+	}
+	if s, _ := table.Get(seid); len(s.Rules.PDRs) != 0 {
+		t.Errorf("Expected the rules to be left unchanged, got %v", s.Rules.PDRs)
+	}
+}
+
+func TestGivenUnknownBARWhenModificationRequestUpdatesItThenRejectedWithFailedBAR(t *testing.T) {
+	upServer, _ := startUserPlane(t)
+	peerConn := pfcptest.NewPeer(t)
+	seid := establish(t, peerConn, upServer.Conn().LocalAddr(), 1111)
+
+	_, response := pfcptest.SendRequest(t, peerConn, upServer.Conn().LocalAddr(),
+		messages.PFCPSessionModificationRequest{UpdateBAR: &ie.UpdateBAR{BARID: ie.BARID{Value: 4}}},
+		messages.NewSessionHeader(messages.PFCPSessionModificationRequestMessageType, seid, 2))
+
+	modification := response.(messages.PFCPSessionModificationResponse)
+	if modification.Cause.Value != ie.RuleCreationFailure {
+		t.Errorf("Expected cause Rule creation/modification Failure, got %d", modification.Cause.Value)
+	}
+	if failed := modification.FailedRuleID; failed == nil || failed.RuleIDType != ie.BARRuleIDType || failed.RuleIDValue != 4 {
+		t.Errorf("Expected failed BAR 4, got %v", failed)
+	}
+}
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -23,7 +24,7 @@ var (
 	heartbeatRequestWithSourceIPMu                        sync.Mutex
 	heartbeatRequestWithSourceIPhandlerCalled             bool
 	heartbeatRequestWithSourceIPreceivedRecoveryTimestamp ie.RecoveryTimeStamp
-	heartbeatRequestWithSourceIPreceivedSourceIPAddress   ie.SourceIPAddress
+	heartbeatRequestWithSourceIPreceivedSourceIPAddress   *ie.SourceIPAddress
 	heartbeatRequestWithSourceIPReceivedSequenceNumber    uint32
 )
 
@@ -64,6 +65,7 @@ func TestHeartbeat(t *testing.T) {
 	t.Run("TestHeartbeatRequest", HeartbeatRequest)
 	t.Run("TestHeartbeatRequestWithSourceIPAddress", HeartbeatRequestWithSourceIPAddress)
 	t.Run("TestHeartbeatResponse", HeartbeatResponse)
+	t.Run("TestHeartbeatResponseSentFromServerAddress", HeartbeatResponseSentFromServerAddress)
 }
 
 func HeartbeatRequest(t *testing.T) {
@@ -80,11 +82,11 @@ func HeartbeatRequest(t *testing.T) {
 		RecoveryTimeStamp: recoveryTimeStamp,
 	}
 
-	go pfcpServer.Run()
+	go pfcpServer.Run(context.Background())
 
 	defer pfcpServer.Close()
 
-	time.Sleep(time.Second)
+	<-pfcpServer.Ready()
 
 	pfcpClient := client.New("127.0.0.1:8805")
 	err = pfcpClient.SendHeartbeatRequest(heartbeatRequestMsg, sentSequenceNumber)
@@ -130,14 +132,14 @@ func HeartbeatRequestWithSourceIPAddress(t *testing.T) {
 
 	heartbeatRequestMsg := messages.HeartbeatRequest{
 		RecoveryTimeStamp: recoveryTimeStamp,
-		SourceIPAddress:   sourceIPAddress,
+		SourceIPAddress:   &sourceIPAddress,
 	}
 
-	go pfcpServer.Run()
+	go pfcpServer.Run(context.Background())
 
 	defer pfcpServer.Close()
 
-	time.Sleep(time.Second)
+	<-pfcpServer.Ready()
 
 	pfcpClient := client.New("127.0.0.1:8805")
 	err = pfcpClient.SendHeartbeatRequest(heartbeatRequestMsg, sentSequenceNumber)
@@ -185,11 +187,11 @@ func HeartbeatResponse(t *testing.T) {
 		RecoveryTimeStamp: recoveryTimeStamp,
 	}
 
-	go pfcpServer.Run()
+	go pfcpServer.Run(context.Background())
 
 	defer pfcpServer.Close()
 
-	time.Sleep(time.Second)
+	<-pfcpServer.Ready()
 
 	pfcpClient := client.New("127.0.0.1:8805")
 	err = pfcpClient.SendHeartbeatResponse(heartbeatResponseMsg, sentSequenceNumber)
@@ -212,3 +214,39 @@ func HeartbeatResponse(t *testing.T) {
 
 	heartbeatResponseMu.Unlock()
 }
+
+func HandleHeartbeatRequestAndRespond(pfcpClient *client.PFCP, sequenceNumber uint32, msg messages.HeartbeatRequest) {
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+	if err != nil {
+		return
+	}
+	pfcpClient.SendHeartbeatResponse(messages.HeartbeatResponse{RecoveryTimeStamp: recoveryTimeStamp}, sequenceNumber)
+}
+
+func HeartbeatResponseSentFromServerAddress(t *testing.T) {
+	pfcpServer := server.New("127.0.0.1:8805")
+	pfcpServer.HeartbeatRequest(HandleHeartbeatRequestAndRespond)
+	recoveryTimeStamp, err := ie.NewRecoveryTimeStamp(time.Now())
+
+	if err != nil {
+		t.Fatalf("Error creating Recovery Time Stamp IE: %v", err)
+	}
+
+	heartbeatRequestMsg := messages.HeartbeatRequest{
+		RecoveryTimeStamp: recoveryTimeStamp,
+	}
+
+	go pfcpServer.Run(context.Background())
+
+	defer pfcpServer.Close()
+
+	<-pfcpServer.Ready()
+
+	pfcpClient := client.New("127.0.0.1:8805", client.WithRetransmission(time.Second, 0))
+	defer pfcpClient.Close()
+
+	_, err = pfcpClient.SendHeartbeatRequestAndWait(heartbeatRequestMsg)
+	if err != nil {
+		t.Fatalf("Expected Heartbeat response from the server address: %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -53,7 +54,7 @@ func PFCPAssociationReleaseRequest(t *testing.T) {
 	pfcpServer.PFCPAssociationReleaseRequest(HandlePFCPAssociationReleaseRequest)
 
 	go func() {
-		err := pfcpServer.Run()
+		err := pfcpServer.Run(context.Background())
 		if err != nil {
 			t.Errorf("Expected no error to be returned")
 		}
@@ -61,7 +62,7 @@ func PFCPAssociationReleaseRequest(t *testing.T) {
 
 	defer pfcpServer.Close()
 
-	time.Sleep(time.Second)
+	<-pfcpServer.Ready()
 	pfcpClient := client.New("127.0.0.1:8805")
 	nodeID, err := ie.NewNodeID("12.23.34.45")
 
@@ -112,7 +113,7 @@ func PFCPAssociationReleaseResponse(t *testing.T) {
 	pfcpServer.PFCPAssociationReleaseResponse(HandlePFCPAssociationReleaseResponse)
 
 	go func() {
-		err := pfcpServer.Run()
+		err := pfcpServer.Run(context.Background())
 		if err != nil {
 			t.Errorf("Expected no error to be returned")
 		}
@@ -120,7 +121,7 @@ func PFCPAssociationReleaseResponse(t *testing.T) {
 
 	defer pfcpServer.Close()
 
-	time.Sleep(time.Second)
+	<-pfcpServer.Ready()
 	pfcpClient := client.New("127.0.0.1:8805")
 	nodeID, err := ie.NewNodeID("3.4.5.6")
 
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -17,7 +18,7 @@ var (
 	pfcpAssociationSetupRequestReceivedSequenceNumber     uint32
 	pfcpAssociationSetupRequestReceivedRecoveryTimeStamp  ie.RecoveryTimeStamp
 	pfcpAssociationSetupRequestReceivedNodeID             ie.NodeID
-	pfcpAssociationSetupRequestReceivedUPFunctionFeatures ie.UPFunctionFeatures
+	pfcpAssociationSetupRequestReceivedUPFunctionFeatures *ie.UPFunctionFeatures
 )
 
 var (
@@ -59,7 +60,7 @@ func PFCPAssociationSetupRequest(t *testing.T) {
 	pfcpServer.PFCPAssociationSetupRequest(HandlePFCPAssociationSetupRequest)
 
 	go func() {
-		err := pfcpServer.Run()
+		err := pfcpServer.Run(context.Background())
 		if err != nil {
 			t.Errorf("Expected no error to be returned")
 		}
@@ -67,7 +68,7 @@ func PFCPAssociationSetupRequest(t *testing.T) {
 
 	defer pfcpServer.Close()
 
-	time.Sleep(time.Second)
+	<-pfcpServer.Ready()
 	pfcpClient := client.New("127.0.0.1:8805")
 	nodeID, err := ie.NewNodeID("12.23.34.45")
 
@@ -95,7 +96,7 @@ func PFCPAssociationSetupRequest(t *testing.T) {
 	PFCPAssociationSetupRequestMsg := messages.PFCPAssociationSetupRequest{
 		NodeID:             nodeID,
 		RecoveryTimeStamp:  recoveryTimeStamp,
-		UPFunctionFeatures: upFeatures,
+		UPFunctionFeatures: &upFeatures,
 	}
 
 	err = pfcpClient.SendPFCPAssociationSetupRequest(PFCPAssociationSetupRequestMsg, sequenceNumber)
@@ -152,7 +153,7 @@ func PFCPAssociationSetupResponse(t *testing.T) {
 	pfcpServer.PFCPAssociationSetupResponse(HandlePFCPAssociationSetupResponse)
 
 	go func() {
-		err := pfcpServer.Run()
+		err := pfcpServer.Run(context.Background())
 		if err != nil {
 			t.Errorf("Expected no error to be returned")
 		}
@@ -160,7 +161,7 @@ func PFCPAssociationSetupResponse(t *testing.T) {
 
 	defer pfcpServer.Close()
 
-	time.Sleep(time.Second)
+	<-pfcpServer.Ready()
 
 	pfcpClient := client.New("127.0.0.1:8805")
 	nodeID, err := ie.NewNodeID("1.2.3.4")
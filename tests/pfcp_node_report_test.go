@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -55,7 +56,7 @@ func PFCPNodeReportRequest(t *testing.T) {
 	pfcpServer.PFCPNodeReportRequest(HandlePFCPNodeReportRequest)
 
 	go func() {
-		err := pfcpServer.Run()
+		err := pfcpServer.Run(context.Background())
 		if err != nil {
 			t.Errorf("Expected no error to be returned")
 		}
@@ -63,7 +64,7 @@ func PFCPNodeReportRequest(t *testing.T) {
 
 	defer pfcpServer.Close()
 
-	time.Sleep(time.Second)
+	<-pfcpServer.Ready()
 	pfcpClient := client.New("127.0.0.1:8805")
 	nodeID, err := ie.NewNodeID("12.23.34.45")
 
@@ -142,7 +143,7 @@ func PFCPNodeReportResponse(t *testing.T) {
 	pfcpServer.PFCPNodeReportResponse(HandlePFCPNodeReportResponse)
 
 	go func() {
-		err := pfcpServer.Run()
+		err := pfcpServer.Run(context.Background())
 		if err != nil {
 			t.Errorf("Expected no error to be returned")
 		}
@@ -150,7 +151,7 @@ func PFCPNodeReportResponse(t *testing.T) {
 
 	defer pfcpServer.Close()
 
-	time.Sleep(time.Second)
+	<-pfcpServer.Ready()
 	pfcpClient := client.New("127.0.0.1:8805")
 	nodeID, err := ie.NewNodeID("3.4.5.6")
 
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -53,7 +54,7 @@ func PFCPSessionDeletionRequest(t *testing.T) {
 	pfcpServer.PFCPSessionDeletionRequest(HandlePFCPSessionDeletionRequest)
 
 	go func() {
-		err := pfcpServer.Run()
+		err := pfcpServer.Run(context.Background())
 		if err != nil {
 			t.Errorf("Expected no error to be returned")
 		}
@@ -61,7 +62,7 @@ func PFCPSessionDeletionRequest(t *testing.T) {
 
 	defer pfcpServer.Close()
 
-	time.Sleep(time.Second)
+	<-pfcpServer.Ready()
 	pfcpClient := client.New("127.0.0.1:8805")
 
 	PFCPSessionDeletionRequestMsg := messages.PFCPSessionDeletionRequest{}
@@ -96,7 +97,7 @@ func PFCPSessionDeletionResponse(t *testing.T) {
 	pfcpServer.PFCPSessionDeletionResponse(HandlePFCPSessionDeletionResponse)
 
 	go func() {
-		err := pfcpServer.Run()
+		err := pfcpServer.Run(context.Background())
 		if err != nil {
 			t.Errorf("Expected no error to be returned")
 		}
@@ -104,7 +105,7 @@ func PFCPSessionDeletionResponse(t *testing.T) {
 
 	defer pfcpServer.Close()
 
-	time.Sleep(time.Second)
+	<-pfcpServer.Ready()
 	pfcpClient := client.New("127.0.0.1:8805")
 
 	PFCPSessionDeletionResponseMsg := messages.PFCPSessionDeletionResponse{}
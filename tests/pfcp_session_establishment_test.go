@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -18,8 +19,8 @@ var (
 	pfcpSessionEstablishmentRequestReceivedSEID           uint64
 	pfcpSessionEstablishmentRequestReceivedNodeID         ie.NodeID
 	pfcpSessionEstablishmentRequestReceivedCPFSEID        ie.FSEID
-	pfcpSessionEstablishmentRequestReceivedCreatePDR      ie.CreatePDR
-	pfcpSessionEstablishmentRequestReceivedCreateFAR      ie.CreateFAR
+	pfcpSessionEstablishmentRequestReceivedCreatePDR      []ie.CreatePDR
+	pfcpSessionEstablishmentRequestReceivedCreateFAR      []ie.CreateFAR
 )
 
 var (
@@ -28,6 +29,10 @@ var (
 	pfcpSessionEstablishmentResponseReceivedSequenceNumber uint32
 	pfcpSessionEstablishmentResponseReceivedNodeID         ie.NodeID
 	pfcpSessionEstablishmentResponseReceivedCause          ie.Cause
+	pfcpSessionEstablishmentResponseReceivedOffendingIE    *ie.OffendingIE
+	pfcpSessionEstablishmentResponseReceivedUPFSEID        *ie.FSEID
+	pfcpSessionEstablishmentResponseReceivedCreatedPDR     []ie.CreatedPDR
+	pfcpSessionEstablishmentResponseReceivedFailedRuleID   *ie.FailedRuleID
 )
 
 func HandlePFCPSessionEstablishmentRequest(client *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionEstablishmentRequest) {
@@ -49,11 +54,16 @@ func HandlePFCPSessionEstablishmentResponse(client *client.PFCP, sequenceNumber
 	pfcpSessionEstablishmentResponseReceivedSequenceNumber = sequenceNumber
 	pfcpSessionEstablishmentResponseReceivedNodeID = msg.NodeID
 	pfcpSessionEstablishmentResponseReceivedCause = msg.Cause
+	pfcpSessionEstablishmentResponseReceivedOffendingIE = msg.OffendingIE
+	pfcpSessionEstablishmentResponseReceivedUPFSEID = msg.UPFSEID
+	pfcpSessionEstablishmentResponseReceivedCreatedPDR = msg.CreatedPDR
+	pfcpSessionEstablishmentResponseReceivedFailedRuleID = msg.FailedRuleID
 }
 
 func TestPFCPSessionEstablishment(t *testing.T) {
 	t.Run("TestPFCPSessionEstablishmentRequest", PFCPSessionEstablishmentRequest)
 	t.Run("TestPFCPSessionEstablishmentResponse", PFCPSessionEstablishmentResponse)
+	t.Run("TestPFCPSessionEstablishmentRequestWithManyRules", PFCPSessionEstablishmentRequestWithManyRules)
 }
 
 func PFCPSessionEstablishmentRequest(t *testing.T) {
@@ -61,7 +71,7 @@ func PFCPSessionEstablishmentRequest(t *testing.T) {
 	pfcpServer.PFCPSessionEstablishmentRequest(HandlePFCPSessionEstablishmentRequest)
 
 	go func() {
-		err := pfcpServer.Run()
+		err := pfcpServer.Run(context.Background())
 		if err != nil {
 			t.Errorf("Expected no error to be returned")
 		}
@@ -69,7 +79,7 @@ func PFCPSessionEstablishmentRequest(t *testing.T) {
 
 	defer pfcpServer.Close()
 
-	time.Sleep(time.Second)
+	<-pfcpServer.Ready()
 	pfcpClient := client.New("127.0.0.1:8805")
 
 	nodeID, err := ie.NewNodeID("12.23.34.45")
@@ -85,62 +95,18 @@ func PFCPSessionEstablishmentRequest(t *testing.T) {
 		t.Fatalf("Error creating FSEID: %v", err)
 	}
 
-	pdrID, err := ie.NewPDRID(1)
-
-	if err != nil {
-		t.Fatalf("Error creating PDR ID: %v", err)
-	}
-
-	precedence, err := ie.NewPrecedence(uint32(1))
-
-	if err != nil {
-		t.Fatalf("Error creating Precedence: %v", err)
-	}
-
-	sourceInterface, err := ie.NewSourceInterface(3)
-	if err != nil {
-		t.Fatalf("Error creating SourceInterface: %v", err)
-	}
-
-	sd := ie.SourceDestination{}
-	prefixLength := uint8(32)
-	ueIPAddress, err := ie.NewUEIPAddress("", "", sd, 0, prefixLength, false, true)
-	if err != nil {
-		t.Fatalf("Expected no error, got %v", err)
-	}
-
-	pdi, err := ie.NewPDI(sourceInterface, ueIPAddress)
-	if err != nil {
-		t.Fatalf("Error creating PDI: %v", err)
-	}
-
-	createPDR, err := ie.NewCreatePDR(pdrID, precedence, pdi)
-	if err != nil {
-		t.Fatalf("Error creating CreatePDR: %v", err)
-	}
-
-	farID, err := ie.NewFarID(uint32(1))
-
-	if err != nil {
-		t.Fatalf("Error creating FarID: %v", err)
-	}
-
-	applyAction, err := ie.NewApplyAction(ie.FORW, []ie.ApplyActionExtraFlag{})
-
-	if err != nil {
-		t.Fatalf("Error creating ApplyAction: %v", err)
-	}
-
-	createFAR, err := ie.NewCreateFAR(farID, applyAction)
-	if err != nil {
-		t.Fatalf("Error creating CreateFAR: %v", err)
-	}
+	uplinkPDR := newCreatePDR(t, 1, 0)
+	downlinkPDR := newCreatePDR(t, 2, 1)
+	uplinkFAR := newCreateFAR(t, 1, ie.FORW)
+	downlinkFAR := newCreateFAR(t, 2, ie.BUFF)
+	createPDRs := []ie.CreatePDR{uplinkPDR, downlinkPDR}
+	createFARs := []ie.CreateFAR{uplinkFAR, downlinkFAR}
 
 	PFCPSessionEstablishmentRequestMsg := messages.PFCPSessionEstablishmentRequest{
 		NodeID:    nodeID,
 		CPFSEID:   fseid,
-		CreatePDR: createPDR,
-		CreateFAR: createFAR,
+		CreatePDR: createPDRs,
+		CreateFAR: createFARs,
 	}
 	sequenceNumber := uint32(32)
 
@@ -210,24 +176,38 @@ func PFCPSessionEstablishmentRequest(t *testing.T) {
 		}
 	}
 
-	if pfcpSessionEstablishmentRequestReceivedCreatePDR.PDRID != createPDR.PDRID {
-		t.Errorf("PFCP Session Establishment Request handler was called with wrong CreatePDR PDRID.\n- Sent CreatePDR PDRID: %v\n- Received CreatePDR PDRID %v\n", createPDR.PDRID, pfcpSessionEstablishmentRequestReceivedCreatePDR.PDRID)
+	if len(pfcpSessionEstablishmentRequestReceivedCreatePDR) != len(createPDRs) {
+		t.Fatalf("PFCP Session Establishment Request handler was called with wrong number of CreatePDR.\n- Sent: %v\n- Received %v\n", len(createPDRs), len(pfcpSessionEstablishmentRequestReceivedCreatePDR))
 	}
 
-	if pfcpSessionEstablishmentRequestReceivedCreatePDR.Precedence != createPDR.Precedence {
-		t.Errorf("PFCP Session Establishment Request handler was called with wrong CreatePDR Precedence.\n- Sent CreatePDR Precedence: %v\n- Received CreatePDR Precedence %v\n", createPDR.Precedence, pfcpSessionEstablishmentRequestReceivedCreatePDR.Precedence)
-	}
+	for i, createPDR := range createPDRs {
+		receivedCreatePDR := pfcpSessionEstablishmentRequestReceivedCreatePDR[i]
+		if receivedCreatePDR.PDRID != createPDR.PDRID {
+			t.Errorf("PFCP Session Establishment Request handler was called with wrong CreatePDR PDRID.\n- Sent CreatePDR PDRID: %v\n- Received CreatePDR PDRID %v\n", createPDR.PDRID, receivedCreatePDR.PDRID)
+		}
+
+		if receivedCreatePDR.Precedence != createPDR.Precedence {
+			t.Errorf("PFCP Session Establishment Request handler was called with wrong CreatePDR Precedence.\n- Sent CreatePDR Precedence: %v\n- Received CreatePDR Precedence %v\n", createPDR.Precedence, receivedCreatePDR.Precedence)
+		}
 
-	if pfcpSessionEstablishmentRequestReceivedCreatePDR.PDI.SourceInterface.Value != createPDR.PDI.SourceInterface.Value {
-		t.Errorf("PFCP Session Establishment Request handler was called with wrong CreatePDR PDI SourceInterface Value.\n- Sent CreatePDR PDI SourceInterface Value: %v\n- Received CreatePDR PDI SourceInterface Value %v\n", createPDR.PDI.SourceInterface.Value, pfcpSessionEstablishmentRequestReceivedCreatePDR.PDI.SourceInterface.Value)
+		if receivedCreatePDR.PDI.SourceInterface.Value != createPDR.PDI.SourceInterface.Value {
+			t.Errorf("PFCP Session Establishment Request handler was called with wrong CreatePDR PDI SourceInterface Value.\n- Sent CreatePDR PDI SourceInterface Value: %v\n- Received CreatePDR PDI SourceInterface Value %v\n", createPDR.PDI.SourceInterface.Value, receivedCreatePDR.PDI.SourceInterface.Value)
+		}
 	}
 
-	if pfcpSessionEstablishmentRequestReceivedCreateFAR.FARID != createFAR.FARID {
-		t.Errorf("PFCP Session Establishment Request handler was called with wrong CreateFAR FARID.\n- Sent CreateFAR FARID: %v\n- Received CreateFAR FARID %v\n", createFAR.FARID, pfcpSessionEstablishmentRequestReceivedCreateFAR.FARID)
+	if len(pfcpSessionEstablishmentRequestReceivedCreateFAR) != len(createFARs) {
+		t.Fatalf("PFCP Session Establishment Request handler was called with wrong number of CreateFAR.\n- Sent: %v\n- Received %v\n", len(createFARs), len(pfcpSessionEstablishmentRequestReceivedCreateFAR))
 	}
 
-	if pfcpSessionEstablishmentRequestReceivedCreateFAR.ApplyAction.FORW != createFAR.ApplyAction.FORW {
-		t.Errorf("PFCP Session Establishment Request handler was called with wrong CreateFAR ApplyAction Forw.\n- Sent CreateFAR ApplyAction Forw: %v\n- Received CreateFAR ApplyAction Forw %v\n", createFAR.ApplyAction.FORW, pfcpSessionEstablishmentRequestReceivedCreateFAR.ApplyAction.FORW)
+	for i, createFAR := range createFARs {
+		receivedCreateFAR := pfcpSessionEstablishmentRequestReceivedCreateFAR[i]
+		if receivedCreateFAR.FARID != createFAR.FARID {
+			t.Errorf("PFCP Session Establishment Request handler was called with wrong CreateFAR FARID.\n- Sent CreateFAR FARID: %v\n- Received CreateFAR FARID %v\n", createFAR.FARID, receivedCreateFAR.FARID)
+		}
+
+		if receivedCreateFAR.ApplyAction != createFAR.ApplyAction {
+			t.Errorf("PFCP Session Establishment Request handler was called with wrong CreateFAR ApplyAction.\n- Sent CreateFAR ApplyAction: %v\n- Received CreateFAR ApplyAction %v\n", createFAR.ApplyAction, receivedCreateFAR.ApplyAction)
+		}
 	}
 
 	pfcpSessionEstablishmentRequestMu.Unlock()
@@ -238,7 +218,7 @@ func PFCPSessionEstablishmentResponse(t *testing.T) {
 	pfcpServer.PFCPSessionEstablishmentResponse(HandlePFCPSessionEstablishmentResponse)
 
 	go func() {
-		err := pfcpServer.Run()
+		err := pfcpServer.Run(context.Background())
 		if err != nil {
 			t.Errorf("Expected no error to be returned")
 		}
@@ -246,7 +226,7 @@ func PFCPSessionEstablishmentResponse(t *testing.T) {
 
 	defer pfcpServer.Close()
 
-	time.Sleep(time.Second)
+	<-pfcpServer.Ready()
 	pfcpClient := client.New("127.0.0.1:8805")
 
 	nodeID, err := ie.NewNodeID("")
@@ -259,14 +239,51 @@ func PFCPSessionEstablishmentResponse(t *testing.T) {
 		t.Fatalf("Error creating Cause: %v", err)
 	}
 
-	PFCPSessionEstablishmentResponseMsg := messages.PFCPSessionEstablishmentResponse{
-		NodeID: nodeID,
-		Cause:  cause,
-	}
-
 	sequenceNumber := uint32(32)
 	seid := uint64(1234567890)
 
+	upFSEID, err := ie.NewFSEID(uint64(987654321), "2.3.4.5", "")
+	if err != nil {
+		t.Fatalf("Error creating UP F-SEID: %v", err)
+	}
+
+	uplinkPDRID, err := ie.NewPDRID(1)
+	if err != nil {
+		t.Fatalf("Error creating PDR ID: %v", err)
+	}
+
+	localFTEID, err := ie.NewFTEID(uint32(0x1000), "2.3.4.5", "")
+	if err != nil {
+		t.Fatalf("Error creating Local F-TEID: %v", err)
+	}
+
+	uplinkCreatedPDR, err := ie.NewCreatedPDR(uplinkPDRID, &localFTEID, nil)
+	if err != nil {
+		t.Fatalf("Error creating Created PDR: %v", err)
+	}
+
+	downlinkPDRID, err := ie.NewPDRID(2)
+	if err != nil {
+		t.Fatalf("Error creating PDR ID: %v", err)
+	}
+
+	ueIPAddress, err := ie.NewUEIPAddress("10.0.0.2", "", ie.SourceDestination{}, 0, 0, false, false)
+	if err != nil {
+		t.Fatalf("Error creating UE IP Address: %v", err)
+	}
+
+	downlinkCreatedPDR, err := ie.NewCreatedPDR(downlinkPDRID, nil, &ueIPAddress)
+	if err != nil {
+		t.Fatalf("Error creating Created PDR: %v", err)
+	}
+
+	PFCPSessionEstablishmentResponseMsg := messages.PFCPSessionEstablishmentResponse{
+		NodeID:     nodeID,
+		Cause:      cause,
+		UPFSEID:    &upFSEID,
+		CreatedPDR: []ie.CreatedPDR{uplinkCreatedPDR, downlinkCreatedPDR},
+	}
+
 	err = pfcpClient.SendPFCPSessionEstablishmentResponse(PFCPSessionEstablishmentResponseMsg, seid, sequenceNumber)
 	if err != nil {
 		t.Fatalf("Error sending PFCP Session Establishment Response: %v", err)
@@ -302,5 +319,161 @@ func PFCPSessionEstablishmentResponse(t *testing.T) {
 		t.Errorf("PFCP Session Establishment Response handler was called with wrong cause value.\n- Sent cause value: %v\n- Received cause value %v\n", cause.Value, pfcpSessionEstablishmentResponseReceivedCause.Value)
 	}
 
+	if pfcpSessionEstablishmentResponseReceivedOffendingIE != nil {
+		t.Errorf("PFCP Session Establishment Response handler was called with unexpected Offending IE: %v", pfcpSessionEstablishmentResponseReceivedOffendingIE)
+	}
+
+	if pfcpSessionEstablishmentResponseReceivedFailedRuleID != nil {
+		t.Errorf("PFCP Session Establishment Response handler was called with unexpected Failed Rule ID: %v", pfcpSessionEstablishmentResponseReceivedFailedRuleID)
+	}
+
+	if pfcpSessionEstablishmentResponseReceivedUPFSEID == nil || pfcpSessionEstablishmentResponseReceivedUPFSEID.SEID != upFSEID.SEID {
+		t.Errorf("PFCP Session Establishment Response handler was called with wrong UP F-SEID.\n- Sent UP F-SEID: %v\n- Received UP F-SEID %v\n", upFSEID, pfcpSessionEstablishmentResponseReceivedUPFSEID)
+	}
+
+	if len(pfcpSessionEstablishmentResponseReceivedCreatedPDR) != 2 {
+		t.Fatalf("PFCP Session Establishment Response handler was called with wrong number of Created PDR: %d", len(pfcpSessionEstablishmentResponseReceivedCreatedPDR))
+	}
+
+	receivedUplinkCreatedPDR := pfcpSessionEstablishmentResponseReceivedCreatedPDR[0]
+	if receivedUplinkCreatedPDR.PDRID != uplinkPDRID || receivedUplinkCreatedPDR.LocalFTEID == nil || receivedUplinkCreatedPDR.LocalFTEID.TEID != localFTEID.TEID {
+		t.Errorf("PFCP Session Establishment Response handler was called with wrong Created PDR.\n- Sent Created PDR: %v\n- Received Created PDR %v\n", uplinkCreatedPDR, receivedUplinkCreatedPDR)
+	}
+
+	receivedDownlinkCreatedPDR := pfcpSessionEstablishmentResponseReceivedCreatedPDR[1]
+	if receivedDownlinkCreatedPDR.PDRID != downlinkPDRID || receivedDownlinkCreatedPDR.UEIPAddress == nil || !receivedDownlinkCreatedPDR.UEIPAddress.V4 {
+		t.Errorf("PFCP Session Establishment Response handler was called with wrong Created PDR.\n- Sent Created PDR: %v\n- Received Created PDR %v\n", downlinkCreatedPDR, receivedDownlinkCreatedPDR)
+	}
+
 	pfcpSessionEstablishmentResponseMu.Unlock()
 }
+
+func newCreatePDR(t *testing.T, ruleID uint16, sourceInterfaceValue int) ie.CreatePDR {
+	pdrID, err := ie.NewPDRID(ruleID)
+	if err != nil {
+		t.Fatalf("Error creating PDR ID: %v", err)
+	}
+
+	precedence, err := ie.NewPrecedence(uint32(ruleID))
+	if err != nil {
+		t.Fatalf("Error creating Precedence: %v", err)
+	}
+
+	sourceInterface, err := ie.NewSourceInterface(sourceInterfaceValue)
+	if err != nil {
+		t.Fatalf("Error creating SourceInterface: %v", err)
+	}
+
+	sd := ie.SourceDestination{}
+	prefixLength := uint8(32)
+	ueIPAddress, err := ie.NewUEIPAddress("", "", sd, 0, prefixLength, false, true)
+	if err != nil {
+		t.Fatalf("Error creating UEIPAddress: %v", err)
+	}
+
+	pdi, err := ie.NewPDI(sourceInterface, &ueIPAddress)
+	if err != nil {
+		t.Fatalf("Error creating PDI: %v", err)
+	}
+
+	createPDR, err := ie.NewCreatePDR(pdrID, precedence, pdi)
+	if err != nil {
+		t.Fatalf("Error creating CreatePDR: %v", err)
+	}
+
+	return createPDR
+}
+
+func newCreateFAR(t *testing.T, id uint32, flag ie.ApplyActionFlag) ie.CreateFAR {
+	farID, err := ie.NewFarID(id)
+	if err != nil {
+		t.Fatalf("Error creating FarID: %v", err)
+	}
+
+	applyAction, err := ie.NewApplyAction(flag, []ie.ApplyActionExtraFlag{})
+	if err != nil {
+		t.Fatalf("Error creating ApplyAction: %v", err)
+	}
+
+	createFAR, err := ie.NewCreateFAR(farID, applyAction)
+	if err != nil {
+		t.Fatalf("Error creating CreateFAR: %v", err)
+	}
+
+	return createFAR
+}
+
+func PFCPSessionEstablishmentRequestWithManyRules(t *testing.T) {
+	pfcpServer := server.New("127.0.0.1:8805")
+	pfcpServer.PFCPSessionEstablishmentRequest(HandlePFCPSessionEstablishmentRequest)
+
+	go pfcpServer.Run(context.Background())
+
+	defer pfcpServer.Close()
+
+	<-pfcpServer.Ready()
+	pfcpClient := client.New("127.0.0.1:8805")
+
+	nodeID, err := ie.NewNodeID("12.23.34.45")
+	if err != nil {
+		t.Fatalf("Error creating Node ID: %v", err)
+	}
+
+	seid := uint64(1234567891)
+
+	fseid, err := ie.NewFSEID(seid, "1.2.3.4", "")
+	if err != nil {
+		t.Fatalf("Error creating FSEID: %v", err)
+	}
+
+	numberOfRules := 200
+	var createPDRs []ie.CreatePDR
+	var createFARs []ie.CreateFAR
+	for i := 1; i <= numberOfRules; i++ {
+		createPDRs = append(createPDRs, newCreatePDR(t, uint16(i), i%2))
+		createFARs = append(createFARs, newCreateFAR(t, uint32(i), ie.FORW))
+	}
+
+	PFCPSessionEstablishmentRequestMsg := messages.PFCPSessionEstablishmentRequest{
+		NodeID:    nodeID,
+		CPFSEID:   fseid,
+		CreatePDR: createPDRs,
+		CreateFAR: createFARs,
+	}
+	sequenceNumber := uint32(33)
+
+	messageSize := len(messages.Serialize(PFCPSessionEstablishmentRequestMsg, messages.NewSessionHeader(messages.PFCPSessionEstablishmentRequestMessageType, seid, sequenceNumber)))
+	if messageSize < 8*1024 {
+		t.Fatalf("Expected a message of several kilobytes, got %d bytes", messageSize)
+	}
+
+	err = pfcpClient.SendPFCPSessionEstablishmentRequest(PFCPSessionEstablishmentRequestMsg, seid, sequenceNumber)
+	if err != nil {
+		t.Fatalf("Error sending PFCP Session Establishment Request: %v", err)
+	}
+
+	time.Sleep(time.Second)
+
+	pfcpSessionEstablishmentRequestMu.Lock()
+	defer pfcpSessionEstablishmentRequestMu.Unlock()
+	if !pfcpSessionEstablishmentRequesthandlerCalled {
+		t.Fatalf("PFCP Session Establishment Request handler was not called")
+	}
+
+	if pfcpSessionEstablishmentRequestReceivedSequenceNumber != sequenceNumber {
+		t.Fatalf("PFCP Session Establishment Request handler was called with wrong sequence number.\n- Sent sequence number: %v\n- Received sequence number %v\n", sequenceNumber, pfcpSessionEstablishmentRequestReceivedSequenceNumber)
+	}
+
+	if len(pfcpSessionEstablishmentRequestReceivedCreatePDR) != numberOfRules {
+		t.Errorf("PFCP Session Establishment Request handler was called with wrong number of Create PDR.\n- Sent: %v\n- Received %v\n", numberOfRules, len(pfcpSessionEstablishmentRequestReceivedCreatePDR))
+	}
+
+	if len(pfcpSessionEstablishmentRequestReceivedCreateFAR) != numberOfRules {
+		t.Errorf("PFCP Session Establishment Request handler was called with wrong number of Create FAR.\n- Sent: %v\n- Received %v\n", numberOfRules, len(pfcpSessionEstablishmentRequestReceivedCreateFAR))
+	}
+
+	lastPDR := pfcpSessionEstablishmentRequestReceivedCreatePDR[len(pfcpSessionEstablishmentRequestReceivedCreatePDR)-1]
+	if lastPDR.PDRID.RuleID != uint16(numberOfRules) {
+		t.Errorf("PFCP Session Establishment Request handler was called with wrong last PDR ID.\n- Sent: %v\n- Received %v\n", numberOfRules, lastPDR.PDRID.RuleID)
+	}
+}
@@ -0,0 +1,256 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dot-5g/pfcp/client"
+	"github.com/dot-5g/pfcp/ie"
+	"github.com/dot-5g/pfcp/messages"
+	"github.com/dot-5g/pfcp/server"
+)
+
+var (
+	pfcpSessionModificationRequestMu                     sync.Mutex
+	pfcpSessionModificationRequesthandlerCalled          bool
+	pfcpSessionModificationRequestReceivedSequenceNumber uint32
+	pfcpSessionModificationRequestReceivedSEID           uint64
+	pfcpSessionModificationRequestReceivedMsg            messages.PFCPSessionModificationRequest
+)
+
+var (
+	pfcpSessionModificationResponseMu                     sync.Mutex
+	pfcpSessionModificationResponsehandlerCalled          bool
+	pfcpSessionModificationResponseReceivedSequenceNumber uint32
+	pfcpSessionModificationResponseReceivedSEID           uint64
+	pfcpSessionModificationResponseReceivedCause          ie.Cause
+)
+
+func HandlePFCPSessionModificationRequest(client *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionModificationRequest) {
+	pfcpSessionModificationRequestMu.Lock()
+	defer pfcpSessionModificationRequestMu.Unlock()
+	pfcpSessionModificationRequesthandlerCalled = true
+	pfcpSessionModificationRequestReceivedSequenceNumber = sequenceNumber
+	pfcpSessionModificationRequestReceivedSEID = seid
+	pfcpSessionModificationRequestReceivedMsg = msg
+}
+
+func HandlePFCPSessionModificationResponse(client *client.PFCP, sequenceNumber uint32, seid uint64, msg messages.PFCPSessionModificationResponse) {
+	pfcpSessionModificationResponseMu.Lock()
+	defer pfcpSessionModificationResponseMu.Unlock()
+	pfcpSessionModificationResponsehandlerCalled = true
+	pfcpSessionModificationResponseReceivedSequenceNumber = sequenceNumber
+	pfcpSessionModificationResponseReceivedSEID = seid
+	pfcpSessionModificationResponseReceivedCause = msg.Cause
+}
+
+func TestPFCPSessionModification(t *testing.T) {
+	t.Run("TestPFCPSessionModificationRequest", PFCPSessionModificationRequest)
+	t.Run("TestPFCPSessionModificationResponse", PFCPSessionModificationResponse)
+}
+
+func PFCPSessionModificationRequest(t *testing.T) {
+	pfcpServer := server.New("127.0.0.1:8805")
+	pfcpServer.PFCPSessionModificationRequest(HandlePFCPSessionModificationRequest)
+
+	go func() {
+		err := pfcpServer.Run(context.Background())
+		if err != nil {
+			t.Errorf("Expected no error to be returned")
+		}
+	}()
+
+	defer pfcpServer.Close()
+
+	<-pfcpServer.Ready()
+	pfcpClient := client.New("127.0.0.1:8805")
+
+	removePDRID, err := ie.NewPDRID(1)
+	if err != nil {
+		t.Fatalf("Error creating PDR ID: %v", err)
+	}
+
+	removePDR, err := ie.NewRemovePDR(removePDRID)
+	if err != nil {
+		t.Fatalf("Error creating RemovePDR: %v", err)
+	}
+
+	farID, err := ie.NewFarID(2)
+	if err != nil {
+		t.Fatalf("Error creating FAR ID: %v", err)
+	}
+
+	applyAction, err := ie.NewApplyAction(ie.DROP, []ie.ApplyActionExtraFlag{})
+	if err != nil {
+		t.Fatalf("Error creating ApplyAction: %v", err)
+	}
+
+	updateFAR, err := ie.NewUpdateFAR(farID, &applyAction)
+	if err != nil {
+		t.Fatalf("Error creating UpdateFAR: %v", err)
+	}
+
+	urrID, err := ie.NewURRID(3)
+	if err != nil {
+		t.Fatalf("Error creating URR ID: %v", err)
+	}
+
+	measurementMethod, err := ie.NewMeasurementMethod(false, true, false)
+	if err != nil {
+		t.Fatalf("Error creating MeasurementMethod: %v", err)
+	}
+
+	reportingTriggers, err := ie.NewReportingTriggers([]ie.ReportingTrigger{ie.VOLTH})
+	if err != nil {
+		t.Fatalf("Error creating ReportingTriggers: %v", err)
+	}
+
+	createURR, err := ie.NewCreateURR(urrID, measurementMethod, reportingTriggers)
+	if err != nil {
+		t.Fatalf("Error creating CreateURR: %v", err)
+	}
+
+	qerID, err := ie.NewQERID(4)
+	if err != nil {
+		t.Fatalf("Error creating QER ID: %v", err)
+	}
+
+	gateStatus, err := ie.NewGateStatus(ie.GateOpen, ie.GateOpen)
+	if err != nil {
+		t.Fatalf("Error creating GateStatus: %v", err)
+	}
+
+	createQER, err := ie.NewCreateQER(qerID, gateStatus)
+	if err != nil {
+		t.Fatalf("Error creating CreateQER: %v", err)
+	}
+
+	removeQER, err := ie.NewRemoveQER(qerID)
+	if err != nil {
+		t.Fatalf("Error creating RemoveQER: %v", err)
+	}
+
+	PFCPSessionModificationRequestMsg := messages.PFCPSessionModificationRequest{
+		RemovePDR: []ie.RemovePDR{removePDR},
+		RemoveQER: []ie.RemoveQER{removeQER},
+		CreateURR: []ie.CreateURR{createURR},
+		CreateQER: []ie.CreateQER{createQER},
+		UpdateFAR: []ie.UpdateFAR{updateFAR},
+	}
+	seid := uint64(1234567890)
+	sequenceNumber := uint32(32)
+
+	err = pfcpClient.SendPFCPSessionModificationRequest(PFCPSessionModificationRequestMsg, seid, sequenceNumber)
+	if err != nil {
+		t.Fatalf("Error sending PFCP Session Modification Request: %v", err)
+	}
+
+	time.Sleep(time.Second)
+
+	pfcpSessionModificationRequestMu.Lock()
+	defer pfcpSessionModificationRequestMu.Unlock()
+	if !pfcpSessionModificationRequesthandlerCalled {
+		t.Fatalf("PFCP Session Modification Request handler was not called")
+	}
+
+	if pfcpSessionModificationRequestReceivedSequenceNumber != sequenceNumber {
+		t.Errorf("PFCP Session Modification Request handler was called with wrong sequence number.\n- Sent sequence number: %v\n- Received sequence number %v\n", sequenceNumber, pfcpSessionModificationRequestReceivedSequenceNumber)
+	}
+
+	if pfcpSessionModificationRequestReceivedSEID != seid {
+		t.Errorf("PFCP Session Modification Request handler was called with wrong SEID.\n- Sent SEID: %v\n- Received SEID %v\n", seid, pfcpSessionModificationRequestReceivedSEID)
+	}
+
+	receivedMsg := pfcpSessionModificationRequestReceivedMsg
+
+	if receivedMsg.CPFSEID != nil {
+		t.Errorf("PFCP Session Modification Request handler was called with unexpected CP F-SEID: %v", receivedMsg.CPFSEID)
+	}
+
+	if len(receivedMsg.RemovePDR) != 1 || receivedMsg.RemovePDR[0] != removePDR {
+		t.Errorf("PFCP Session Modification Request handler was called with wrong Remove PDR.\n- Sent Remove PDR: %v\n- Received Remove PDR %v\n", removePDR, receivedMsg.RemovePDR)
+	}
+
+	if len(receivedMsg.RemoveQER) != 1 || receivedMsg.RemoveQER[0] != removeQER {
+		t.Errorf("PFCP Session Modification Request handler was called with wrong Remove QER.\n- Sent Remove QER: %v\n- Received Remove QER %v\n", removeQER, receivedMsg.RemoveQER)
+	}
+
+	if len(receivedMsg.CreateURR) != 1 || receivedMsg.CreateURR[0].URRID != urrID || receivedMsg.CreateURR[0].MeasurementMethod != measurementMethod {
+		t.Errorf("PFCP Session Modification Request handler was called with wrong Create URR.\n- Sent Create URR: %v\n- Received Create URR %v\n", createURR, receivedMsg.CreateURR)
+	}
+
+	if len(receivedMsg.CreateQER) != 1 || receivedMsg.CreateQER[0] != createQER {
+		t.Errorf("PFCP Session Modification Request handler was called with wrong Create QER.\n- Sent Create QER: %v\n- Received Create QER %v\n", createQER, receivedMsg.CreateQER)
+	}
+
+	if len(receivedMsg.UpdateFAR) != 1 {
+		t.Fatalf("PFCP Session Modification Request handler was called with wrong number of Update FAR: %d", len(receivedMsg.UpdateFAR))
+	}
+
+	if receivedMsg.UpdateFAR[0].FARID != farID {
+		t.Errorf("PFCP Session Modification Request handler was called with wrong Update FAR FAR ID.\n- Sent FAR ID: %v\n- Received FAR ID %v\n", farID, receivedMsg.UpdateFAR[0].FARID)
+	}
+
+	if receivedMsg.UpdateFAR[0].ApplyAction == nil || *receivedMsg.UpdateFAR[0].ApplyAction != applyAction {
+		t.Errorf("PFCP Session Modification Request handler was called with wrong Update FAR Apply Action.\n- Sent Apply Action: %v\n- Received Apply Action %v\n", applyAction, receivedMsg.UpdateFAR[0].ApplyAction)
+	}
+
+	if len(receivedMsg.CreatePDR) != 0 || len(receivedMsg.UpdatePDR) != 0 || len(receivedMsg.RemoveFAR) != 0 {
+		t.Errorf("PFCP Session Modification Request handler was called with unexpected rules: %v", receivedMsg)
+	}
+}
+
+func PFCPSessionModificationResponse(t *testing.T) {
+	pfcpServer := server.New("127.0.0.1:8805")
+	pfcpServer.PFCPSessionModificationResponse(HandlePFCPSessionModificationResponse)
+
+	go func() {
+		err := pfcpServer.Run(context.Background())
+		if err != nil {
+			t.Errorf("Expected no error to be returned")
+		}
+	}()
+
+	defer pfcpServer.Close()
+
+	<-pfcpServer.Ready()
+	pfcpClient := client.New("127.0.0.1:8805")
+
+	cause, err := ie.NewCause(ie.RequestAccepted)
+	if err != nil {
+		t.Fatalf("Error creating Cause: %v", err)
+	}
+
+	PFCPSessionModificationResponseMsg := messages.PFCPSessionModificationResponse{
+		Cause: cause,
+	}
+	seid := uint64(1234567890)
+	sequenceNumber := uint32(31232)
+
+	err = pfcpClient.SendPFCPSessionModificationResponse(PFCPSessionModificationResponseMsg, seid, sequenceNumber)
+	if err != nil {
+		t.Fatalf("Error sending PFCP Session Modification Response: %v", err)
+	}
+
+	time.Sleep(time.Second)
+
+	pfcpSessionModificationResponseMu.Lock()
+	defer pfcpSessionModificationResponseMu.Unlock()
+	if !pfcpSessionModificationResponsehandlerCalled {
+		t.Fatalf("PFCP Session Modification Response handler was not called")
+	}
+
+	if pfcpSessionModificationResponseReceivedSequenceNumber != sequenceNumber {
+		t.Errorf("PFCP Session Modification Response handler was called with wrong sequence number.\n- Sent sequence number: %v\n- Received sequence number %v\n", sequenceNumber, pfcpSessionModificationResponseReceivedSequenceNumber)
+	}
+
+	if pfcpSessionModificationResponseReceivedSEID != seid {
+		t.Errorf("PFCP Session Modification Response handler was called with wrong SEID.\n- Sent SEID: %v\n- Received SEID %v\n", seid, pfcpSessionModificationResponseReceivedSEID)
+	}
+
+	if pfcpSessionModificationResponseReceivedCause != cause {
+		t.Errorf("PFCP Session Modification Response handler was called with wrong cause.\n- Sent cause: %v\n- Received cause %v\n", cause, pfcpSessionModificationResponseReceivedCause)
+	}
+}
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -55,7 +56,7 @@ func PFCPSessionReportRequest(t *testing.T) {
 	pfcpServer.PFCPSessionReportRequest(HandlePFCPSessionReportRequest)
 
 	go func() {
-		err := pfcpServer.Run()
+		err := pfcpServer.Run(context.Background())
 		if err != nil {
 			t.Errorf("Expected no error to be returned")
 		}
@@ -63,7 +64,7 @@ func PFCPSessionReportRequest(t *testing.T) {
 
 	defer pfcpServer.Close()
 
-	time.Sleep(time.Second)
+	<-pfcpServer.Ready()
 	pfcpClient := client.New("127.0.0.1:8805")
 
 	reportType, err := ie.NewReportType([]ie.Report{ie.UISR, ie.SESR})
@@ -113,11 +114,11 @@ func PFCPSessionReportResponse(t *testing.T) {
 	pfcpServer := server.New("127.0.0.1:8805")
 	pfcpServer.PFCPSessionReportResponse(HandlePFCPSessionReportResponse)
 
-	go pfcpServer.Run()
+	go pfcpServer.Run(context.Background())
 
 	defer pfcpServer.Close()
 
-	time.Sleep(time.Second)
+	<-pfcpServer.Ready()
 	pfcpClient := client.New("127.0.0.1:8805")
 
 	cause, err := ie.NewCause(ie.RequestAccepted)